@@ -57,3 +57,100 @@ func Benchmark_runeSet6_containsMBRune(b *testing.B) {
 		_ = rs.containsMBRune(searchRune)
 	}
 }
+
+// benchRuneSetInput returns a buffer of n bytes that contains a control rune
+// only at the very end so the full buffer must be scanned.
+func benchRuneSetInput(n int, filler string, end byte) []byte {
+	p := make([]byte, 0, n)
+	for len(p) < n-1 {
+		p = append(p, filler...)
+	}
+	p = p[:n-1]
+	return append(p, end)
+}
+
+func benchmarkReaderRuneSet(mb bool) runeSet6 {
+	var rs runeSet6
+	rs.addRuneUniqueUnchecked(',')
+	rs.addRuneUniqueUnchecked('"')
+	rs.addByte(asciiCarriageReturn)
+	rs.addByte(asciiLineFeed)
+	if mb {
+		rs.addMBRune(utf8NextLine)
+		rs.addMBRune(utf8LineSeparator)
+	}
+	return rs
+}
+
+func Benchmark_runeSet6_indexAnyRuneLenInBytes_ASCII(b *testing.B) {
+	b.ReportAllocs()
+
+	rs := benchmarkReaderRuneSet(false)
+	p := benchRuneSetInput(4096, "lorem ipsum dolor sit amet ", '\n')
+
+	b.SetBytes(int64(len(p)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = rs.indexAnyRuneLenInBytes(p)
+	}
+}
+
+func Benchmark_runeSet6_indexAnyRuneLenInBytes_ASCIIWithMBRunes(b *testing.B) {
+	b.ReportAllocs()
+
+	rs := benchmarkReaderRuneSet(true)
+	p := benchRuneSetInput(4096, "lorem ipsum dolor sit amet ", '\n')
+
+	b.SetBytes(int64(len(p)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = rs.indexAnyRuneLenInBytes(p)
+	}
+}
+
+func Benchmark_runeSet6_indexAnyRuneLenInBytes_UTF8WithMBRunes(b *testing.B) {
+	b.ReportAllocs()
+
+	rs := benchmarkReaderRuneSet(true)
+	p := benchRuneSetInput(4096, "lörem ípsüm dólor sit ämet ", '\n')
+
+	b.SetBytes(int64(len(p)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = rs.indexAnyRuneLenInBytes(p)
+	}
+}
+
+func Benchmark_runeSet6_indexAnyRuneLenInBytes_ShortFields(b *testing.B) {
+	b.ReportAllocs()
+
+	rs := benchmarkReaderRuneSet(false)
+	p := []byte("12,abc,4.5,x\n")
+
+	b.SetBytes(int64(len(p)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(p); {
+			_, size, di := rs.indexAnyRuneLenInBytes(p[j:])
+			j += di + int(size)
+		}
+	}
+}
+
+func Benchmark_runeSet4_indexAnyInString_ASCII(b *testing.B) {
+	b.ReportAllocs()
+
+	var rs runeSet4
+	rs.addRuneUniqueUnchecked(',')
+	rs.addRuneUniqueUnchecked('"')
+	rs.addByte(asciiCarriageReturn)
+	rs.addByte(asciiLineFeed)
+
+	s := string(benchRuneSetInput(4096, "lorem ipsum dolor sit amet ", '"'))
+
+	b.SetBytes(int64(len(s)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rs.indexAnyInString(s)
+	}
+}
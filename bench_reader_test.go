@@ -611,3 +611,156 @@ const fileContents3c256Rows = `a,b,c
 255,odd,r255
 256,even,r256
 `
+
+func BenchmarkReadPostInit256RowsSelectColumns(b *testing.B) {
+	b.ReportAllocs()
+	b.StopTimer()
+
+	strReader := strings.NewReader("")
+	opts := csv.ReaderOpts()
+
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		strReader.Reset(fileContents3c256Rows)
+		cr, err := csv.NewReader(
+			opts.Reader(strReader),
+			opts.SelectColumns(1),
+		)
+		if err != nil {
+			panic(err)
+		}
+		// defer cr.Close() // for the sake of the benchmark, calling explicitly at the end of the loop
+		b.StartTimer()
+
+		for cr.Scan() {
+			_ = cr.Row()
+		}
+		if err := cr.Err(); err != nil {
+			panic(err)
+		}
+
+		_ = cr.Close()
+	}
+}
+
+func BenchmarkReadPostInit256RowsBytesReader(b *testing.B) {
+	b.ReportAllocs()
+	b.StopTimer()
+
+	data := []byte(fileContents3c256Rows)
+
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		cr, err := csv.NewBytesReader(data)
+		if err != nil {
+			panic(err)
+		}
+		// defer cr.Close() // for the sake of the benchmark, calling explicitly at the end of the loop
+		b.StartTimer()
+
+		for cr.Scan() {
+			_ = cr.Row()
+		}
+		if err := cr.Err(); err != nil {
+			panic(err)
+		}
+
+		_ = cr.Close()
+	}
+}
+
+func BenchmarkReadPostInit256RowsBytesReaderBorrowRow(b *testing.B) {
+	b.ReportAllocs()
+	b.StopTimer()
+
+	data := []byte(fileContents3c256Rows)
+
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		cr, err := csv.NewBytesReader(data, csv.ReaderOpts().BorrowRow(true))
+		if err != nil {
+			panic(err)
+		}
+		// defer cr.Close() // for the sake of the benchmark, calling explicitly at the end of the loop
+		b.StartTimer()
+
+		for cr.Scan() {
+			_ = cr.Row()
+		}
+		if err := cr.Err(); err != nil {
+			panic(err)
+		}
+
+		_ = cr.Close()
+	}
+}
+
+func BenchmarkReadSmallDocsNewReader(b *testing.B) {
+	b.ReportAllocs()
+
+	strReader := strings.NewReader("")
+	opts := csv.ReaderOpts()
+
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strReader.Reset("a,b,c\n1,2,3\n")
+		cr, err := csv.NewReader(
+			opts.Reader(strReader),
+			opts.BorrowRow(true),
+			opts.BorrowFields(true),
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		for cr.Scan() {
+			_ = cr.Row()
+		}
+		if err := cr.Err(); err != nil {
+			panic(err)
+		}
+
+		_ = cr.Close()
+	}
+}
+
+func BenchmarkReadSmallDocsReset(b *testing.B) {
+	b.ReportAllocs()
+
+	strReader := strings.NewReader("")
+	opts := csv.ReaderOpts()
+
+	cr, err := csv.NewReader(
+		opts.Reader(strReader),
+		opts.BorrowRow(true),
+		opts.BorrowFields(true),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	runtime.GC()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strReader.Reset("a,b,c\n1,2,3\n")
+		if err := cr.(csv.ExtendedReader).Reset(strReader); err != nil {
+			panic(err)
+		}
+
+		for cr.Scan() {
+			_ = cr.Row()
+		}
+		if err := cr.Err(); err != nil {
+			panic(err)
+		}
+
+		_ = cr.Close()
+	}
+}
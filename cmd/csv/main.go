@@ -0,0 +1,80 @@
+// Command csv provides utilities for processing large CSV documents.
+//
+// Usage:
+//
+//	csv <command> [flags] [file]
+//
+// The commands are:
+//
+//	sort    sort records by one or more columns
+//
+// Run "csv <command> -h" for the flags of a command. When no file is given
+// the document is read from standard input.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"sort", "sort records by one or more columns", runSort},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: csv <command> [flags] [file]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s%s\n", c.name, c.usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+
+		if err := c.run(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "csv "+c.name+": "+err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage(os.Stderr)
+	os.Exit(2)
+}
+
+// openInput returns the named file, or stdin when no file is given.
+func openInput(args []string, stdin io.Reader) (io.Reader, func() error, error) {
+	switch len(args) {
+	case 0:
+		return stdin, func() error { return nil }, nil
+	case 1:
+		if args[0] == "-" {
+			return stdin, func() error { return nil }, nil
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.Close, nil
+	}
+
+	return nil, nil, fmt.Errorf("expected at most one file, got %d", len(args))
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+)
+
+// sortKeysFlag collects repeated -k flags.
+type sortKeysFlag []csv.SortKey
+
+func (f *sortKeysFlag) String() string {
+	return ""
+}
+
+// Set parses a key of the form column[:modifier]... where column is a
+// header name, or a one based column number when there is no header, and
+// each modifier is one of n (numeric), t[=layout] (time), or r (reverse).
+func (f *sortKeysFlag) Set(s string) error {
+	parts := strings.Split(s, ":")
+
+	var k csv.SortKey
+	if parts[0] == "" {
+		return errors.New("empty sort key column")
+	}
+	k.Column = parts[0]
+
+	for _, m := range parts[1:] {
+		switch {
+		case m == "n":
+			k.Type = csv.SortNumeric
+		case m == "t":
+			k.Type = csv.SortTime
+		case strings.HasPrefix(m, "t="):
+			k.Type = csv.SortTime
+			k.Layout = m[2:]
+		case m == "r":
+			k.Descending = true
+		default:
+			return fmt.Errorf("unknown sort key modifier %q", m)
+		}
+	}
+
+	*f = append(*f, k)
+	return nil
+}
+
+// runeFlag parses a flag value which must be a single rune.
+type runeFlag rune
+
+func (f *runeFlag) String() string {
+	return string(rune(*f))
+}
+
+func (f *runeFlag) Set(s string) error {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 || n != len(s) || r == utf8.RuneError {
+		return errors.New("must be a single character")
+	}
+	*f = runeFlag(r)
+	return nil
+}
+
+func runSort(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("sort", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: csv sort -k column[:n|:t[=layout]][:r] [flags] [file]")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+
+	var keys sortKeysFlag
+	fieldSep := runeFlag(',')
+	quote := runeFlag('"')
+	fs.Var(&keys, "k", "sort key; repeat to break ties with further keys.\nmodifiers: n numeric, t time (RFC3339 unless t=layout), r reverse")
+	fs.Var(&fieldSep, "d", "field separator")
+	fs.Var(&quote, "q", "quote character")
+	header := fs.Bool("header", true, "treat the first record as a header row which is kept first")
+	stable := fs.Bool("stable", false, "keep records with equal keys in input order")
+	memory := fs.Int64("mem", 0, "approximate bytes of records to hold in memory before spilling to disk (default 64MiB)")
+	fanIn := fs.Int("fanin", 0, "maximum number of temporary files merged at once (default 64)")
+	tempDir := fs.String("tmp", "", "directory for temporary files (default os.TempDir())")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return errors.New("at least one -k sort key is required")
+	}
+
+	if !*header {
+		// without a header columns are one based numbers
+		for i := range keys {
+			n, err := strconv.Atoi(keys[i].Column)
+			if err != nil || n < 1 {
+				return fmt.Errorf("sort key column %q must be a number greater than zero when -header=false", keys[i].Column)
+			}
+			keys[i].Column = ""
+			keys[i].Index = n - 1
+		}
+	}
+
+	in, closeIn, err := openInput(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+	defer closeIn()
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(in),
+		csv.ReaderOpts().FieldSeparator(rune(fieldSep)),
+		csv.ReaderOpts().Quote(rune(quote)),
+		csv.ReaderOpts().RemoveByteOrderMarker(true),
+	)
+	if err != nil {
+		return err
+	}
+	defer cr.Close()
+
+	bw := bufio.NewWriter(stdout)
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(bw),
+		csv.WriterOpts().FieldSeparator(rune(fieldSep)),
+		csv.WriterOpts().Quote(rune(quote)),
+		csv.WriterOpts().ErrorOnNonUTF8(false),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := csv.Sort(cr, cw, keys, csv.SortOptions{
+		MemoryBudget:  *memory,
+		MaxMergeFanIn: *fanIn,
+		TempDir:       *tempDir,
+		Header:        *header,
+		Stable:        *stable,
+	}); err != nil {
+		return err
+	}
+
+	if err := cw.Close(); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalRunSort(t *testing.T) {
+	t.Parallel()
+
+	const doc = "name,qty\nb,10\na,9\nc,\na,10.5\n"
+
+	tcs := []struct {
+		when   string
+		args   []string
+		stdin  string
+		exp    string
+		errStr string
+	}{
+		{
+			when:  "sorting by a header column",
+			args:  []string{"-k", "name", "-stable"},
+			stdin: doc,
+			exp:   "name,qty\na,9\na,10.5\nb,10\nc,\n",
+		},
+		{
+			when:  "sorting by a reversed numeric column read from stdin by name",
+			args:  []string{"-k", "qty:n:r", "-"},
+			stdin: doc,
+			exp:   "name,qty\nc,\na,10.5\nb,10\na,9\n",
+		},
+		{
+			when:  "sorting without a header by column number",
+			args:  []string{"-header=false", "-d", ";", "-k", "2:n"},
+			stdin: "x;3\ny;1\nz;2\n",
+			exp:   "y;1\nz;2\nx;3\n",
+		},
+		{
+			when:  "sorting quoted fields",
+			args:  []string{"-k", "name", "-mem", "1"},
+			stdin: "name,note\n\"b,c\",\"say \"\"hi\"\"\"\na,\"x\ny\"\n",
+			exp:   "name,note\na,\"x\ny\"\n\"b,c\",\"say \"\"hi\"\"\"\n",
+		},
+		{
+			when:  "sorting fields quoted with a custom quote character",
+			args:  []string{"-k", "name", "-q", "'"},
+			stdin: "name,note\n'b,c',1\na,'x''y'\n",
+			exp:   "name,note\na,'x''y'\n'b,c',1\n",
+		},
+		{
+			when:   "no sort key is given",
+			stdin:  doc,
+			errStr: "at least one -k sort key is required",
+		},
+		{
+			when:   "a column is not a number without a header",
+			args:   []string{"-header=false", "-k", "name"},
+			stdin:  doc,
+			errStr: `sort key column "name" must be a number greater than zero when -header=false`,
+		},
+		{
+			when:   "a sort key modifier is unknown",
+			args:   []string{"-k", "name:x"},
+			stdin:  doc,
+			errStr: `invalid value "name:x" for flag -k: unknown sort key modifier "x"`,
+		},
+		{
+			when:   "the sort column is not in the header",
+			args:   []string{"-k", "missing"},
+			stdin:  doc,
+			errStr: "bad config\nsort key column \"missing\" not found",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var stdout bytes.Buffer
+			err := runSort(tc.args, strings.NewReader(tc.stdin), &stdout)
+			if tc.errStr != "" {
+				is.EqualError(err, tc.errStr)
+				return
+			}
+
+			is.Nil(err)
+			is.Equal(tc.exp, stdout.String())
+		})
+	}
+}
+
+func TestFunctionalRunSortSpill(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var in, exp strings.Builder
+	in.WriteString("i\n")
+	exp.WriteString("i\n")
+	for i := range 100 {
+		in.WriteString(strconv.Itoa(99-i) + "\n")
+		exp.WriteString(strconv.Itoa(i) + "\n")
+	}
+
+	dir := t.TempDir()
+
+	var stdout bytes.Buffer
+	err := runSort([]string{"-k", "i:n", "-mem", "256", "-fanin", "2", "-tmp", dir}, strings.NewReader(in.String()), &stdout)
+	is.Nil(err)
+	is.Equal(exp.String(), stdout.String())
+
+	entries, err := os.ReadDir(dir)
+	is.Nil(err)
+	is.Empty(entries)
+}
@@ -0,0 +1,125 @@
+package csvw
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dateFields pairs the date field patterns of CSVW formats with the
+// equivalent elements of Go time layouts. Longer patterns come first.
+var dateFields = []dateField{
+	{"yyyy", "2006", true},
+	{"yy", "06", true},
+	{"MM", "01", true},
+	{"M", "1", true},
+	{"dd", "02", true},
+	{"d", "2", true},
+	{"HH", "15", false},
+	{"mm", "04", false},
+	{"ss", "05", false},
+	{"XXX", "Z07:00", false},
+	{"XX", "Z0700", false},
+	{"X", "Z07", false},
+	{"xxx", "-07:00", false},
+	{"xx", "-0700", false},
+	{"x", "-07", false},
+}
+
+type dateField struct {
+	pattern, layout string
+	date            bool
+}
+
+// layoutFields are dateFields with longer layout elements first.
+var layoutFields = func() []dateField {
+	v := slices.Clone(dateFields)
+	slices.SortStableFunc(v, func(a, b dateField) int {
+		return len(b.layout) - len(a.layout)
+	})
+	return v
+}()
+
+// dateLiterals are the runes which may separate date fields.
+const dateLiterals = "-/:., T"
+
+// goLayout converts the date field pattern of a CSVW date or time format,
+// such as "dd/MM/yyyy HH:mm", into a Go time layout.
+func goLayout(format string) (string, error) {
+	var sb strings.Builder
+
+	for s := format; s != ""; {
+		if n := len(s) - len(strings.TrimLeft(s, "S")); n > 0 {
+			// fractional seconds
+			sb.WriteString(strings.Repeat("0", n))
+			s = s[n:]
+			continue
+		}
+
+		var ok bool
+		for _, f := range dateFields {
+			if strings.HasPrefix(s, f.pattern) {
+				sb.WriteString(f.layout)
+				s = s[len(f.pattern):]
+				ok = true
+				break
+			}
+		}
+		if ok {
+			continue
+		}
+
+		if !strings.ContainsRune(dateLiterals, rune(s[0])) {
+			return "", errors.Join(ErrUnsupported, errors.New("date format "+strconv.Quote(format)+" is not supported"))
+		}
+		sb.WriteByte(s[0])
+		s = s[1:]
+	}
+
+	return sb.String(), nil
+}
+
+// dateFormat converts a Go time layout into the date field pattern of a
+// CSVW date or time format. It also reports whether the layout has date
+// fields and whether it has time fields.
+func dateFormat(layout string) (format string, hasDate, hasTime bool, err error) {
+	var sb strings.Builder
+
+	for s := layout; s != ""; {
+		if strings.HasPrefix(s, ".0") || strings.HasPrefix(s, ",0") {
+			n := len(s) - 1 - len(strings.TrimLeft(s[1:], "0"))
+			sb.WriteByte(s[0])
+			sb.WriteString(strings.Repeat("S", n))
+			s = s[1+n:]
+			hasTime = true
+			continue
+		}
+
+		var ok bool
+		for _, f := range layoutFields {
+			if strings.HasPrefix(s, f.layout) {
+				sb.WriteString(f.pattern)
+				s = s[len(f.layout):]
+				if f.date {
+					hasDate = true
+				} else {
+					hasTime = true
+				}
+				ok = true
+				break
+			}
+		}
+		if ok {
+			continue
+		}
+
+		if !strings.ContainsRune(dateLiterals, rune(s[0])) {
+			return "", false, false, errors.Join(ErrUnsupported, errors.New("time layout "+strconv.Quote(layout)+" cannot be described"))
+		}
+		sb.WriteByte(s[0])
+		s = s[1:]
+	}
+
+	return sb.String(), hasDate, hasTime, nil
+}
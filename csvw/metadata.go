@@ -0,0 +1,262 @@
+// Package csvw reads and writes W3C CSV on the Web (CSVW) metadata
+// documents describing the dialect and columns of a CSV document.
+//
+// LoadMetadata converts a metadata document into the csv.ReaderOption
+// values which read the table it describes, validating each field against
+// the datatype of its column. DescribeWriter produces the metadata document
+// of the tables a csv.Writer writes.
+//
+// See https://www.w3.org/TR/tabular-metadata/.
+package csvw
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+// Context is the JSON-LD context of CSVW metadata documents.
+const Context = "http://www.w3.org/ns/csvw"
+
+var (
+	// ErrInvalidMetadata is returned when a metadata document is not
+	// valid CSVW metadata.
+	ErrInvalidMetadata = errors.New("invalid csvw metadata")
+	// ErrUnsupported is returned when a metadata document describes a table
+	// which cannot be read or written by this module.
+	ErrUnsupported = errors.New("unsupported csvw metadata")
+)
+
+// Metadata is a CSVW metadata document describing a single table.
+type Metadata struct {
+	// Context is the JSON-LD context, either Context or an array whose
+	// first element is Context.
+	Context     any          `json:"@context"`
+	URL         string       `json:"url,omitempty"`
+	Dialect     *Dialect     `json:"dialect,omitempty"`
+	TableSchema *TableSchema `json:"tableSchema,omitempty"`
+}
+
+// Dialect describes how a table is encoded. A nil field takes the default
+// value defined by the CSVW specification.
+type Dialect struct {
+	CommentPrefix  *string `json:"commentPrefix,omitempty"`
+	Delimiter      *string `json:"delimiter,omitempty"`
+	DoubleQuote    *bool   `json:"doubleQuote,omitempty"`
+	Encoding       *string `json:"encoding,omitempty"`
+	Header         *bool   `json:"header,omitempty"`
+	HeaderRowCount *int    `json:"headerRowCount,omitempty"`
+	// LineTerminators defaults to both "\r\n" and "\n".
+	LineTerminators Strings `json:"lineTerminators,omitempty"`
+	// QuoteChar is encoded as null when it points to an empty string,
+	// meaning fields are never quoted.
+	QuoteChar        *string `json:"quoteChar,omitempty"`
+	SkipBlankRows    *bool   `json:"skipBlankRows,omitempty"`
+	SkipColumns      *int    `json:"skipColumns,omitempty"`
+	SkipInitialSpace *bool   `json:"skipInitialSpace,omitempty"`
+	SkipRows         *int    `json:"skipRows,omitempty"`
+	Trim             Trim    `json:"trim,omitempty"`
+}
+
+type dialectAlias Dialect
+
+func (d *Dialect) UnmarshalJSON(b []byte) error {
+	v := struct {
+		*dialectAlias
+		QuoteChar json.RawMessage `json:"quoteChar"`
+	}{dialectAlias: (*dialectAlias)(d)}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	switch {
+	case v.QuoteChar == nil:
+		d.QuoteChar = nil
+	case string(v.QuoteChar) == "null":
+		d.QuoteChar = new(string)
+	default:
+		var s string
+		if err := json.Unmarshal(v.QuoteChar, &s); err != nil {
+			return err
+		}
+		d.QuoteChar = &s
+	}
+
+	return nil
+}
+
+func (d Dialect) MarshalJSON() ([]byte, error) {
+	v := struct {
+		*dialectAlias
+		QuoteChar json.RawMessage `json:"quoteChar,omitempty"`
+	}{dialectAlias: (*dialectAlias)(&d)}
+
+	if d.QuoteChar != nil {
+		if *d.QuoteChar == "" {
+			v.QuoteChar = json.RawMessage("null")
+		} else {
+			b, err := json.Marshal(*d.QuoteChar)
+			if err != nil {
+				return nil, err
+			}
+			v.QuoteChar = b
+		}
+	}
+
+	return json.Marshal(v)
+}
+
+// TableSchema describes the columns of a table in column order.
+type TableSchema struct {
+	Columns []Column `json:"columns,omitempty"`
+}
+
+// Column describes a column of a table.
+type Column struct {
+	// Name defaults to the first of Titles.
+	Name string `json:"name,omitempty"`
+	// Titles are the values the header row may hold for the column.
+	Titles   Strings   `json:"titles,omitempty"`
+	Datatype *Datatype `json:"datatype,omitempty"`
+	Required bool      `json:"required,omitempty"`
+	// Null are the values which represent a missing value. It defaults to
+	// the empty string.
+	Null Strings `json:"null,omitempty"`
+	// Virtual columns are not present in the table.
+	Virtual bool `json:"virtual,omitempty"`
+}
+
+// Datatype describes the values of a column. It is encoded as its Base
+// alone when no other field is set.
+type Datatype struct {
+	// Base is the name of a CSVW built-in datatype such as "string",
+	// "integer", or "date". It defaults to "string".
+	Base string `json:"base,omitempty"`
+	// Format is a regular expression for string types, the true and false
+	// values separated by | for "boolean", or a date field pattern such as
+	// "dd/MM/yyyy" for date and time types.
+	Format       string  `json:"format,omitempty"`
+	Length       *int    `json:"length,omitempty"`
+	MinLength    *int    `json:"minLength,omitempty"`
+	MaxLength    *int    `json:"maxLength,omitempty"`
+	Minimum      Literal `json:"minimum,omitempty"`
+	Maximum      Literal `json:"maximum,omitempty"`
+	MinInclusive Literal `json:"minInclusive,omitempty"`
+	MaxInclusive Literal `json:"maxInclusive,omitempty"`
+	MinExclusive Literal `json:"minExclusive,omitempty"`
+	MaxExclusive Literal `json:"maxExclusive,omitempty"`
+}
+
+type datatypeAlias Datatype
+
+func (d *Datatype) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		*d = Datatype{}
+		return json.Unmarshal(b, &d.Base)
+	}
+
+	v := struct {
+		*datatypeAlias
+		Format json.RawMessage `json:"format"`
+	}{datatypeAlias: (*datatypeAlias)(d)}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	if v.Format != nil {
+		if !bytes.HasPrefix(v.Format, []byte(`"`)) {
+			return errors.Join(ErrUnsupported, errors.New("datatype format must be a string"))
+		}
+		if err := json.Unmarshal(v.Format, &d.Format); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Datatype) MarshalJSON() ([]byte, error) {
+	if (Datatype{Base: d.Base}) == d {
+		return json.Marshal(d.Base)
+	}
+
+	return json.Marshal((datatypeAlias)(d))
+}
+
+// Strings is a property which may be encoded as a single string, an array
+// of strings, or, for natural language properties such as titles, an object
+// mapping language tags to either.
+type Strings = descriptor.Strings
+
+// Literal is a property which may be encoded as a JSON number or string,
+// held in its lexical form.
+type Literal string
+
+func (l *Literal) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*l = Literal(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*l = Literal(n)
+
+	return nil
+}
+
+// MarshalJSON encodes l as a JSON number when it is one and as a string
+// otherwise.
+func (l Literal) MarshalJSON() ([]byte, error) {
+	if l != "" && (l[0] == '-' || (l[0] >= '0' && l[0] <= '9')) && json.Valid([]byte(l)) {
+		return []byte(l), nil
+	}
+
+	return json.Marshal(string(l))
+}
+
+// Trim is the trim dialect property: "true", "false", "start", or "end".
+type Trim string
+
+func (t *Trim) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case bool:
+		if v {
+			*t = "true"
+		} else {
+			*t = "false"
+		}
+	case string:
+		*t = Trim(v)
+	default:
+		return errors.Join(ErrInvalidMetadata, errors.New("trim must be a boolean or a string"))
+	}
+
+	return nil
+}
+
+func (t Trim) MarshalJSON() ([]byte, error) {
+	switch t {
+	case "true":
+		return []byte("true"), nil
+	case "false":
+		return []byte("false"), nil
+	}
+
+	return json.Marshal(string(t))
+}
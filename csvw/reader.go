@@ -0,0 +1,491 @@
+package csvw
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+// LoadMetadata decodes a metadata document and verifies that the table it
+// describes can be read with the options returned by ReaderOptions.
+//
+// A table group is accepted when it holds exactly one table, in which case
+// the dialect and table schema of the group apply unless the table
+// overrides them.
+func LoadMetadata(r io.Reader) (*Metadata, error) {
+	var doc struct {
+		Metadata
+		Tables []Metadata `json:"tables"`
+	}
+
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrInvalidMetadata) {
+			return nil, err
+		}
+		return nil, errors.Join(ErrInvalidMetadata, err)
+	}
+
+	m := doc.Metadata
+	if doc.Tables != nil {
+		if len(doc.Tables) != 1 {
+			return nil, errors.Join(ErrUnsupported, errors.New("table groups must contain exactly one table"))
+		}
+
+		t := doc.Tables[0]
+		m.URL = t.URL
+		if t.Dialect != nil {
+			m.Dialect = t.Dialect
+		}
+		if t.TableSchema != nil {
+			m.TableSchema = t.TableSchema
+		}
+	}
+
+	if !validContext(m.Context) {
+		return nil, errors.Join(ErrInvalidMetadata, errors.New("@context must be "+strconv.Quote(Context)))
+	}
+
+	opts, err := m.ReaderOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// the reader validates the combination of options up front
+	cr, err := csv.NewReader(append(opts, csv.ReaderOpts().Reader(strings.NewReader("")))...)
+	if err != nil {
+		return nil, errors.Join(ErrUnsupported, err)
+	}
+	cr.Close()
+
+	return &m, nil
+}
+
+func validContext(v any) bool {
+	if a, ok := v.([]any); ok && len(a) > 0 {
+		v = a[0]
+	}
+
+	return v == Context
+}
+
+// ReaderOptions returns the options which read the table m describes.
+//
+// The header row, when present, is removed and must hold the name or one
+// of the titles of each column in order. When headerRowCount is greater
+// than one only the last header row is checked. Every field is validated
+// against the datatype, constraints, and required flag of its column with
+// csv.ReaderOpts().Schema.
+//
+// The trim dialect property is ignored since fields are never trimmed, and
+// skipColumns, skipInitialSpace, and null values other than the empty
+// string are not supported.
+//
+// Append csv.ReaderOpts().Reader and any further options to the result to
+// read the table.
+func (m *Metadata) ReaderOptions() ([]csv.ReaderOption, error) {
+	var d Dialect
+	if m.Dialect != nil {
+		d = *m.Dialect
+	}
+
+	var opts []csv.ReaderOption
+
+	if d.Delimiter != nil {
+		r, err := descriptor.SingleRune(ErrUnsupported, "delimiter", *d.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, csv.ReaderOpts().FieldSeparator(r))
+	}
+
+	quote := '"'
+	if d.QuoteChar != nil {
+		quote = 0
+		if *d.QuoteChar != "" {
+			r, err := descriptor.SingleRune(ErrUnsupported, "quoteChar", *d.QuoteChar)
+			if err != nil {
+				return nil, err
+			}
+			quote = r
+		}
+	}
+	if quote != 0 {
+		opts = append(opts, csv.ReaderOpts().Quote(quote))
+
+		if d.DoubleQuote != nil && !*d.DoubleQuote {
+			opts = append(opts, csv.ReaderOpts().Escape('\\'))
+		}
+	}
+
+	comment := "#"
+	if d.CommentPrefix != nil {
+		comment = *d.CommentPrefix
+	}
+	if comment != "" {
+		r, err := descriptor.SingleRune(ErrUnsupported, "commentPrefix", comment)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			csv.ReaderOpts().Comment(r),
+			csv.ReaderOpts().CommentsAllowedAfterStartOfRecords(true),
+		)
+	}
+
+	if d.Encoding != nil && !strings.EqualFold(*d.Encoding, "utf-8") {
+		return nil, errors.Join(ErrUnsupported, errors.New("encoding must be utf-8"))
+	}
+	opts = append(opts, csv.ReaderOpts().RemoveByteOrderMarker(true))
+
+	switch lt := d.LineTerminators; {
+	case lt == nil, len(lt) == 2 && lt[0] != lt[1] && isLineFeed(lt[0]) && isLineFeed(lt[1]):
+		opts = append(opts, csv.ReaderOpts().DiscoverRecordSeparator(true))
+	case len(lt) == 1:
+		opts = append(opts, csv.ReaderOpts().RecordSeparator(lt[0]))
+	default:
+		return nil, errors.Join(ErrUnsupported, errors.New("lineTerminators must be a single value or both \"\\r\\n\" and \"\\n\""))
+	}
+
+	if d.SkipRows != nil {
+		if *d.SkipRows < 0 {
+			return nil, errors.Join(ErrInvalidMetadata, errors.New("skipRows cannot be less than zero"))
+		}
+		if *d.SkipRows > 0 {
+			opts = append(opts, csv.ReaderOpts().SkipLeadingLines(*d.SkipRows))
+		}
+	}
+
+	if d.SkipColumns != nil && *d.SkipColumns != 0 {
+		return nil, errors.Join(ErrUnsupported, errors.New("skipColumns must be zero"))
+	}
+
+	if d.SkipInitialSpace != nil && *d.SkipInitialSpace {
+		return nil, errors.Join(ErrUnsupported, errors.New("skipInitialSpace must be false"))
+	}
+
+	if d.SkipBlankRows != nil && *d.SkipBlankRows {
+		opts = append(opts, csv.ReaderOpts().SkipBlankLines(true))
+	}
+
+	headerRows := 1
+	if d.Header != nil && !*d.Header {
+		headerRows = 0
+	}
+	if d.HeaderRowCount != nil {
+		if *d.HeaderRowCount < 0 {
+			return nil, errors.Join(ErrInvalidMetadata, errors.New("headerRowCount cannot be less than zero"))
+		}
+		headerRows = *d.HeaderRowCount
+	}
+
+	var columns []Column
+	if m.TableSchema != nil {
+		columns = m.TableSchema.Columns
+	}
+
+	schema := csv.Schema{}
+	var names []string
+	aliases := map[string][]string{}
+	checkHeaders := true
+	for i, c := range columns {
+		if c.Virtual {
+			continue
+		}
+
+		name := c.Name
+		if name == "" && len(c.Titles) > 0 {
+			name = c.Titles[0]
+		}
+		if name == "" {
+			checkHeaders = false
+		}
+
+		for _, t := range c.Titles {
+			if t != name {
+				aliases[name] = append(aliases[name], t)
+			}
+		}
+		names = append(names, name)
+
+		cs, err := columnSchema(c)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", i+1, err)
+		}
+		cs.Name = name
+		schema.Columns = append(schema.Columns, cs)
+	}
+
+	if headerRows > 0 {
+		if headerRows > 1 {
+			opts = append(opts, csv.ReaderOpts().SkipLeadingRecords(headerRows-1))
+		}
+
+		if checkHeaders && len(names) > 0 {
+			opts = append(opts, csv.ReaderOpts().ExpectHeaders(names...))
+			if len(aliases) > 0 {
+				opts = append(opts, csv.ReaderOpts().HeaderAliases(aliases))
+			}
+		}
+		opts = append(opts, csv.ReaderOpts().RemoveHeaderRow(true))
+	}
+
+	if len(schema.Columns) > 0 {
+		opts = append(opts, csv.ReaderOpts().Schema(schema))
+	}
+
+	return opts, nil
+}
+
+func isLineFeed(s string) bool {
+	return s == "\r\n" || s == "\n"
+}
+
+// isoDurationPattern matches the lexical form of xsd:duration.
+const isoDurationPattern = `^-?P(?:[0-9]+Y)?(?:[0-9]+M)?(?:[0-9]+D)?(?:T(?:[0-9]+H)?(?:[0-9]+M)?(?:[0-9]+(?:\.[0-9]+)?S)?)?$`
+
+// integerBounds are the implicit bounds of the integer datatypes. An empty
+// bound is unbounded.
+var integerBounds = map[string][2]string{
+	"integer":            {},
+	"long":               {},
+	"int":                {"-2147483648", "2147483647"},
+	"short":              {"-32768", "32767"},
+	"byte":               {"-128", "127"},
+	"nonPositiveInteger": {"", "0"},
+	"negativeInteger":    {"", "-1"},
+}
+
+// unsignedBounds are the implicit bounds of the non-negative integer
+// datatypes.
+var unsignedBounds = map[string][2]string{
+	"nonNegativeInteger": {},
+	"unsignedLong":       {},
+	"positiveInteger":    {"1", ""},
+	"unsignedInt":        {"", "4294967295"},
+	"unsignedShort":      {"", "65535"},
+	"unsignedByte":       {"", "255"},
+}
+
+// timeLayouts are the time layouts of the date and time datatypes when no
+// format is given.
+var timeLayouts = map[string]string{
+	"date":          time.DateOnly,
+	"dateTime":      "2006-01-02T15:04:05",
+	"datetime":      "2006-01-02T15:04:05",
+	"dateTimeStamp": time.RFC3339Nano,
+	"time":          "15:04:05",
+	"gYear":         "2006",
+	"gYearMonth":    "2006-01",
+}
+
+// stringTypes are the datatypes whose values are validated as strings.
+var stringTypes = map[string]bool{
+	"string":           true,
+	"normalizedString": true,
+	"token":            true,
+	"language":         true,
+	"Name":             true,
+	"NMTOKEN":          true,
+	"anyAtomicType":    true,
+	"anyURI":           true,
+	"base64Binary":     true,
+	"hexBinary":        true,
+	"QName":            true,
+	"xml":              true,
+	"html":             true,
+	"json":             true,
+}
+
+// columnSchema converts the datatype and constraints of a column into a
+// csv.ColumnSchema.
+func columnSchema(c Column) (csv.ColumnSchema, error) {
+	cs := csv.ColumnSchema{Required: c.Required}
+
+	for _, v := range c.Null {
+		if v != "" {
+			return cs, errors.Join(ErrUnsupported, errors.New("null values must be empty"))
+		}
+	}
+
+	dt := Datatype{Base: "string"}
+	if c.Datatype != nil {
+		dt = *c.Datatype
+		if dt.Base == "" {
+			dt.Base = "string"
+		}
+	}
+
+	var lo, hi string
+	switch base := dt.Base; {
+	case stringTypes[base]:
+		if dt.Format != "" {
+			p, err := regexp.Compile(`^(?:` + dt.Format + `)$`)
+			if err != nil {
+				return cs, errors.Join(ErrInvalidMetadata, fmt.Errorf("format: %w", err))
+			}
+			cs.Pattern = p
+		}
+	case base == "boolean":
+		cs.Enum = []string{"true", "false", "1", "0"}
+		if dt.Format != "" {
+			t, f, ok := strings.Cut(dt.Format, "|")
+			if !ok || t == "" || f == "" {
+				return cs, errors.Join(ErrInvalidMetadata, errors.New("boolean format must be two values separated by |"))
+			}
+			cs.Enum = []string{t, f}
+		}
+	case base == "decimal", base == "double", base == "float", base == "number":
+		cs.Type = csv.ColumnFloat64
+		if base == "decimal" {
+			cs.Type = csv.ColumnDecimal
+		}
+		if dt.Format != "" {
+			return cs, errors.Join(ErrUnsupported, errors.New("numeric formats are not supported"))
+		}
+	case base == "duration", base == "dayTimeDuration", base == "yearMonthDuration":
+		cs.Pattern = regexp.MustCompile(isoDurationPattern)
+	default:
+		if b, ok := integerBounds[base]; ok {
+			cs.Type = csv.ColumnInt64
+			lo, hi = b[0], b[1]
+		} else if b, ok := unsignedBounds[base]; ok {
+			cs.Type = csv.ColumnUint64
+			lo, hi = b[0], b[1]
+		} else if layout, ok := timeLayouts[base]; ok {
+			cs.Type = csv.ColumnTime
+			cs.Layout = layout
+			if dt.Format != "" {
+				var err error
+				if cs.Layout, err = goLayout(dt.Format); err != nil {
+					return cs, err
+				}
+			}
+		} else {
+			return cs, errors.Join(ErrUnsupported, errors.New("datatype "+strconv.Quote(base)+" is not supported"))
+		}
+
+		if (cs.Type == csv.ColumnInt64 || cs.Type == csv.ColumnUint64) && dt.Format != "" {
+			return cs, errors.Join(ErrUnsupported, errors.New("numeric formats are not supported"))
+		}
+	}
+
+	if dt.Length != nil {
+		dt.MinLength, dt.MaxLength = dt.Length, dt.Length
+	}
+	if dt.MinLength != nil {
+		cs.MinLength = *dt.MinLength
+	}
+	if dt.MaxLength != nil {
+		cs.MaxLength = *dt.MaxLength
+	}
+
+	integer := cs.Type == csv.ColumnInt64 || cs.Type == csv.ColumnUint64
+	bounds := []struct {
+		v         Literal
+		lower     bool
+		exclusive bool
+	}{
+		{dt.Minimum, true, false},
+		{dt.MinInclusive, true, false},
+		{dt.MinExclusive, true, true},
+		{dt.Maximum, false, false},
+		{dt.MaxInclusive, false, false},
+		{dt.MaxExclusive, false, true},
+	}
+	for _, b := range bounds {
+		if b.v == "" {
+			continue
+		}
+
+		v := string(b.v)
+		if b.exclusive {
+			if !integer {
+				return cs, errors.Join(ErrUnsupported, errors.New("exclusive bounds are only supported for integer datatypes"))
+			}
+
+			var err error
+			if v, err = stepInteger(v, cs.Type, b.lower); err != nil {
+				return cs, err
+			}
+		}
+
+		if b.lower {
+			lo = tighterBound(cs.Type, lo, v, true)
+		} else {
+			hi = tighterBound(cs.Type, hi, v, false)
+		}
+	}
+	cs.Minimum, cs.Maximum = lo, hi
+
+	return cs, nil
+}
+
+// stepInteger returns the inclusive equivalent of an exclusive integer
+// bound.
+func stepInteger(v string, t csv.ColumnType, lower bool) (string, error) {
+	errInvalid := errors.Join(ErrInvalidMetadata, errors.New("bound "+strconv.Quote(v)+" is not a valid "+t.String()))
+
+	if t == csv.ColumnUint64 {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || (lower && n == ^uint64(0)) || (!lower && n == 0) {
+			return "", errInvalid
+		}
+		if lower {
+			return strconv.FormatUint(n+1, 10), nil
+		}
+		return strconv.FormatUint(n-1, 10), nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || (lower && n == int64(^uint64(0)>>1)) || (!lower && n == -int64(^uint64(0)>>1)-1) {
+		return "", errInvalid
+	}
+	if lower {
+		return strconv.FormatInt(n+1, 10), nil
+	}
+	return strconv.FormatInt(n-1, 10), nil
+}
+
+// tighterBound returns the more restrictive of two bounds. Only integer
+// bounds are compared, otherwise v is returned. Bounds which cannot be
+// parsed are left for the reader to reject.
+func tighterBound(t csv.ColumnType, cur, v string, lower bool) string {
+	if cur == "" {
+		return v
+	}
+
+	var c int
+	switch t {
+	case csv.ColumnInt64:
+		a, errA := strconv.ParseInt(cur, 10, 64)
+		b, errB := strconv.ParseInt(v, 10, 64)
+		if errA != nil || errB != nil {
+			return v
+		}
+		c = cmp.Compare(a, b)
+	case csv.ColumnUint64:
+		a, errA := strconv.ParseUint(cur, 10, 64)
+		b, errB := strconv.ParseUint(v, 10, 64)
+		if errA != nil || errB != nil {
+			return v
+		}
+		c = cmp.Compare(a, b)
+	default:
+		return v
+	}
+
+	if (lower && c > 0) || (!lower && c < 0) {
+		return cur
+	}
+	return v
+}
@@ -0,0 +1,404 @@
+package csvw_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/csvw"
+	"github.com/stretchr/testify/assert"
+)
+
+// readTable loads metadata and reads every record of doc with the options
+// it describes.
+func readTable(t *testing.T, metadata, doc string) ([][]string, error) {
+	t.Helper()
+
+	m, err := csvw.LoadMetadata(strings.NewReader(metadata))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := m.ReaderOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr, err := csv.NewReader(append(opts, csv.ReaderOpts().Reader(strings.NewReader(doc)))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cr.Close()
+
+	var rows [][]string
+	for cr.Scan() {
+		rows = append(rows, append([]string(nil), cr.Row()...))
+	}
+
+	return rows, cr.Err()
+}
+
+const testMetadata = `{
+	"@context": ["http://www.w3.org/ns/csvw", {"@language": "en"}],
+	"url": "orders.csv",
+	"dialect": {"delimiter": ";", "skipRows": 1, "lineTerminators": "\n", "skipBlankRows": true},
+	"tableSchema": {
+		"columns": [
+			{"name": "id", "titles": {"en": "Order", "fr": "Commande"}, "datatype": {"base": "short", "minExclusive": 0}, "required": true},
+			{"name": "placed", "titles": "Placed", "datatype": {"base": "date", "format": "dd/MM/yyyy"}},
+			{"name": "paid", "titles": "Paid", "datatype": {"base": "boolean", "format": "Y|N"}},
+			{"name": "code", "titles": "Code", "datatype": {"base": "string", "format": "[A-Z]{2}", "maxLength": 2}},
+			{"name": "total", "titles": "Total", "datatype": "decimal"},
+			{"name": "link", "virtual": true}
+		]
+	}
+}`
+
+func TestFunctionalLoadMetadata(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when    string
+		doc     string
+		rows    [][]string
+		errIs   []error
+		errStr  string
+		errAsSV bool
+	}{
+		{
+			when: "every field satisfies the metadata",
+			doc:  "produced by orders\nCommande;Placed;Paid;Code;Total\n# a comment\n1;02/01/2024;Y;AB;1.50\n\n2;;N;;\n",
+			rows: [][]string{{"1", "02/01/2024", "Y", "AB", "1.50"}, {"2", "", "N", "", ""}},
+		},
+		{
+			when:  "the header row does not match the titles",
+			doc:   "produced by orders\nOrder;Placed;Paid;Code;Amount\n",
+			errIs: []error{csv.ErrUnexpectedHeaderRowContents},
+		},
+		{
+			when:    "a field is outside of its datatype bounds",
+			doc:     "produced by orders\nOrder;Placed;Paid;Code;Total\n0;02/01/2024;Y;AB;1.50\n",
+			errIs:   []error{csv.ErrSchemaViolation, csv.ErrSchemaRange},
+			errStr:  csv.ErrSchemaViolation.Error() + " at byte 71, record 2, field 1: column \"id\": " + csv.ErrSchemaRange.Error() + ": less than minimum 1",
+			errAsSV: true,
+		},
+		{
+			when:   "a date does not match its format",
+			doc:    "produced by orders\nOrder;Placed;Paid;Code;Total\n1;2024-01-02;Y;AB;1.50\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 71, record 2, field 2: column \"placed\": " + csv.ErrSchemaType.Error() + ": time",
+		},
+		{
+			when:   "a boolean is not one of its format values",
+			doc:    "produced by orders\nOrder;Placed;Paid;Code;Total\n1;;true;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaEnum},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 58, record 2, field 3: column \"paid\": " + csv.ErrSchemaEnum.Error(),
+		},
+		{
+			when:   "a string does not match its format",
+			doc:    "produced by orders\nOrder;Placed;Paid;Code;Total\n1;;;A1;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaPattern},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 56, record 2, field 4: column \"code\": " + csv.ErrSchemaPattern.Error() + ": ^(?:[A-Z]{2})$",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			rows, err := readTable(t, testMetadata, tc.doc)
+			is.Equal(tc.rows, rows)
+
+			if tc.errIs == nil {
+				is.Nil(err)
+				return
+			}
+
+			for _, target := range tc.errIs {
+				is.ErrorIs(err, target)
+			}
+			if tc.errStr != "" {
+				is.Equal(tc.errStr, err.Error())
+			}
+			if tc.errAsSV {
+				var sv csv.SchemaViolation
+				if is.ErrorAs(err, &sv) {
+					is.Equal("id", sv.Column)
+					is.Equal(uint64(2), sv.Record)
+				}
+			}
+		})
+	}
+}
+
+func TestFunctionalLoadMetadataDefaults(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	const metadata = `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"titles": "a"}, {"titles": "b", "datatype": "integer"}]}}`
+
+	rows, err := readTable(t, metadata, "\ufeffa,b\r\n# comment\r\n\"x,y\",1\r\nz,-2\r\n")
+	is.Nil(err)
+	is.Equal([][]string{{"x,y", "1"}, {"z", "-2"}}, rows)
+
+	rows, err = readTable(t, `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"header": false, "quoteChar": null, "commentPrefix": ""}}`, "#a,\"b\n")
+	is.Nil(err)
+	is.Equal([][]string{{"#a", "\"b"}}, rows)
+
+	rows, err = readTable(t, `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"doubleQuote": false, "headerRowCount": 2}}`, "title\na\n\"x\\\"y\"\n")
+	is.Nil(err)
+	is.Equal([][]string{{"x\"y"}}, rows)
+
+	rows, err = readTable(t, `{"@context": "http://www.w3.org/ns/csvw", "tables": [{"url": "t.csv", "tableSchema": {"columns": [{"name": "n", "datatype": "positiveInteger"}]}}], "dialect": {"header": false}}`, "1\n")
+	is.Nil(err)
+	is.Equal([][]string{{"1"}}, rows)
+}
+
+func TestFunctionalLoadMetadataErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when     string
+		metadata string
+		errIs    error
+		errStr   string
+	}{
+		{
+			when:     "the document is not json",
+			metadata: `{`,
+			errIs:    csvw.ErrInvalidMetadata,
+			errStr:   csvw.ErrInvalidMetadata.Error() + "\nunexpected EOF",
+		},
+		{
+			when:     "the context is missing",
+			metadata: `{}`,
+			errIs:    csvw.ErrInvalidMetadata,
+			errStr:   csvw.ErrInvalidMetadata.Error() + "\n@context must be \"http://www.w3.org/ns/csvw\"",
+		},
+		{
+			when:     "a table group holds several tables",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tables": [{}, {}]}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\ntable groups must contain exactly one table",
+		},
+		{
+			when:     "the delimiter is several characters",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"delimiter": "||"}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\ndelimiter must be a single character",
+		},
+		{
+			when:     "the encoding is not utf-8",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"encoding": "latin1"}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\nencoding must be utf-8",
+		},
+		{
+			when:     "columns are skipped",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"skipColumns": 1}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\nskipColumns must be zero",
+		},
+		{
+			when:     "the line terminators are unsupported",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"lineTerminators": ["\n", "\r"]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\nlineTerminators must be a single value or both \"\\r\\n\" and \"\\n\"",
+		},
+		{
+			when:     "the header row count is negative",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"headerRowCount": -1}}`,
+			errIs:    csvw.ErrInvalidMetadata,
+			errStr:   csvw.ErrInvalidMetadata.Error() + "\nheaderRowCount cannot be less than zero",
+		},
+		{
+			when:     "a datatype is unknown",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "datatype": "point"}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   "column 1: " + csvw.ErrUnsupported.Error() + "\ndatatype \"point\" is not supported",
+		},
+		{
+			when:     "a null value is not empty",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "null": ["", "NA"]}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   "column 1: " + csvw.ErrUnsupported.Error() + "\nnull values must be empty",
+		},
+		{
+			when:     "a numeric datatype has a format",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "datatype": {"base": "integer", "format": "#,##0"}}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   "column 1: " + csvw.ErrUnsupported.Error() + "\nnumeric formats are not supported",
+		},
+		{
+			when:     "a numeric format is an object",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "datatype": {"base": "number", "format": {"groupChar": ","}}}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   csvw.ErrUnsupported.Error() + "\ndatatype format must be a string",
+		},
+		{
+			when:     "a date format has unsupported fields",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "datatype": {"base": "date", "format": "EEE, d MMM yyyy"}}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   "column 1: " + csvw.ErrUnsupported.Error() + "\ndate format \"EEE, d MMM yyyy\" is not supported",
+		},
+		{
+			when:     "a non-integer datatype has an exclusive bound",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "tableSchema": {"columns": [{"name": "a", "datatype": {"base": "double", "maxExclusive": 1}}]}}`,
+			errIs:    csvw.ErrUnsupported,
+			errStr:   "column 1: " + csvw.ErrUnsupported.Error() + "\nexclusive bounds are only supported for integer datatypes",
+		},
+		{
+			when:     "the reader rejects the combination of options",
+			metadata: `{"@context": "http://www.w3.org/ns/csvw", "dialect": {"delimiter": "\""}}`,
+			errIs:    csv.ErrBadConfig,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			m, err := csvw.LoadMetadata(strings.NewReader(tc.metadata))
+			is.Nil(m)
+			is.ErrorIs(err, tc.errIs)
+			if tc.errStr != "" {
+				is.Equal(tc.errStr, err.Error())
+			}
+		})
+	}
+}
+
+func TestFunctionalDescribeWriter(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	opts := []csv.WriterOption{
+		csv.WriterOpts().FieldSeparator('\t'),
+		csv.WriterOpts().Escape('\\'),
+		csv.WriterOpts().RecordSeparator("\r\n"),
+	}
+	schema := csv.Schema{Columns: []csv.ColumnSchema{
+		{Name: "id", Type: csv.ColumnUint64, Required: true, Minimum: "1"},
+		{Name: "day", Type: csv.ColumnTime, Layout: time.DateOnly},
+		{Name: "ok", Type: csv.ColumnBool},
+		{Name: "state", Enum: []string{"new", "a.b"}},
+		{Name: "note", MaxLength: 10},
+	}}
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(append(opts, csv.WriterOpts().Writer(&buf))...)
+	is.Nil(err)
+
+	m, err := csvw.DescribeWriter(cw, csvw.TableDescription{
+		URL:    "out.tsv",
+		Header: []string{"ID", "day", "ok", "state", "note"},
+		Schema: &schema,
+	})
+	is.Nil(err)
+
+	b, err := json.Marshal(m)
+	is.Nil(err)
+	is.JSONEq(`{
+		"@context": "http://www.w3.org/ns/csvw",
+		"url": "out.tsv",
+		"dialect": {
+			"commentPrefix": "",
+			"delimiter": "\t",
+			"doubleQuote": false,
+			"encoding": "utf-8",
+			"header": true,
+			"lineTerminators": "\r\n",
+			"quoteChar": "\"",
+			"trim": false
+		},
+		"tableSchema": {"columns": [
+			{"name": "id", "titles": "ID", "datatype": {"base": "unsignedLong", "minimum": 1}, "required": true},
+			{"titles": "day", "datatype": {"base": "date", "format": "yyyy-MM-dd"}},
+			{"titles": "ok", "datatype": {"base": "boolean", "format": "1|0"}},
+			{"titles": "state", "datatype": {"base": "string", "format": "new|a\\.b"}},
+			{"titles": "note", "datatype": {"base": "string", "maxLength": 10}}
+		]}
+	}`, string(b))
+
+	// the written table reads back with the described options
+	_, err = cw.WriteRow("ID", "day", "ok", "state", "note")
+	is.Nil(err)
+	_, err = cw.WriteRow("7", "2024-01-02", "1", "a.b", "say \"hi\"")
+	is.Nil(err)
+	is.Nil(cw.Close())
+
+	rows, err := readTable(t, string(b), buf.String())
+	is.Nil(err)
+	is.Equal([][]string{{"7", "2024-01-02", "1", "a.b", "say \"hi\""}}, rows)
+}
+
+func TestFunctionalDescribeWriterErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		opts   []csv.WriterOption
+		table  csvw.TableDescription
+		errIs  error
+		errStr string
+	}{
+		{
+			when:   "the escape is not a backslash",
+			opts:   []csv.WriterOption{csv.WriterOpts().Escape('~')},
+			errIs:  csvw.ErrUnsupported,
+			errStr: csvw.ErrUnsupported.Error() + "\nescape must be a backslash",
+		},
+		{
+			when: "the header and schema column counts differ",
+			table: csvw.TableDescription{
+				Header: []string{"a", "b"},
+				Schema: &csv.Schema{Columns: []csv.ColumnSchema{{Name: "a"}}},
+			},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nheader and schema column counts must match",
+		},
+		{
+			when: "a column has both a pattern and an enum",
+			table: csvw.TableDescription{
+				Schema: &csv.Schema{Columns: []csv.ColumnSchema{{Name: "a", Pattern: regexp.MustCompile("x"), Enum: []string{"x"}}}},
+			},
+			errIs:  csvw.ErrUnsupported,
+			errStr: "column 1: " + csvw.ErrUnsupported.Error() + "\na column with both a pattern and an enum cannot be described",
+		},
+		{
+			when: "a time layout has unsupported elements",
+			table: csvw.TableDescription{
+				Schema: &csv.Schema{Columns: []csv.ColumnSchema{{Name: "a", Type: csv.ColumnTime, Layout: "Jan 2, 2006"}}},
+			},
+			errIs:  csvw.ErrUnsupported,
+			errStr: "column 1: " + csvw.ErrUnsupported.Error() + "\ntime layout \"Jan 2, 2006\" cannot be described",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			cw, err := csv.NewWriter(append(tc.opts, csv.WriterOpts().Writer(io.Discard))...)
+			is.Nil(err)
+
+			m, err := csvw.DescribeWriter(cw, tc.table)
+			is.Nil(m)
+			is.ErrorIs(err, tc.errIs)
+			is.Equal(tc.errStr, err.Error())
+		})
+	}
+}
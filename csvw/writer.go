@@ -0,0 +1,182 @@
+package csvw
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+// TableDescription describes the content of a table for DescribeWriter.
+type TableDescription struct {
+	// URL is the location of the table, usually relative to the metadata
+	// document.
+	URL string
+	// Header is the header row written to the table, if any. When empty
+	// the table has no header row.
+	Header []string
+	// Schema, when not nil, describes the values of each column. Columns
+	// are named by Schema when Header is empty.
+	Schema *csv.Schema
+}
+
+// DescribeWriter returns the metadata document of a table written by w. The
+// columns of the table are described by t.
+func DescribeWriter(w *csv.Writer, t TableDescription) (*Metadata, error) {
+	cd := w.Dialect()
+
+	d := &Dialect{
+		Delimiter:       descriptor.Ptr(string(cd.FieldSeparator)),
+		QuoteChar:       descriptor.Ptr(string(cd.Quote)),
+		Header:          descriptor.Ptr(len(t.Header) > 0),
+		LineTerminators: Strings{cd.RecordSeparator},
+		Encoding:        descriptor.Ptr("utf-8"),
+		CommentPrefix:   descriptor.Ptr(""),
+		Trim:            "false",
+	}
+
+	switch cd.Escape {
+	case 0:
+		d.DoubleQuote = descriptor.Ptr(true)
+	case '\\':
+		d.DoubleQuote = descriptor.Ptr(false)
+	default:
+		return nil, errors.Join(ErrUnsupported, errors.New("escape must be a backslash"))
+	}
+
+	if cd.Comment != 0 {
+		*d.CommentPrefix = string(cd.Comment)
+	}
+
+	m := &Metadata{
+		Context: Context,
+		URL:     t.URL,
+		Dialect: d,
+	}
+
+	var columns []csv.ColumnSchema
+	if t.Schema != nil {
+		columns = t.Schema.Columns
+		if len(t.Header) > 0 && len(t.Header) != len(columns) {
+			return nil, errors.Join(csv.ErrBadConfig, errors.New("header and schema column counts must match"))
+		}
+	}
+
+	n := max(len(t.Header), len(columns))
+	if n == 0 {
+		return m, nil
+	}
+
+	m.TableSchema = &TableSchema{Columns: make([]Column, n)}
+	for i := range n {
+		c := &m.TableSchema.Columns[i]
+
+		if i < len(t.Header) {
+			c.Titles = Strings{t.Header[i]}
+		}
+
+		if i < len(columns) {
+			cs := columns[i]
+			if cs.Name != "" && (c.Titles == nil || cs.Name != c.Titles[0]) {
+				c.Name = cs.Name
+			}
+
+			c.Required = cs.Required
+
+			dt, err := describeColumn(cs)
+			if err != nil {
+				return nil, fmt.Errorf("column %d: %w", i+1, err)
+			}
+			if dt != (Datatype{Base: "string"}) {
+				c.Datatype = &dt
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// describeColumn converts a csv.ColumnSchema into the datatype which
+// validates the same values.
+func describeColumn(cs csv.ColumnSchema) (Datatype, error) {
+	dt := Datatype{Base: "string"}
+
+	switch cs.Type {
+	case csv.ColumnString:
+	case csv.ColumnBool:
+		dt = Datatype{Base: "boolean", Format: "1|0"}
+	case csv.ColumnInt64:
+		dt.Base = "long"
+	case csv.ColumnUint64:
+		dt.Base = "unsignedLong"
+	case csv.ColumnDecimal:
+		dt.Base = "decimal"
+	case csv.ColumnFloat64:
+		dt.Base = "double"
+	case csv.ColumnTime:
+		layout := cmp.Or(cs.Layout, time.RFC3339Nano)
+		if layout == time.RFC3339Nano {
+			dt.Base = "dateTimeStamp"
+			break
+		}
+
+		format, hasDate, hasTime, err := dateFormat(layout)
+		if err != nil {
+			return dt, err
+		}
+		dt.Format = format
+		switch {
+		case hasDate && hasTime:
+			dt.Base = "dateTime"
+		case hasDate:
+			dt.Base = "date"
+		default:
+			dt.Base = "time"
+		}
+	case csv.ColumnDuration:
+		// Go durations are not xsd:duration values
+	default:
+		return dt, errors.Join(csv.ErrBadConfig, errors.New("invalid column type"))
+	}
+
+	if cs.MinLength > 0 {
+		dt.MinLength = descriptor.Ptr(cs.MinLength)
+	}
+	if cs.MaxLength > 0 {
+		dt.MaxLength = descriptor.Ptr(cs.MaxLength)
+	}
+
+	if cs.Minimum != "" || cs.Maximum != "" {
+		if cs.Type == csv.ColumnString || cs.Type == csv.ColumnDuration {
+			return dt, errors.Join(ErrUnsupported, errors.New("bounds of "+cs.Type.String()+" columns cannot be described"))
+		}
+		dt.Minimum = Literal(cs.Minimum)
+		dt.Maximum = Literal(cs.Maximum)
+	}
+
+	if cs.Pattern != nil || len(cs.Enum) > 0 {
+		if dt.Base != "string" || cs.Type == csv.ColumnDuration {
+			return dt, errors.Join(ErrUnsupported, errors.New("patterns and enums of "+cs.Type.String()+" columns cannot be described"))
+		}
+		if cs.Pattern != nil && len(cs.Enum) > 0 {
+			return dt, errors.Join(ErrUnsupported, errors.New("a column with both a pattern and an enum cannot be described"))
+		}
+
+		if cs.Pattern != nil {
+			dt.Format = cs.Pattern.String()
+		} else {
+			quoted := make([]string, len(cs.Enum))
+			for i, v := range cs.Enum {
+				quoted[i] = regexp.QuoteMeta(v)
+			}
+			dt.Format = strings.Join(quoted, "|")
+		}
+	}
+
+	return dt, nil
+}
@@ -0,0 +1,794 @@
+package csv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// ChangeType classifies a Change between two documents.
+type ChangeType uint8
+
+const (
+	// ChangeAdded is a record whose key is only present in the new document.
+	ChangeAdded ChangeType = iota + 1
+	// ChangeRemoved is a record whose key is only present in the old
+	// document.
+	ChangeRemoved
+	// ChangeModified is a record whose key is present in both documents
+	// with different values in at least one shared column.
+	ChangeModified
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// ColumnChange is the old and new value of a column of a modified record.
+type ColumnChange struct {
+	Column string
+	Old    string
+	New    string
+}
+
+// Change describes how the record identified by Key differs between two
+// documents.
+type Change struct {
+	Type ChangeType
+	// Key is the value of each key column.
+	Key []string
+	// Old is the record from the old document in its column order, or nil
+	// when the record was added.
+	Old []string
+	// New is the record from the new document in its column order, or nil
+	// when the record was removed.
+	New []string
+	// Columns lists every shared column whose value differs when the
+	// record was modified, in the column order of the new document.
+	Columns []ColumnChange
+}
+
+// diffNumBuckets is the number of partitions each input is split into
+// once a hash diff exceeds its memory budget.
+const diffNumBuckets = 64
+
+// diffMaxSplitDepth bounds how many times a partition which still exceeds
+// the memory budget is split again.
+const diffMaxSplitDepth = 4
+
+type diffCfg struct {
+	tempDir      string
+	memoryBudget int64
+	sorted       bool
+}
+
+type DiffOption func(*diffCfg)
+
+// DiffOptions should never be instantiated manually
+//
+// Instead call DiffOpts()
+//
+// This is only exported to allow godocs to discover the exported methods.
+//
+// DiffOptions will never have exported members and the zero value is not
+// part of the semver guarantee. Instantiate it incorrectly at your own peril.
+//
+// Calling the function is a nop that is compiled away anyways, you will not
+// optimize anything at all. Use DiffOpts()!
+type DiffOptions struct{}
+
+func DiffOpts() DiffOptions {
+	return DiffOptions{}
+}
+
+// Sorted specifies that both documents are ordered by their key columns,
+// compared as strings in ascending order, so they can be compared in a
+// single streaming pass which uses constant memory.
+//
+// An error is returned should either document be out of order.
+func (DiffOptions) Sorted(b bool) DiffOption {
+	return func(cfg *diffCfg) {
+		cfg.sorted = b
+	}
+}
+
+// MemoryBudget is the approximate number of bytes of old records to hold in
+// memory when the documents are not sorted. Should the old document exceed
+// it, both documents are partitioned by key into temporary files which are
+// compared one partition at a time. A partition which still exceeds it is
+// partitioned again. It defaults to 64MiB.
+func (DiffOptions) MemoryBudget(n int64) DiffOption {
+	return func(cfg *diffCfg) {
+		cfg.memoryBudget = n
+	}
+}
+
+// TempDir is the directory temporary partition files are created in. It
+// defaults to os.TempDir().
+func (DiffOptions) TempDir(dir string) DiffOption {
+	return func(cfg *diffCfg) {
+		cfg.tempDir = dir
+	}
+}
+
+// differ compares two documents which each begin with a header row.
+type differ struct {
+	a, b    Reader
+	cfg     diffCfg
+	keys    []string
+	aHeader []string
+	bHeader []string
+	aKey    []int
+	bKey    []int
+	// shared holds the a and b index of every non-key column present in
+	// both documents in the column order of b
+	shared [][2]int
+	runs   []string
+}
+
+// Diff compares the records of the old document a to those of the new
+// document b, matching records by the values of the keyColumns.
+//
+// The first record of each document must be its header row. Columns are
+// matched by name so reordering columns is not reported as a change, and
+// only columns present in both documents are compared. Every key must be
+// unique within each document and every record must have as many fields as
+// the header row of its document.
+//
+// Unless the Sorted option is used, changes are yielded in the order the
+// new records are read followed by removed records, and both orders are
+// unspecified once the MemoryBudget is exceeded.
+//
+// Should an error occur it is yielded with a zero Change and iteration
+// stops. Neither reader is closed.
+func Diff(a, b Reader, keyColumns []string, options ...DiffOption) iter.Seq2[Change, error] {
+	return func(yield func(Change, error) bool) {
+		d, err := newDiffer(a, b, keyColumns, options)
+		if err == nil {
+			defer d.removeRuns()
+			err = d.run(func(c Change) bool {
+				return yield(c, nil)
+			})
+		}
+
+		if err != nil {
+			yield(Change{}, err)
+		}
+	}
+}
+
+// WriteDiff compares a to b as Diff does and writes each change to dst.
+//
+// The header row written is "change" followed by every column of b and then
+// every column only present in a. The first field of each record is the
+// ChangeType. Removed records are written with their old values while added
+// and modified records are written with their new values. Columns missing
+// from a document are written as empty fields.
+func WriteDiff(dst *Writer, a, b Reader, keyColumns []string, options ...DiffOption) error {
+	d, err := newDiffer(a, b, keyColumns, options)
+	if err != nil {
+		return err
+	}
+	defer d.removeRuns()
+
+	header := append([]string{"change"}, d.bHeader...)
+
+	// the header position of each column of b and then of a
+	bCols := make([]int, len(d.bHeader))
+	for i := range bCols {
+		bCols[i] = i + 1
+	}
+	aCols := make([]int, len(d.aHeader))
+	for i, h := range d.aHeader {
+		if j := slices.Index(d.bHeader, h); j != -1 {
+			aCols[i] = j + 1
+			continue
+		}
+		aCols[i] = len(header)
+		header = append(header, h)
+	}
+
+	if _, err := dst.WriteRow(header...); err != nil {
+		return err
+	}
+
+	row := make([]string, len(header))
+	var writeErr error
+	err = d.run(func(c Change) bool {
+		clear(row)
+		row[0] = c.Type.String()
+
+		if c.Type == ChangeRemoved {
+			for i, v := range c.Old {
+				row[aCols[i]] = v
+			}
+		} else {
+			for i, v := range c.New {
+				row[bCols[i]] = v
+			}
+		}
+
+		_, writeErr = dst.WriteRow(row...)
+		return writeErr == nil
+	})
+
+	return errors.Join(err, writeErr)
+}
+
+func newDiffer(a, b Reader, keyColumns []string, options []DiffOption) (*differ, error) {
+	var cfg diffCfg
+	for _, f := range options {
+		f(&cfg)
+	}
+
+	if len(keyColumns) == 0 {
+		return nil, errors.Join(ErrBadConfig, errors.New("no key columns"))
+	}
+
+	if cfg.memoryBudget < 0 {
+		return nil, errors.Join(ErrBadConfig, errors.New("memory budget must be greater than or equal to zero"))
+	}
+
+	if cfg.memoryBudget == 0 {
+		cfg.memoryBudget = defaultSortMemoryBudget
+	}
+
+	d := &differ{
+		a:    a,
+		b:    b,
+		cfg:  cfg,
+		keys: keyColumns,
+	}
+
+	var err error
+	if d.aHeader, d.aKey, err = d.header(a, "a"); err != nil {
+		return nil, err
+	}
+	if d.bHeader, d.bKey, err = d.header(b, "b"); err != nil {
+		return nil, err
+	}
+
+	for j, h := range d.bHeader {
+		if slices.Contains(keyColumns, h) {
+			continue
+		}
+
+		if i := slices.Index(d.aHeader, h); i != -1 {
+			d.shared = append(d.shared, [2]int{i, j})
+		}
+	}
+
+	return d, nil
+}
+
+// header reads the header row of r and resolves the key columns within it.
+func (d *differ) header(r Reader, name string) ([]string, []int, error) {
+	if !r.Scan() {
+		if err := r.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("input " + name + " has no header row")
+	}
+
+	header := slices.Clone(r.Row())
+
+	key := make([]int, len(d.keys))
+	for i, k := range d.keys {
+		idx := slices.Index(header, k)
+		if idx == -1 {
+			return nil, nil, errors.Join(ErrBadConfig, fmt.Errorf("key column %q not found in input %s", k, name))
+		}
+		key[i] = idx
+	}
+
+	return header, key, nil
+}
+
+func (d *differ) run(yield func(Change) bool) error {
+	if d.cfg.sorted {
+		return d.merge(yield)
+	}
+
+	return d.hash(yield)
+}
+
+// checkRow returns an error when row does not have as many fields as the
+// header row of the named input.
+//
+// Every record is checked as it is read so that the key and shared column
+// indexes are always within range.
+func (d *differ) checkRow(row []string, name string) error {
+	n := len(d.aHeader)
+	if name == "b" {
+		n = len(d.bHeader)
+	}
+
+	return checkRowWidth(row, name, n)
+}
+
+func checkRowWidth(row []string, name string, n int) error {
+	if len(row) != n {
+		return fmt.Errorf("input %s: %w: expected %d fields, got %d", name, ErrInvalidFieldCountInRecord, n, len(row))
+	}
+
+	return nil
+}
+
+func keyOf(row []string, key []int) []string {
+	v := make([]string, len(key))
+	for i, idx := range key {
+		v[i] = row[idx]
+	}
+	return v
+}
+
+// encodeKey returns an unambiguous string form of the key column values of
+// row.
+func encodeKey(row []string, key []int) string {
+	var b []byte
+	for _, idx := range key {
+		b = strconv.AppendInt(b, int64(len(row[idx])), 10)
+		b = append(b, ':')
+		b = append(b, row[idx]...)
+	}
+	return string(b)
+}
+
+// compare returns a modified Change when any shared column differs.
+func (d *differ) compare(oldRow, newRow []string) (Change, bool) {
+	var cols []ColumnChange
+	for _, p := range d.shared {
+		if o, n := oldRow[p[0]], newRow[p[1]]; o != n {
+			cols = append(cols, ColumnChange{d.bHeader[p[1]], o, n})
+		}
+	}
+
+	if cols == nil {
+		return Change{}, false
+	}
+
+	return Change{
+		Type:    ChangeModified,
+		Key:     keyOf(newRow, d.bKey),
+		Old:     oldRow,
+		New:     newRow,
+		Columns: cols,
+	}, true
+}
+
+// sortedInput tracks the current record of a sorted document.
+type sortedInput struct {
+	r    Reader
+	name string
+	// width is the number of fields every record must have
+	width int
+	key   []int
+	row   []string
+	cur   []string
+	ok    bool
+	// dups allows consecutive records to share a key
+	dups bool
+}
+
+func (in *sortedInput) next() error {
+	prev := in.cur
+
+	if in.ok = in.r.Scan(); !in.ok {
+		return in.r.Err()
+	}
+
+	if err := checkRowWidth(in.r.Row(), in.name, in.width); err != nil {
+		return err
+	}
+
+	in.row, _ = cloneRow(in.r.Row())
+	in.cur = keyOf(in.row, in.key)
+
+	if prev != nil {
+		switch c := slices.Compare(prev, in.cur); {
+		case c == 0 && !in.dups:
+			return fmt.Errorf("input %s: duplicate key %q", in.name, in.cur)
+		case c > 0:
+			return fmt.Errorf("input %s: not sorted by key at %q", in.name, in.cur)
+		}
+	}
+
+	return nil
+}
+
+// merge compares two documents sorted by key in a single pass.
+func (d *differ) merge(yield func(Change) bool) error {
+	a := sortedInput{r: d.a, name: "a", width: len(d.aHeader), key: d.aKey}
+	b := sortedInput{r: d.b, name: "b", width: len(d.bHeader), key: d.bKey}
+
+	if err := a.next(); err != nil {
+		return err
+	}
+	if err := b.next(); err != nil {
+		return err
+	}
+
+	for a.ok || b.ok {
+		c := 1
+		if !b.ok {
+			c = -1
+		} else if a.ok {
+			c = slices.Compare(a.cur, b.cur)
+		}
+
+		switch {
+		case c < 0:
+			if !yield(Change{Type: ChangeRemoved, Key: a.cur, Old: a.row}) {
+				return nil
+			}
+			if err := a.next(); err != nil {
+				return err
+			}
+		case c > 0:
+			if !yield(Change{Type: ChangeAdded, Key: b.cur, New: b.row}) {
+				return nil
+			}
+			if err := b.next(); err != nil {
+				return err
+			}
+		default:
+			if change, ok := d.compare(a.row, b.row); ok && !yield(change) {
+				return nil
+			}
+			if err := a.next(); err != nil {
+				return err
+			}
+			if err := b.next(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffTable indexes the old records of a document, or of one partition of
+// it, by key.
+type diffTable struct {
+	index   map[string]int
+	rows    [][]string
+	matched []bool
+	size    int64
+}
+
+func newDiffTable() *diffTable {
+	return &diffTable{index: map[string]int{}}
+}
+
+func (t *diffTable) add(row []string, key []int) error {
+	k := encodeKey(row, key)
+	if _, ok := t.index[k]; ok {
+		return fmt.Errorf("input a: duplicate key %q", keyOf(row, key))
+	}
+
+	row, n := cloneRow(row)
+	t.index[k] = len(t.rows)
+	t.rows = append(t.rows, row)
+	t.matched = append(t.matched, false)
+	t.size += int64(n)
+
+	return nil
+}
+
+// hash compares documents which are not sorted by loading the old document
+// into memory, falling back to partitioning both documents into temporary
+// files should it exceed the memory budget.
+func (d *differ) hash(yield func(Change) bool) error {
+	t := newDiffTable()
+	for d.a.Scan() {
+		if err := d.checkRow(d.a.Row(), "a"); err != nil {
+			return err
+		}
+
+		if err := t.add(d.a.Row(), d.aKey); err != nil {
+			return err
+		}
+
+		if t.size > d.cfg.memoryBudget {
+			return d.partitioned(t, yield)
+		}
+	}
+	if err := d.a.Err(); err != nil {
+		return err
+	}
+
+	_, err := d.probe(t, d.b, yield)
+	return err
+}
+
+// probe yields the changes between the old records in t and every record
+// of r, returning false if yield asked to stop.
+func (d *differ) probe(t *diffTable, r Reader, yield func(Change) bool) (bool, error) {
+	added := map[string]struct{}{}
+
+	for r.Scan() {
+		row := r.Row()
+		if err := d.checkRow(row, "b"); err != nil {
+			return false, err
+		}
+
+		k := encodeKey(row, d.bKey)
+
+		i, ok := t.index[k]
+		if !ok {
+			if _, ok := added[k]; ok {
+				return false, fmt.Errorf("input b: duplicate key %q", keyOf(row, d.bKey))
+			}
+			added[k] = struct{}{}
+
+			row, _ = cloneRow(row)
+			if !yield(Change{Type: ChangeAdded, Key: keyOf(row, d.bKey), New: row}) {
+				return false, nil
+			}
+			continue
+		}
+
+		if t.matched[i] {
+			return false, fmt.Errorf("input b: duplicate key %q", keyOf(row, d.bKey))
+		}
+		t.matched[i] = true
+
+		row, _ = cloneRow(row)
+		if change, ok := d.compare(t.rows[i], row); ok && !yield(change) {
+			return false, nil
+		}
+	}
+	if err := r.Err(); err != nil {
+		return false, err
+	}
+
+	for i, row := range t.rows {
+		if t.matched[i] {
+			continue
+		}
+
+		if !yield(Change{Type: ChangeRemoved, Key: keyOf(row, d.aKey), Old: row}) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// partitioned splits the remainder of both documents into partitions by
+// key, starting with the old records already held in t, and compares each
+// pair of partitions in turn.
+func (d *differ) partitioned(t *diffTable, yield func(Change) bool) error {
+	aParts, err := d.createPartitions(0)
+	if err != nil {
+		return err
+	}
+	defer aParts.close()
+
+	bParts, err := d.createPartitions(0)
+	if err != nil {
+		return err
+	}
+	defer bParts.close()
+
+	for _, row := range t.rows {
+		if err := aParts.write(row, d.aKey); err != nil {
+			return err
+		}
+	}
+	for d.a.Scan() {
+		if err := d.checkRow(d.a.Row(), "a"); err != nil {
+			return err
+		}
+
+		if err := aParts.write(d.a.Row(), d.aKey); err != nil {
+			return err
+		}
+	}
+	if err := d.a.Err(); err != nil {
+		return err
+	}
+
+	for d.b.Scan() {
+		if err := d.checkRow(d.b.Row(), "b"); err != nil {
+			return err
+		}
+
+		if err := bParts.write(d.b.Row(), d.bKey); err != nil {
+			return err
+		}
+	}
+	if err := d.b.Err(); err != nil {
+		return err
+	}
+
+	_, err = d.diffPartitions(aParts, bParts, 0, yield)
+	return err
+}
+
+// diffPartitions closes both sets of partitions and compares each pair of
+// partitions in turn, returning false if yield asked to stop.
+func (d *differ) diffPartitions(aParts, bParts *diffPartitions, depth int, yield func(Change) bool) (bool, error) {
+	if err := aParts.close(); err != nil {
+		return false, err
+	}
+	if err := bParts.close(); err != nil {
+		return false, err
+	}
+
+	for i := range diffNumBuckets {
+		ok, err := d.diffPartition(aParts.names[i], bParts.names[i], depth, yield)
+		if err != nil || !ok {
+			return ok, err
+		}
+	}
+
+	return true, nil
+}
+
+// diffPartition compares one pair of partitions by loading the old records
+// into memory, splitting both partitions again should they exceed the
+// memory budget.
+func (d *differ) diffPartition(aName, bName string, depth int, yield func(Change) bool) (bool, error) {
+	af, err := os.Open(aName)
+	if err != nil {
+		return false, err
+	}
+	defer af.Close()
+
+	ar, err := newSpillReader(af)
+	if err != nil {
+		return false, err
+	}
+
+	t := newDiffTable()
+	for ar.Scan() {
+		if err := t.add(ar.Row(), d.aKey); err != nil {
+			return false, err
+		}
+
+		// a single record can never be split
+		if t.size > d.cfg.memoryBudget && len(t.rows) > 1 && depth < diffMaxSplitDepth {
+			return d.splitPartition(aName, bName, depth+1, yield)
+		}
+	}
+	if err := ar.Err(); err != nil {
+		return false, err
+	}
+
+	bf, err := os.Open(bName)
+	if err != nil {
+		return false, err
+	}
+	defer bf.Close()
+
+	br, err := newSpillReader(bf)
+	if err != nil {
+		return false, err
+	}
+
+	return d.probe(t, br, yield)
+}
+
+// splitPartition partitions the records of a pair of partitions again
+// using a hash seeded by depth and compares each resulting pair in turn.
+func (d *differ) splitPartition(aName, bName string, depth int, yield func(Change) bool) (bool, error) {
+	aParts, err := d.createPartitions(depth)
+	if err != nil {
+		return false, err
+	}
+	defer aParts.close()
+
+	bParts, err := d.createPartitions(depth)
+	if err != nil {
+		return false, err
+	}
+	defer bParts.close()
+
+	if err := aParts.copyFrom(aName, d.aKey); err != nil {
+		return false, err
+	}
+	if err := bParts.copyFrom(bName, d.bKey); err != nil {
+		return false, err
+	}
+
+	return d.diffPartitions(aParts, bParts, depth, yield)
+}
+
+// diffPartitions writes records to one of several temporary files selected
+// by a hash of their key.
+type diffPartitions struct {
+	// depth seeds the hash so that records of one partition are spread
+	// across every partition when it is split again
+	depth   int
+	names   []string
+	files   []*os.File
+	bufs    []*bufio.Writer
+	writers []*Writer
+}
+
+func (d *differ) createPartitions(depth int) (*diffPartitions, error) {
+	p := &diffPartitions{depth: depth}
+	for range diffNumBuckets {
+		f, err := os.CreateTemp(d.cfg.tempDir, "csv-diff-*.csv")
+		if err != nil {
+			return nil, errors.Join(err, p.close())
+		}
+		d.runs = append(d.runs, f.Name())
+
+		bw := bufio.NewWriter(f)
+		w, err := newSpillWriter(bw)
+		if err != nil {
+			return nil, errors.Join(err, f.Close(), p.close())
+		}
+
+		p.names = append(p.names, f.Name())
+		p.files = append(p.files, f)
+		p.bufs = append(p.bufs, bw)
+		p.writers = append(p.writers, w)
+	}
+
+	return p, nil
+}
+
+func (p *diffPartitions) write(row []string, key []int) error {
+	h := fnv.New64a()
+	if p.depth > 0 {
+		_, _ = h.Write([]byte{byte(p.depth)})
+	}
+	_, _ = h.Write([]byte(encodeKey(row, key)))
+
+	_, err := p.writers[h.Sum64()%diffNumBuckets].WriteRow(row...)
+	return err
+}
+
+// copyFrom writes every record of the named partition file.
+func (p *diffPartitions) copyFrom(name string, key []int) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := newSpillReader(f)
+	if err != nil {
+		return err
+	}
+
+	for r.Scan() {
+		if err := p.write(r.Row(), key); err != nil {
+			return err
+		}
+	}
+
+	return r.Err()
+}
+
+func (p *diffPartitions) close() error {
+	var errs []error
+	for i, f := range p.files {
+		errs = append(errs, p.writers[i].Close(), p.bufs[i].Flush(), f.Close())
+	}
+	p.files = nil
+
+	return errors.Join(errs...)
+}
+
+func (d *differ) removeRuns() {
+	for _, name := range d.runs {
+		_ = os.Remove(name)
+	}
+}
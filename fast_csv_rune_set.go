@@ -51,6 +51,8 @@
 package csv
 
 import (
+	"encoding/binary"
+	"math/bits"
 	"unicode/utf8"
 )
 
@@ -68,6 +70,96 @@ const (
 	invalidMBStartIdx = -utf8.UTFMax
 )
 
+//
+// SWAR (SIMD within a register) scanning
+//
+// When every single byte rune of a set fits within swarBytes the indexAny*
+// functions test eight bytes per iteration by xor-ing a little endian word of
+// input with each repeated set byte and then detecting any zero byte in the
+// result. The classic zero byte test can flag false positives, but only in
+// bytes more significant than a true zero byte, so the least significant flag
+// of the combined result always identifies the first match.
+//
+// When the set contains multi-byte runes scanning is handed back to the byte
+// at a time logic at the first word containing a non-ascii byte so that
+// multi-byte runes are always decoded exactly as before.
+//
+// Building for amd64 with the csvsimd tag additionally tests sixteen bytes
+// at a time with SSE2 before falling back to the above, see
+// fast_csv_rune_set_simd_amd64.s. No other instruction set or architecture
+// has a vector implementation.
+//
+
+const (
+	// swarMaxBytes is the maximum number of single byte runes that can be
+	// tested with word-at-a-time scanning
+	//
+	// The Reader may add up to eight: field separator, quote, escape,
+	// comment, and the CR, LF, VT, FF runes used for record separator
+	// discovery.
+	swarMaxBytes = 8
+
+	swarLSBs = 0x0101010101010101
+	swarMSBs = 0x8080808080808080
+)
+
+func (rs *runeSetBase) addSWARByte(b byte) {
+	if rs.swarCount == swarMaxBytes {
+		rs.swarEnabled = false
+		return
+	}
+
+	v := swarLSBs * uint64(b)
+	if rs.swarCount == 0 {
+		// unused slots repeat the first byte so that swarMatch never needs
+		// to know how many bytes are in use
+		for i := range rs.swarBytes {
+			rs.swarBytes[i] = v
+		}
+		rs.swarEnabled = true
+	} else {
+		rs.swarBytes[rs.swarCount] = v
+	}
+	rs.swarCount++
+}
+
+// swarMatch returns a word with the most significant bit set for each byte
+// of w that may match a single byte rune of the set.
+//
+// Only the least significant set bit is guaranteed to be an exact match.
+func (rs *runeSetBase) swarMatch(w uint64) uint64 {
+	x0, x1, x2, x3 := w^rs.swarBytes[0], w^rs.swarBytes[1], w^rs.swarBytes[2], w^rs.swarBytes[3]
+	x4, x5, x6, x7 := w^rs.swarBytes[4], w^rs.swarBytes[5], w^rs.swarBytes[6], w^rs.swarBytes[7]
+
+	return (((x0 - swarLSBs) &^ x0) |
+		((x1 - swarLSBs) &^ x1) |
+		((x2 - swarLSBs) &^ x2) |
+		((x3 - swarLSBs) &^ x3) |
+		((x4 - swarLSBs) &^ x4) |
+		((x5 - swarLSBs) &^ x5) |
+		((x6 - swarLSBs) &^ x6) |
+		((x7 - swarLSBs) &^ x7)) & swarMSBs
+}
+
+// swarFirstIndex converts the result of swarMatch to the index of the first
+// matching byte.
+func swarFirstIndex(m uint64) int {
+	return bits.TrailingZeros64(m) >> 3
+}
+
+// swarLoad reads the first eight bytes of p as a little endian word so that
+// the first byte is the least significant.
+func swarLoad(p []byte) uint64 {
+	return binary.LittleEndian.Uint64(p)
+}
+
+// swarLoadString is the string equivalent of swarLoad.
+func swarLoadString(s string) uint64 {
+	_ = s[7] // bounds check hint to compiler
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
 //
 // NOTICE: runeSet4 and runeSet6 implementation is now generated and inflated via a template into gen_strategies.go
 //
@@ -0,0 +1,25 @@
+//go:build amd64 && csvsimd
+
+package csv
+
+// simdEnabled reports whether indexASCIISetSIMD is backed by vector
+// instructions on this platform.
+//
+// Only SSE2 is implemented, it is part of the amd64 baseline so no cpu feature
+// detection is required.
+// It is opt-in via the csvsimd build tag because the call overhead is only
+// recovered when fields are long, otherwise the portable word-at-a-time logic
+// is used.
+const simdEnabled = true
+
+// indexASCIISetSIMD tests sixteen bytes of p per iteration against the
+// repeated single byte runes in set, stopping at the first block of
+// sixteen that contains a match or, when stopOnNonASCII is true, a byte
+// greater than or equal to utf8.RuneSelf.
+//
+// It returns the index of that byte, or n rounded down to a multiple of
+// sixteen should no block contain one. Callers must inspect the byte at the
+// returned index to learn why the scan stopped.
+//
+//go:noescape
+func indexASCIISetSIMD(p *byte, n int, set *[swarMaxBytes]uint64, stopOnNonASCII bool) int
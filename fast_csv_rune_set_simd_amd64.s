@@ -0,0 +1,81 @@
+//go:build amd64 && csvsimd
+
+#include "textflag.h"
+
+// func indexASCIISetSIMD(p *byte, n int, set *[swarMaxBytes]uint64, stopOnNonASCII bool) int
+TEXT ·indexASCIISetSIMD(SB), NOSPLIT, $0-40
+	MOVQ p+0(FP), SI
+	MOVQ n+8(FP), CX
+	MOVQ set+16(FP), DX
+	MOVBQZX stopOnNonASCII+24(FP), R8
+
+	// R8 becomes a mask of the non-ascii bytes to stop on
+	NEGQ R8
+
+	// broadcast each word of the set across a vector register
+	MOVQ 0(DX), X2
+	PUNPCKLQDQ X2, X2
+	MOVQ 8(DX), X3
+	PUNPCKLQDQ X3, X3
+	MOVQ 16(DX), X4
+	PUNPCKLQDQ X4, X4
+	MOVQ 24(DX), X5
+	PUNPCKLQDQ X5, X5
+	MOVQ 32(DX), X6
+	PUNPCKLQDQ X6, X6
+	MOVQ 40(DX), X7
+	PUNPCKLQDQ X7, X7
+	MOVQ 48(DX), X8
+	PUNPCKLQDQ X8, X8
+	MOVQ 56(DX), X9
+	PUNPCKLQDQ X9, X9
+
+	ANDQ $~15, CX
+	XORQ AX, AX
+
+loop:
+	CMPQ AX, CX
+	JAE  done
+
+	MOVOU (SI)(AX*1), X0
+
+	MOVO    X0, X1
+	PCMPEQB X2, X1
+	MOVO    X0, X10
+	PCMPEQB X3, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X4, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X5, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X6, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X7, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X8, X10
+	POR     X10, X1
+	MOVO    X0, X10
+	PCMPEQB X9, X10
+	POR     X10, X1
+
+	PMOVMSKB X1, BX
+	PMOVMSKB X0, DI
+	ANDQ     R8, DI
+	ORQ      DI, BX
+	JNZ      found
+
+	ADDQ $16, AX
+	JMP  loop
+
+found:
+	BSFQ BX, BX
+	ADDQ BX, AX
+
+done:
+	MOVQ AX, ret+32(FP)
+	RET
@@ -0,0 +1,15 @@
+//go:build !amd64 || !csvsimd
+
+package csv
+
+// simdEnabled reports whether indexASCIISetSIMD is backed by vector
+// instructions on this platform.
+//
+// Only amd64 has a vector implementation, SSE2, and it requires the csvsimd
+// build tag. On every other architecture the csvsimd tag has no effect.
+const simdEnabled = false
+
+// indexASCIISetSIMD is never called when simdEnabled is false.
+func indexASCIISetSIMD(_ *byte, _ int, _ *[swarMaxBytes]uint64, _ bool) int {
+	return 0
+}
@@ -0,0 +1,134 @@
+package frictionless
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// directives pairs the strptime directives of date and time formats with
+// the equivalent elements of Go time layouts.
+var directives = []directive{
+	{"%Y", "2006", true},
+	{"%y", "06", true},
+	{"%m", "01", true},
+	{"%d", "02", true},
+	{"%b", "Jan", true},
+	{"%B", "January", true},
+	{"%a", "Mon", true},
+	{"%A", "Monday", true},
+	{"%H", "15", false},
+	{"%I", "03", false},
+	{"%M", "04", false},
+	{"%S", "05", false},
+	{"%f", "000000", false},
+	{"%p", "PM", false},
+	{"%z", "-0700", false},
+	{"%Z", "MST", false},
+}
+
+type directive struct {
+	directive, layout string
+	date              bool
+}
+
+// layoutElements are directives with longer layout elements first along
+// with the zone elements only produced by Go.
+var layoutElements = func() []directive {
+	v := append(slices.Clone(directives),
+		directive{"%z", "Z07:00", false},
+		directive{"%z", "-07:00", false},
+	)
+	slices.SortStableFunc(v, func(a, b directive) int {
+		return len(b.layout) - len(a.layout)
+	})
+	return v
+}()
+
+// isLiteral reports whether r may appear in a format outside of a
+// directive without being mistaken for a layout element.
+func isLiteral(r rune) bool {
+	return r == 'T' || (r < utf8.RuneSelf && !unicode.IsLetter(r) && !unicode.IsDigit(r))
+}
+
+// goLayout converts a strptime date or time format, such as "%d/%m/%Y",
+// into a Go time layout.
+func goLayout(format string) (string, error) {
+	errUnsupported := errors.Join(ErrUnsupported, errors.New("date format "+strconv.Quote(format)+" is not supported"))
+
+	var sb strings.Builder
+	for s := format; s != ""; {
+		if s[0] != '%' {
+			if !isLiteral(rune(s[0])) {
+				return "", errUnsupported
+			}
+			sb.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+
+		if strings.HasPrefix(s, "%%") {
+			sb.WriteByte('%')
+			s = s[2:]
+			continue
+		}
+
+		i := slices.IndexFunc(directives, func(d directive) bool {
+			return strings.HasPrefix(s, d.directive)
+		})
+		if i == -1 {
+			return "", errUnsupported
+		}
+		sb.WriteString(directives[i].layout)
+		s = s[len(directives[i].directive):]
+	}
+
+	return sb.String(), nil
+}
+
+// strptimeFormat converts a Go time layout into a strptime date or time
+// format. It also reports whether the layout has date elements and whether
+// it has time elements.
+func strptimeFormat(layout string) (format string, hasDate, hasTime bool, err error) {
+	var sb strings.Builder
+
+	for s := layout; s != ""; {
+		if strings.HasPrefix(s, ".0") {
+			// fractional seconds of any precision
+			n := len(s) - 1 - len(strings.TrimLeft(s[1:], "0"))
+			sb.WriteString(".%f")
+			s = s[1+n:]
+			hasTime = true
+			continue
+		}
+
+		i := slices.IndexFunc(layoutElements, func(d directive) bool {
+			return strings.HasPrefix(s, d.layout)
+		})
+		if i != -1 {
+			d := layoutElements[i]
+			sb.WriteString(d.directive)
+			s = s[len(d.layout):]
+			if d.date {
+				hasDate = true
+			} else {
+				hasTime = true
+			}
+			continue
+		}
+
+		if !isLiteral(rune(s[0])) {
+			return "", false, false, errors.Join(ErrUnsupported, errors.New("time layout "+strconv.Quote(layout)+" cannot be described"))
+		}
+		if s[0] == '%' {
+			sb.WriteByte('%')
+		}
+		sb.WriteByte(s[0])
+		s = s[1:]
+	}
+
+	return sb.String(), hasDate, hasTime, nil
+}
@@ -0,0 +1,240 @@
+// Package frictionless reads and writes Frictionless Data Package and Table
+// Schema descriptors.
+//
+// A Resource of a Data Package becomes a csv.Reader which decodes the CSV
+// Dialect of the resource and validates each record against its Table
+// Schema, including field types, constraints, missing values, and the
+// primary key. InferSchema and NewSchema produce a Table Schema for a
+// dataset and DescribeWriter the CSV Dialect of a csv.Writer.
+//
+// See https://specs.frictionlessdata.io/.
+package frictionless
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+var (
+	// ErrInvalidDescriptor is returned when a descriptor is not a valid
+	// Data Package, Table Schema, or CSV Dialect.
+	ErrInvalidDescriptor = errors.New("invalid frictionless descriptor")
+	// ErrUnsupported is returned when a descriptor describes a table which
+	// cannot be read or written by this module.
+	ErrUnsupported = errors.New("unsupported frictionless descriptor")
+)
+
+// Package is a Data Package descriptor.
+type Package struct {
+	Name      string     `json:"name,omitempty"`
+	Resources []Resource `json:"resources"`
+}
+
+// Resource is a Data Resource descriptor of a Data Package.
+type Resource struct {
+	Name string `json:"name,omitempty"`
+	// Path locates the data of the resource. Data split into several files
+	// has several paths.
+	Path     Strings `json:"path,omitempty"`
+	Profile  string  `json:"profile,omitempty"`
+	Format   string  `json:"format,omitempty"`
+	Encoding string  `json:"encoding,omitempty"`
+	// Dialect and Schema must be inline descriptors. References to other
+	// descriptors by path or URL are not supported.
+	Dialect *Dialect `json:"dialect,omitempty"`
+	Schema  *Schema  `json:"schema,omitempty"`
+}
+
+type resourceAlias Resource
+
+func (res *Resource) UnmarshalJSON(b []byte) error {
+	v := struct {
+		*resourceAlias
+		Dialect json.RawMessage `json:"dialect"`
+		Schema  json.RawMessage `json:"schema"`
+	}{resourceAlias: (*resourceAlias)(res)}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	for _, p := range [...]struct {
+		name string
+		raw  json.RawMessage
+		dst  any
+	}{
+		{"dialect", v.Dialect, &res.Dialect},
+		{"schema", v.Schema, &res.Schema},
+	} {
+		if bytes.HasPrefix(p.raw, []byte(`"`)) {
+			return errors.Join(ErrUnsupported, errors.New(p.name+" references are not supported"))
+		}
+		if p.raw != nil {
+			if err := unmarshal(p.raw, p.dst); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Dialect is a CSV Dialect descriptor. A nil field takes the default value
+// defined by the CSV Dialect specification.
+type Dialect struct {
+	Delimiter      *string `json:"delimiter,omitempty"`
+	LineTerminator *string `json:"lineTerminator,omitempty"`
+	// QuoteChar is an empty string when fields are never quoted.
+	QuoteChar           *string `json:"quoteChar,omitempty"`
+	DoubleQuote         *bool   `json:"doubleQuote,omitempty"`
+	EscapeChar          *string `json:"escapeChar,omitempty"`
+	NullSequence        *string `json:"nullSequence,omitempty"`
+	SkipInitialSpace    *bool   `json:"skipInitialSpace,omitempty"`
+	Header              *bool   `json:"header,omitempty"`
+	CommentChar         *string `json:"commentChar,omitempty"`
+	CaseSensitiveHeader *bool   `json:"caseSensitiveHeader,omitempty"`
+	CSVDDFVersion       string  `json:"csvddfVersion,omitempty"`
+}
+
+// Schema is a Table Schema descriptor.
+type Schema struct {
+	Fields []Field `json:"fields"`
+	// MissingValues defaults to the empty string. An empty, non-nil slice
+	// means no value is missing.
+	MissingValues []string `json:"missingValues,omitzero"`
+	PrimaryKey    Strings  `json:"primaryKey,omitempty"`
+}
+
+// Field describes a column of a Table Schema.
+type Field struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type defaults to "string".
+	Type string `json:"type,omitempty"`
+	// Format is "default", "any", or a type specific format such as
+	// "email" for strings and a strptime pattern such as "%d/%m/%Y" for
+	// dates and times.
+	Format      string       `json:"format,omitempty"`
+	Constraints *Constraints `json:"constraints,omitempty"`
+	TrueValues  []string     `json:"trueValues,omitempty"`
+	FalseValues []string     `json:"falseValues,omitempty"`
+	BareNumber  *bool        `json:"bareNumber,omitempty"`
+	DecimalChar string       `json:"decimalChar,omitempty"`
+	GroupChar   string       `json:"groupChar,omitempty"`
+}
+
+// Constraints restrict the values of a Field.
+//
+// Minimum, Maximum, and the elements of Enum hold values of the type of the
+// field as decoded from JSON: a string, a bool, or a json.Number when
+// decoded by LoadPackage or LoadSchema. A float64 is also accepted.
+type Constraints struct {
+	Required  bool   `json:"required,omitempty"`
+	Unique    bool   `json:"unique,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Minimum   any    `json:"minimum,omitempty"`
+	Maximum   any    `json:"maximum,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Enum      []any  `json:"enum,omitempty"`
+}
+
+// Strings is a property which may be encoded as a single string or an array
+// of strings. It is encoded as a single string when it holds one value.
+type Strings = descriptor.Strings
+
+// unmarshal decodes b into v keeping numbers in their lexical form.
+func unmarshal(b []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+// LoadPackage decodes a Data Package descriptor and verifies that every
+// tabular resource, those with a schema or the "tabular-data-resource"
+// profile, can be read with the options returned by ReaderOptions.
+func LoadPackage(r io.Reader) (*Package, error) {
+	var p Package
+	if err := decode(r, &p); err != nil {
+		return nil, err
+	}
+
+	for i := range p.Resources {
+		res := &p.Resources[i]
+		if res.Schema == nil && res.Profile != "tabular-data-resource" {
+			continue
+		}
+
+		if err := res.validate(); err != nil {
+			return nil, fmt.Errorf("resource %q: %w", res.Name, err)
+		}
+	}
+
+	return &p, nil
+}
+
+// LoadSchema decodes a Table Schema descriptor and verifies that a CSV
+// document with a header row can be read with it.
+func LoadSchema(r io.Reader) (*Schema, error) {
+	var s Schema
+	if err := decode(r, &s); err != nil {
+		return nil, err
+	}
+
+	if err := (&Resource{Schema: &s}).validate(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func decode(r io.Reader, v any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if err := unmarshal(b, v); err != nil {
+		if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrInvalidDescriptor) {
+			return err
+		}
+		return errors.Join(ErrInvalidDescriptor, err)
+	}
+
+	return nil
+}
+
+// Resource returns the resource of the package with the given name or nil
+// when there is none.
+func (p *Package) Resource(name string) *Resource {
+	for i := range p.Resources {
+		if p.Resources[i].Name == name {
+			return &p.Resources[i]
+		}
+	}
+
+	return nil
+}
+
+// validate creates a reader for an empty document since the reader checks
+// the combination of options up front.
+func (res *Resource) validate() error {
+	cr, err := res.NewReader(strings.NewReader(""))
+	if err != nil {
+		if errors.Is(err, csv.ErrBadConfig) {
+			return errors.Join(ErrUnsupported, err)
+		}
+		return err
+	}
+
+	return cr.Close()
+}
@@ -0,0 +1,345 @@
+package frictionless
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+// NewReader returns a reader of the data of res read from r. Further
+// options are applied after those returned by ReaderOptions.
+func (res *Resource) NewReader(r io.Reader, opts ...csv.ReaderOption) (csv.Reader, error) {
+	resOpts, err := res.ReaderOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return csv.NewReader(append(append(resOpts, csv.ReaderOpts().Reader(r)), opts...)...)
+}
+
+// ReaderOptions returns the options which read the data of res.
+//
+// The header row, when present, is removed and must hold the name of each
+// field in order, ignoring case unless caseSensitiveHeader is set. Every
+// record is validated against the schema with csv.ReaderOpts().Schema: the
+// type, format, and constraints of each field, the missing values of the
+// schema along with the nullSequence of the dialect, and the primary key.
+//
+// Fields of the object, array, geopoint, and geojson types are only
+// validated by their constraints. Numbers must be bare numbers using "." as
+// the decimal separator without group separators, and skipInitialSpace is
+// not supported.
+func (res *Resource) ReaderOptions() ([]csv.ReaderOption, error) {
+	if res.Format != "" && !strings.EqualFold(res.Format, "csv") {
+		return nil, errors.Join(ErrUnsupported, errors.New("format must be csv"))
+	}
+
+	switch strings.ToLower(res.Encoding) {
+	case "", "utf-8", "utf8", "utf-8-sig":
+	default:
+		return nil, errors.Join(ErrUnsupported, errors.New("encoding must be utf-8"))
+	}
+
+	var d Dialect
+	if res.Dialect != nil {
+		d = *res.Dialect
+	}
+
+	opts := []csv.ReaderOption{csv.ReaderOpts().RemoveByteOrderMarker(true)}
+
+	if d.Delimiter != nil {
+		r, err := descriptor.SingleRune(ErrUnsupported, "delimiter", *d.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, csv.ReaderOpts().FieldSeparator(r))
+	}
+
+	if lt := d.LineTerminator; lt == nil || *lt == "\r\n" || *lt == "\n" {
+		opts = append(opts, csv.ReaderOpts().DiscoverRecordSeparator(true))
+	} else {
+		opts = append(opts, csv.ReaderOpts().RecordSeparator(*lt))
+	}
+
+	quote := "\""
+	if d.QuoteChar != nil {
+		quote = *d.QuoteChar
+	}
+	if quote != "" {
+		r, err := descriptor.SingleRune(ErrUnsupported, "quoteChar", quote)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, csv.ReaderOpts().Quote(r))
+	}
+
+	if d.EscapeChar != nil {
+		r, err := descriptor.SingleRune(ErrUnsupported, "escapeChar", *d.EscapeChar)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, csv.ReaderOpts().Escape(r))
+	}
+
+	if d.CommentChar != nil {
+		r, err := descriptor.SingleRune(ErrUnsupported, "commentChar", *d.CommentChar)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			csv.ReaderOpts().Comment(r),
+			csv.ReaderOpts().CommentsAllowedAfterStartOfRecords(true),
+		)
+	}
+
+	if d.SkipInitialSpace != nil && *d.SkipInitialSpace {
+		return nil, errors.Join(ErrUnsupported, errors.New("skipInitialSpace must be false"))
+	}
+
+	var fields []Field
+	if res.Schema != nil {
+		fields = res.Schema.Fields
+	}
+
+	if d.Header == nil || *d.Header {
+		if len(fields) > 0 {
+			names := make([]string, len(fields))
+			for i, f := range fields {
+				names[i] = f.Name
+			}
+			opts = append(opts, csv.ReaderOpts().ExpectHeaders(names...))
+
+			if d.CaseSensitiveHeader == nil || !*d.CaseSensitiveHeader {
+				opts = append(opts, csv.ReaderOpts().CaseInsensitiveHeaders(true))
+			}
+		}
+		opts = append(opts, csv.ReaderOpts().RemoveHeaderRow(true))
+	}
+
+	if res.Schema != nil {
+		s, err := res.Schema.csvSchema(d.NullSequence)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, csv.ReaderOpts().Schema(s))
+	}
+
+	return opts, nil
+}
+
+// csvSchema converts s into a csv.Schema. nullSequence, when not nil, is
+// an additional missing value.
+func (s *Schema) csvSchema(nullSequence *string) (csv.Schema, error) {
+	var missing []string
+	if s.MissingValues != nil || nullSequence != nil {
+		missing = s.MissingValues
+		if missing == nil {
+			missing = []string{""}
+		}
+		if nullSequence != nil {
+			missing = append(missing[:len(missing):len(missing)], *nullSequence)
+		}
+	}
+
+	cs := csv.Schema{
+		Columns:    make([]csv.ColumnSchema, len(s.Fields)),
+		PrimaryKey: s.PrimaryKey,
+	}
+	for i, f := range s.Fields {
+		c, err := f.columnSchema()
+		if err != nil {
+			return cs, fmt.Errorf("field %d: %w", i+1, err)
+		}
+		c.MissingValues = missing
+		cs.Columns[i] = c
+	}
+
+	for _, name := range s.PrimaryKey {
+		if !slices.ContainsFunc(s.Fields, func(f Field) bool { return f.Name == name }) {
+			return cs, errors.Join(ErrInvalidDescriptor, errors.New("primary key field "+strconv.Quote(name)+" not found"))
+		}
+	}
+
+	return cs, nil
+}
+
+// stringFormats are the patterns of the formats of the string type.
+var stringFormats = map[string]*regexp.Regexp{
+	"email":  regexp.MustCompile(`^[^@\s]+@[^@\s]+$`),
+	"uri":    regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:\S*$`),
+	"uuid":   regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`),
+	"binary": regexp.MustCompile(`^[A-Za-z0-9+/]*={0,2}$`),
+}
+
+// isoDuration matches the ISO 8601 durations of the duration type.
+var isoDuration = regexp.MustCompile(`^-?P(?:[0-9]+Y)?(?:[0-9]+M)?(?:[0-9]+D)?(?:T(?:[0-9]+H)?(?:[0-9]+M)?(?:[0-9]+(?:\.[0-9]+)?S)?)?$`)
+
+// defaultLayouts are the time layouts of the default format of the date and
+// time types.
+var defaultLayouts = map[string]string{
+	"date":      time.DateOnly,
+	"time":      time.TimeOnly,
+	"datetime":  time.RFC3339,
+	"year":      "2006",
+	"yearmonth": "2006-01",
+}
+
+var (
+	defaultTrueValues  = []string{"true", "True", "TRUE", "1"}
+	defaultFalseValues = []string{"false", "False", "FALSE", "0"}
+)
+
+// columnSchema converts the type, format, and constraints of f into a
+// csv.ColumnSchema.
+func (f Field) columnSchema() (csv.ColumnSchema, error) {
+	cs := csv.ColumnSchema{Name: f.Name}
+
+	if f.Name == "" {
+		return cs, errors.Join(ErrInvalidDescriptor, errors.New("name is required"))
+	}
+
+	format := strings.TrimPrefix(f.Format, "fmt:")
+	if format == "default" {
+		format = ""
+	}
+
+	switch f.Type {
+	case "", "string":
+		if format != "" {
+			p, ok := stringFormats[format]
+			if !ok {
+				return cs, errors.Join(ErrUnsupported, errors.New("string format "+strconv.Quote(format)+" is not supported"))
+			}
+			cs.Pattern = p
+		}
+	case "integer", "number":
+		cs.Type = csv.ColumnInt64
+		if f.Type == "number" {
+			cs.Type = csv.ColumnFloat64
+		}
+		if (f.BareNumber != nil && !*f.BareNumber) || (f.DecimalChar != "" && f.DecimalChar != ".") || f.GroupChar != "" {
+			return cs, errors.Join(ErrUnsupported, errors.New("numbers must be bare numbers using . as the decimal separator"))
+		}
+	case "boolean":
+		cs.Enum = slices.Concat(trueValues(f), falseValues(f))
+	case "date", "time", "datetime", "year", "yearmonth":
+		if format == "any" {
+			break
+		}
+
+		cs.Type = csv.ColumnTime
+		cs.Layout = defaultLayouts[f.Type]
+		if format != "" {
+			var err error
+			if cs.Layout, err = goLayout(format); err != nil {
+				return cs, err
+			}
+		}
+	case "duration":
+		cs.Pattern = isoDuration
+	case "any", "object", "array", "geopoint", "geojson":
+	default:
+		return cs, errors.Join(ErrUnsupported, errors.New("type "+strconv.Quote(f.Type)+" is not supported"))
+	}
+
+	c := f.Constraints
+	if c == nil {
+		return cs, nil
+	}
+
+	cs.Required = c.Required
+	cs.Unique = c.Unique
+	if c.MinLength != nil {
+		cs.MinLength = *c.MinLength
+	}
+	if c.MaxLength != nil {
+		cs.MaxLength = *c.MaxLength
+	}
+
+	var err error
+	if cs.Minimum, err = lexical(c.Minimum); err != nil {
+		return cs, err
+	}
+	if cs.Maximum, err = lexical(c.Maximum); err != nil {
+		return cs, err
+	}
+
+	if c.Pattern != "" {
+		if cs.Pattern != nil {
+			return cs, errors.Join(ErrUnsupported, errors.New("a pattern cannot be combined with format "+strconv.Quote(format)))
+		}
+		if cs.Pattern, err = regexp.Compile(`^(?:` + c.Pattern + `)$`); err != nil {
+			return cs, errors.Join(ErrInvalidDescriptor, fmt.Errorf("pattern: %w", err))
+		}
+	}
+
+	if c.Enum != nil {
+		var enum []string
+		for _, v := range c.Enum {
+			s, err := lexical(v)
+			if err != nil {
+				return cs, err
+			}
+
+			// boolean enums hold logical values which allow each of
+			// their lexical forms
+			switch {
+			case f.Type != "boolean":
+				enum = append(enum, s)
+			case s == "true":
+				enum = append(enum, trueValues(f)...)
+			case s == "false":
+				enum = append(enum, falseValues(f)...)
+			default:
+				return cs, errors.Join(ErrInvalidDescriptor, errors.New("boolean enum values must be true or false"))
+			}
+		}
+		if len(enum) == 0 {
+			return cs, errors.Join(ErrUnsupported, errors.New("enum cannot be empty"))
+		}
+		cs.Enum = enum
+	}
+
+	return cs, nil
+}
+
+func trueValues(f Field) []string {
+	if f.TrueValues == nil {
+		return defaultTrueValues
+	}
+	return f.TrueValues
+}
+
+func falseValues(f Field) []string {
+	if f.FalseValues == nil {
+		return defaultFalseValues
+	}
+	return f.FalseValues
+}
+
+// lexical returns the text form of a constraint value.
+func lexical(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+
+	return "", errors.Join(ErrInvalidDescriptor, fmt.Errorf("constraint value %v is not a string, number, or boolean", v))
+}
@@ -0,0 +1,207 @@
+package frictionless
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/internal/descriptor"
+)
+
+// InferSchema returns the Table Schema of a dataset guessed by
+// csv.InferSchema from up to sampleRows records of r following its header
+// row.
+//
+// Only field types are inferred. No constraints are published since a
+// sample cannot establish what every value must satisfy, so uint64 columns
+// are described as integers without a minimum.
+func InferSchema(r csv.Reader, sampleRows int) (*Schema, error) {
+	s, err := csv.InferSchema(r, sampleRows)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSchema(s, true)
+}
+
+// NewSchema returns the Table Schema which validates the same values as s.
+//
+// Bools are described as booleans written as 1 or 0, durations as strings,
+// and numeric columns whose values have leading zeros as strings so the
+// zeros are kept by consumers. Every column must have the same
+// MissingValues since missing values apply to a whole Table Schema.
+//
+// Constraints are only described for what s sets explicitly, along with a
+// minimum of zero for uint64 columns. ColumnSchema.Observed is ignored.
+func NewSchema(s csv.Schema) (*Schema, error) {
+	return newSchema(s, false)
+}
+
+// newSchema describes s where inferred reports that the column types were
+// guessed from a sample rather than chosen by the caller.
+func newSchema(s csv.Schema, inferred bool) (*Schema, error) {
+	fs := &Schema{
+		Fields:     make([]Field, len(s.Columns)),
+		PrimaryKey: Strings(s.PrimaryKey),
+	}
+
+	for i, cs := range s.Columns {
+		if i > 0 && !slices.Equal(cs.MissingValues, s.Columns[0].MissingValues) {
+			return nil, errors.Join(ErrUnsupported, errors.New("every column must have the same missing values"))
+		}
+
+		f, err := newField(cs, inferred)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", i+1, err)
+		}
+		fs.Fields[i] = f
+	}
+
+	if len(s.Columns) > 0 && s.Columns[0].MissingValues != nil {
+		fs.MissingValues = slices.Clone(s.Columns[0].MissingValues)
+	}
+
+	return fs, nil
+}
+
+// timeFieldTypes are the field types of the time layouts which are the
+// default format of a type.
+var timeFieldTypes = map[string]string{
+	time.RFC3339Nano: "datetime",
+	time.RFC3339:     "datetime",
+	time.DateOnly:    "date",
+	time.TimeOnly:    "time",
+	"2006":           "year",
+	"2006-01":        "yearmonth",
+}
+
+func newField(cs csv.ColumnSchema, inferred bool) (Field, error) {
+	f := Field{Name: cs.Name, Type: "string"}
+
+	if cs.Name == "" {
+		return f, errors.Join(ErrUnsupported, errors.New("column has no name"))
+	}
+
+	numeric := false
+	switch cs.Type {
+	case csv.ColumnString, csv.ColumnDuration:
+	case csv.ColumnBool:
+		f.Type = "boolean"
+		f.TrueValues = []string{"1"}
+		f.FalseValues = []string{"0"}
+	case csv.ColumnInt64, csv.ColumnUint64:
+		f.Type = "integer"
+		numeric = true
+	case csv.ColumnDecimal, csv.ColumnFloat64:
+		f.Type = "number"
+		numeric = true
+	case csv.ColumnTime:
+		layout := cmp.Or(cs.Layout, time.RFC3339Nano)
+		if t, ok := timeFieldTypes[layout]; ok {
+			f.Type = t
+			break
+		}
+
+		format, hasDate, hasTime, err := strptimeFormat(layout)
+		if err != nil {
+			return f, err
+		}
+		f.Format = format
+		switch {
+		case hasDate && hasTime:
+			f.Type = "datetime"
+		case hasDate:
+			f.Type = "date"
+		default:
+			f.Type = "time"
+		}
+	default:
+		return f, errors.Join(csv.ErrBadConfig, errors.New("invalid column type"))
+	}
+
+	if numeric && cs.Observed.LeadingZeros {
+		f.Type = "string"
+		numeric = false
+	}
+
+	c := Constraints{
+		Required: cs.Required,
+		Unique:   cs.Unique,
+	}
+	if cs.MinLength > 0 {
+		c.MinLength = &cs.MinLength
+	}
+	if cs.MaxLength > 0 {
+		c.MaxLength = &cs.MaxLength
+	}
+
+	value := func(s string) any {
+		if numeric {
+			return json.Number(s)
+		}
+		return s
+	}
+
+	minimum := cs.Minimum
+	if minimum == "" && cs.Type == csv.ColumnUint64 && numeric && !inferred {
+		minimum = "0"
+	}
+	if minimum != "" {
+		c.Minimum = value(minimum)
+	}
+	if cs.Maximum != "" {
+		c.Maximum = value(cs.Maximum)
+	}
+
+	if cs.Pattern != nil {
+		if f.Type != "string" {
+			return f, errors.Join(ErrUnsupported, errors.New("patterns of "+cs.Type.String()+" columns cannot be described"))
+		}
+		c.Pattern = cs.Pattern.String()
+	}
+
+	if len(cs.Enum) > 0 {
+		if cs.Type == csv.ColumnBool {
+			return f, errors.Join(ErrUnsupported, errors.New("enums of bool columns cannot be described"))
+		}
+		c.Enum = make([]any, len(cs.Enum))
+		for i, v := range cs.Enum {
+			c.Enum[i] = value(v)
+		}
+	}
+
+	if c.Required || c.Unique || c.MinLength != nil || c.MaxLength != nil || c.Minimum != nil || c.Maximum != nil || c.Pattern != "" || c.Enum != nil {
+		f.Constraints = &c
+	}
+
+	return f, nil
+}
+
+// DescribeWriter returns the CSV Dialect of the documents w writes.
+//
+// Documents are assumed to have a header row, set Header to false otherwise.
+func DescribeWriter(w *csv.Writer) *Dialect {
+	cd := w.Dialect()
+
+	d := &Dialect{
+		Delimiter:      descriptor.Ptr(string(cd.FieldSeparator)),
+		LineTerminator: descriptor.Ptr(cd.RecordSeparator),
+		QuoteChar:      descriptor.Ptr(string(cd.Quote)),
+		DoubleQuote:    descriptor.Ptr(cd.Escape == 0),
+		CSVDDFVersion:  "1.2",
+	}
+
+	if cd.Escape != 0 {
+		d.EscapeChar = descriptor.Ptr(string(cd.Escape))
+	}
+
+	if cd.Comment != 0 {
+		d.CommentChar = descriptor.Ptr(string(cd.Comment))
+	}
+
+	return d
+}
@@ -0,0 +1,368 @@
+package frictionless_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/frictionless"
+	"github.com/stretchr/testify/assert"
+)
+
+// readResource reads every record of doc as the named resource of the
+// package descriptor.
+func readResource(t *testing.T, pkg, name, doc string) ([][]string, error) {
+	t.Helper()
+
+	p, err := frictionless.LoadPackage(strings.NewReader(pkg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := p.Resource(name)
+	if res == nil {
+		t.Fatalf("resource %q not found", name)
+	}
+
+	cr, err := res.NewReader(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cr.Close()
+
+	var rows [][]string
+	for cr.Scan() {
+		rows = append(rows, append([]string(nil), cr.Row()...))
+	}
+
+	return rows, cr.Err()
+}
+
+const testPackage = `{
+	"name": "members",
+	"resources": [
+		{"name": "readme", "path": "README.md"},
+		{
+			"name": "members",
+			"path": ["members-1.csv", "members-2.csv"],
+			"profile": "tabular-data-resource",
+			"format": "csv",
+			"encoding": "utf-8",
+			"dialect": {"delimiter": ";", "commentChar": "#", "nullSequence": "-"},
+			"schema": {
+				"fields": [
+					{"name": "id", "type": "integer", "constraints": {"minimum": 1}},
+					{"name": "email", "type": "string", "format": "email", "constraints": {"unique": true}},
+					{"name": "active", "type": "boolean", "trueValues": ["Y"], "falseValues": ["N"]},
+					{"name": "joined", "type": "date", "format": "%d/%m/%Y"},
+					{"name": "score", "type": "number", "constraints": {"minimum": 0, "maximum": 100}},
+					{"name": "level", "type": "string", "constraints": {"enum": ["gold", "silver"]}}
+				],
+				"missingValues": ["", "NA"],
+				"primaryKey": "id"
+			}
+		}
+	]
+}`
+
+func TestFunctionalResourceReader(t *testing.T) {
+	t.Parallel()
+
+	const header = "ID;Email;Active;Joined;Score;Level\n"
+
+	tcs := []struct {
+		when   string
+		doc    string
+		rows   [][]string
+		errIs  []error
+		errStr string
+	}{
+		{
+			when: "every record satisfies the schema",
+			doc:  "\ufeff" + header + "# a comment\n1;a@x.io;Y;02/01/2024;99.5;gold\n2;b@x.io;N;NA;-;\n",
+			rows: [][]string{{"1", "a@x.io", "Y", "02/01/2024", "99.5", "gold"}, {"2", "b@x.io", "N", "NA", "-", ""}},
+		},
+		{
+			when:  "the header row does not match the field names",
+			doc:   "id;mail;active;joined;score;level\n",
+			errIs: []error{csv.ErrUnexpectedHeaderRowContents},
+		},
+		{
+			when:   "a primary key repeats",
+			doc:    header + "1;a@x.io;Y;;;\n1;b@x.io;Y;;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaUnique},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 63, record 3, field 1: column \"id\": " + csv.ErrSchemaUnique.Error() + ": primary key",
+		},
+		{
+			when:   "a primary key is missing",
+			doc:    header + "NA;a@x.io;Y;;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRequired},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 50, record 2, field 1: column \"id\": " + csv.ErrSchemaRequired.Error(),
+		},
+		{
+			when:   "a unique field repeats",
+			doc:    header + "1;a@x.io;Y;;;\n2;a@x.io;Y;;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaUnique},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 63, record 3, field 2: column \"email\": " + csv.ErrSchemaUnique.Error(),
+		},
+		{
+			when:  "a field does not match its format",
+			doc:   header + "1;ax.io;Y;;;\n",
+			errIs: []error{csv.ErrSchemaViolation, csv.ErrSchemaPattern},
+		},
+		{
+			when:   "a boolean is not one of its values",
+			doc:    header + "1;;true;;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaEnum},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 46, record 2, field 3: column \"active\": " + csv.ErrSchemaEnum.Error(),
+		},
+		{
+			when:   "a date does not match its format",
+			doc:    header + "1;;;2024-01-02;;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 52, record 2, field 4: column \"joined\": " + csv.ErrSchemaType.Error() + ": time",
+		},
+		{
+			when:   "a number is out of range",
+			doc:    header + "1;;;;100.5;\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRange},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 47, record 2, field 5: column \"score\": " + csv.ErrSchemaRange.Error() + ": greater than maximum 100",
+		},
+		{
+			when:   "a field is not one of its enum values",
+			doc:    header + "1;;;;;bronze\n",
+			errIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaEnum},
+			errStr: csv.ErrSchemaViolation.Error() + " at byte 48, record 2, field 6: column \"level\": " + csv.ErrSchemaEnum.Error(),
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			rows, err := readResource(t, testPackage, "members", tc.doc)
+
+			if tc.errIs == nil {
+				is.Nil(err)
+				is.Equal(tc.rows, rows)
+				return
+			}
+
+			for _, target := range tc.errIs {
+				is.ErrorIs(err, target)
+			}
+			if tc.errStr != "" {
+				is.Equal(tc.errStr, err.Error())
+			}
+		})
+	}
+}
+
+func TestFunctionalResourceReaderDialect(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	const pkg = `{"resources": [{
+		"name": "r",
+		"dialect": {"header": false, "quoteChar": "'", "escapeChar": "\\", "lineTerminator": "|"},
+		"schema": {"fields": [{"name": "a"}, {"name": "b", "type": "boolean", "constraints": {"enum": [true]}}]}
+	}]}`
+
+	rows, err := readResource(t, pkg, "r", `'x\'y',True|z,1|`)
+	is.Nil(err)
+	is.Equal([][]string{{"x'y", "True"}, {"z", "1"}}, rows)
+
+	_, err = readResource(t, pkg, "r", "z,false|")
+	is.ErrorIs(err, csv.ErrSchemaEnum)
+}
+
+func TestFunctionalLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		pkg    string
+		errIs  error
+		errStr string
+	}{
+		{
+			when:   "the descriptor is not json",
+			pkg:    `[`,
+			errIs:  frictionless.ErrInvalidDescriptor,
+			errStr: frictionless.ErrInvalidDescriptor.Error() + "\nunexpected EOF",
+		},
+		{
+			when:   "a schema is a reference",
+			pkg:    `{"resources": [{"name": "r", "schema": "schema.json"}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: frictionless.ErrUnsupported.Error() + "\nschema references are not supported",
+		},
+		{
+			when:   "the format is not csv",
+			pkg:    `{"resources": [{"name": "r", "format": "xlsx", "schema": {"fields": []}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": " + frictionless.ErrUnsupported.Error() + "\nformat must be csv",
+		},
+		{
+			when:   "the encoding is not utf-8",
+			pkg:    `{"resources": [{"name": "r", "encoding": "latin1", "profile": "tabular-data-resource"}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": " + frictionless.ErrUnsupported.Error() + "\nencoding must be utf-8",
+		},
+		{
+			when:   "the delimiter is several characters",
+			pkg:    `{"resources": [{"name": "r", "dialect": {"delimiter": "::"}, "schema": {"fields": []}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": " + frictionless.ErrUnsupported.Error() + "\ndelimiter must be a single character",
+		},
+		{
+			when:   "initial spaces are skipped",
+			pkg:    `{"resources": [{"name": "r", "dialect": {"skipInitialSpace": true}, "schema": {"fields": []}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": " + frictionless.ErrUnsupported.Error() + "\nskipInitialSpace must be false",
+		},
+		{
+			when:   "a field type is unknown",
+			pkg:    `{"resources": [{"name": "r", "schema": {"fields": [{"name": "a", "type": "money"}]}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": field 1: " + frictionless.ErrUnsupported.Error() + "\ntype \"money\" is not supported",
+		},
+		{
+			when:   "a field has no name",
+			pkg:    `{"resources": [{"name": "r", "schema": {"fields": [{"type": "integer"}]}}]}`,
+			errIs:  frictionless.ErrInvalidDescriptor,
+			errStr: "resource \"r\": field 1: " + frictionless.ErrInvalidDescriptor.Error() + "\nname is required",
+		},
+		{
+			when:   "a number has a group separator",
+			pkg:    `{"resources": [{"name": "r", "schema": {"fields": [{"name": "a", "type": "number", "groupChar": ","}]}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": field 1: " + frictionless.ErrUnsupported.Error() + "\nnumbers must be bare numbers using . as the decimal separator",
+		},
+		{
+			when:   "a date format has unsupported directives",
+			pkg:    `{"resources": [{"name": "r", "schema": {"fields": [{"name": "a", "type": "date", "format": "%j"}]}}]}`,
+			errIs:  frictionless.ErrUnsupported,
+			errStr: "resource \"r\": field 1: " + frictionless.ErrUnsupported.Error() + "\ndate format \"%j\" is not supported",
+		},
+		{
+			when:   "the primary key names an unknown field",
+			pkg:    `{"resources": [{"name": "r", "schema": {"fields": [{"name": "a"}], "primaryKey": ["a", "b"]}}]}`,
+			errIs:  frictionless.ErrInvalidDescriptor,
+			errStr: "resource \"r\": " + frictionless.ErrInvalidDescriptor.Error() + "\nprimary key field \"b\" not found",
+		},
+		{
+			when:  "the reader rejects the combination of options",
+			pkg:   `{"resources": [{"name": "r", "dialect": {"delimiter": "\""}, "schema": {"fields": []}}]}`,
+			errIs: csv.ErrBadConfig,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			p, err := frictionless.LoadPackage(strings.NewReader(tc.pkg))
+			is.Nil(p)
+			is.ErrorIs(err, tc.errIs)
+			if tc.errStr != "" {
+				is.Equal(tc.errStr, err.Error())
+			}
+		})
+	}
+}
+
+func TestFunctionalLoadSchema(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	s, err := frictionless.LoadSchema(strings.NewReader(`{"fields": [{"name": "n", "type": "integer", "constraints": {"enum": [1, 2]}}], "missingValues": []}`))
+	is.Nil(err)
+	is.Equal([]any{json.Number("1"), json.Number("2")}, s.Fields[0].Constraints.Enum)
+	is.Equal([]string{}, s.MissingValues)
+
+	cr, err := (&frictionless.Resource{Schema: s}).NewReader(strings.NewReader("n\n1\n\n"))
+	is.Nil(err)
+	defer cr.Close()
+
+	is.True(cr.Scan())
+	is.Equal([]string{"1"}, cr.Row())
+	is.False(cr.Scan())
+	is.ErrorIs(cr.Err(), csv.ErrSchemaType)
+
+	_, err = frictionless.LoadSchema(strings.NewReader(`{"fields": [{"name": "a", "constraints": {"minimum": [1]}}]}`))
+	is.ErrorIs(err, frictionless.ErrInvalidDescriptor)
+}
+
+func TestFunctionalExport(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	opts := []csv.WriterOption{
+		csv.WriterOpts().FieldSeparator('|'),
+		csv.WriterOpts().Escape('\\'),
+		csv.WriterOpts().RecordSeparator("\r\n"),
+	}
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(append(opts, csv.WriterOpts().Writer(&buf))...)
+	is.Nil(err)
+	for _, row := range [][]string{
+		{"id", "zip", "ok", "when", "price", "note"},
+		{"1", "01234", "1", "2024-01-02", "1.5", "a \"quoted\" note"},
+		{"2", "98765", "0", "2024-02-03", "-2", ""},
+	} {
+		_, err = cw.WriteRow(row...)
+		is.Nil(err)
+	}
+	is.Nil(cw.Close())
+
+	d := frictionless.DescribeWriter(cw)
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(bytes.NewReader(buf.Bytes())),
+		csv.ReaderOpts().FieldSeparator('|'),
+		csv.ReaderOpts().Quote('"'),
+		csv.ReaderOpts().Escape('\\'),
+		csv.ReaderOpts().RecordSeparator("\r\n"),
+	)
+	is.Nil(err)
+	s, err := frictionless.InferSchema(cr, 0)
+	is.Nil(err)
+	is.Nil(cr.Close())
+
+	s.PrimaryKey = frictionless.Strings{"id"}
+
+	b, err := json.Marshal(frictionless.Resource{Name: "data", Path: frictionless.Strings{"data.csv"}, Dialect: d, Schema: s})
+	is.Nil(err)
+	is.JSONEq(`{
+		"name": "data",
+		"path": "data.csv",
+		"dialect": {"delimiter": "|", "lineTerminator": "\r\n", "quoteChar": "\"", "doubleQuote": false, "escapeChar": "\\", "csvddfVersion": "1.2"},
+		"schema": {
+			"fields": [
+				{"name": "id", "type": "integer"},
+				{"name": "zip", "type": "string"},
+				{"name": "ok", "type": "boolean", "trueValues": ["1"], "falseValues": ["0"]},
+				{"name": "when", "type": "date"},
+				{"name": "price", "type": "number"},
+				{"name": "note", "type": "string"}
+			],
+			"primaryKey": "id"
+		}
+	}`, string(b))
+
+	// the exported descriptor reads the dataset back
+	rows, err := readResource(t, `{"resources": [`+string(b)+`]}`, "data", buf.String())
+	is.Nil(err)
+	is.Equal([][]string{{"1", "01234", "1", "2024-01-02", "1.5", "a \"quoted\" note"}, {"2", "98765", "0", "2024-02-03", "-2", ""}}, rows)
+}
@@ -0,0 +1,64 @@
+package frictionless_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/josephcopenhaver/csv-go/v3/frictionless"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalInferSchema(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	cr, err := csv.NewReader(csv.ReaderOpts().Reader(strings.NewReader("id,big,zip,name\n1,18446744073709551615,01234,a\n2,1,98765,bcd\n")))
+	is.Nil(err)
+
+	s, err := frictionless.InferSchema(cr, 0)
+	is.Nil(err)
+	is.Nil(cr.Close())
+
+	// sampled values never become constraints
+	b, err := json.Marshal(s)
+	is.Nil(err)
+	is.JSONEq(`{
+		"fields": [
+			{"name": "id", "type": "integer"},
+			{"name": "big", "type": "integer"},
+			{"name": "zip", "type": "string"},
+			{"name": "name", "type": "string"}
+		]
+	}`, string(b))
+}
+
+func TestFunctionalNewSchema(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	s, err := frictionless.NewSchema(csv.Schema{
+		Columns: []csv.ColumnSchema{
+			{Name: "id", Type: csv.ColumnUint64, Required: true, Unique: true},
+			{Name: "code", MinLength: 2, MaxLength: 2, Pattern: regexp.MustCompile(`^[A-Z]+$`)},
+			{Name: "qty", Type: csv.ColumnInt64, Minimum: "-1", Maximum: "10", Observed: csv.ColumnObservations{Values: 1, MinLength: 3, MaxLength: 3}},
+		},
+		PrimaryKey: []string{"id"},
+	})
+	is.Nil(err)
+
+	b, err := json.Marshal(s)
+	is.Nil(err)
+	is.JSONEq(`{
+		"fields": [
+			{"name": "id", "type": "integer", "constraints": {"required": true, "unique": true, "minimum": 0}},
+			{"name": "code", "type": "string", "constraints": {"minLength": 2, "maxLength": 2, "pattern": "^[A-Z]+$"}},
+			{"name": "qty", "type": "integer", "constraints": {"minimum": -1, "maximum": 10}}
+		],
+		"primaryKey": "id"
+	}`, string(b))
+}
@@ -110,13 +110,16 @@ func main() {
 			DeltaCommentBytesCheck string
 			CommentLinesCheck      string
 			IncRecordIndex         string
+			EndField               string
 			SetFieldStart          string
 			NotQuotePossible       bool
+			SkipBlankLines         bool
 		}
 
 		render := renderer[cfg](&buf)
 
 		const quoteOnSetFieldStart = "r.fieldStart = len(r.recordBuf)"
+		const recBufEndField = "r.fieldLengths = append(r.fieldLengths, len(r.recordBuf)-r.fieldStart)"
 
 		render(t, []cfg{
 			{
@@ -124,6 +127,16 @@ func main() {
 				RecBufAppend0:  "r.recordBuf = append(r.recordBuf, ",
 				RecBufAppend1:  "...)",
 				IncRecordIndex: "r.recordIndex++",
+				EndField:       recBufEndField,
+				SetFieldStart:  quoteOnSetFieldStart,
+			},
+			{
+				Struct:         "fastReader",
+				NameSuffix:     "_inMemory",
+				RecBufAppend0:  "r.appendInMemory(",
+				RecBufAppend1:  ")",
+				IncRecordIndex: "r.recordIndex++",
+				EndField:       "r.endInMemoryField()",
 				SetFieldStart:  quoteOnSetFieldStart,
 			},
 			{
@@ -134,7 +147,9 @@ func main() {
 				IncRecordIndex:         "r.incRecordIndex()",
 				DeltaCommentBytesCheck: "if r.outOfCommentBytes(delta) {return false}",
 				CommentLinesCheck:      "if r.outOfCommentLines() {return false}",
+				EndField:               recBufEndField,
 				SetFieldStart:          quoteOnSetFieldStart,
+				SkipBlankLines:         true,
 			},
 		})
 	}
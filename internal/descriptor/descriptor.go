@@ -0,0 +1,71 @@
+// Package descriptor holds the JSON helpers shared by the csvw and
+// frictionless packages.
+package descriptor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// Strings is a property which may be encoded as a single string, an array
+// of strings, or, for natural language properties such as titles, an object
+// mapping language tags to either. It is encoded as a single string when it
+// holds one value.
+type Strings []string
+
+func (s *Strings) UnmarshalJSON(b []byte) error {
+	switch {
+	case bytes.HasPrefix(b, []byte(`"`)):
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = Strings{v}
+	case bytes.HasPrefix(b, []byte(`{`)):
+		var m map[string]Strings
+		if err := json.Unmarshal(b, &m); err != nil {
+			return err
+		}
+		var v Strings
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			v = append(v, m[k]...)
+		}
+		*s = v
+	default:
+		var v []string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = v
+	}
+
+	return nil
+}
+
+func (s Strings) MarshalJSON() ([]byte, error) {
+	if len(s) == 1 {
+		return json.Marshal(s[0])
+	}
+
+	return json.Marshal([]string(s))
+}
+
+// SingleRune returns the only character of s. errUnsupported is joined with
+// the error returned when s is not exactly one character.
+func SingleRune(errUnsupported error, property, s string) (rune, error) {
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 || n != len(s) || r == utf8.RuneError {
+		return 0, errors.Join(errUnsupported, errors.New(property+" must be a single character"))
+	}
+
+	return r, nil
+}
+
+// Ptr returns a pointer to a copy of v.
+func Ptr[T any](v T) *T {
+	return &v
+}
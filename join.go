@@ -0,0 +1,384 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+)
+
+// ErrJoinRightTooLarge is returned when the right document of a Join which
+// is not sorted exceeds JoinSpec.MaxRightBytes.
+var ErrJoinRightTooLarge = errors.New("join right input byte count exceeds max")
+
+// JoinKind selects which unmatched records a Join keeps.
+type JoinKind uint8
+
+const (
+	// JoinInner keeps only records whose key is present in both documents.
+	JoinInner JoinKind = iota
+	// JoinLeft keeps every left record, combining those without a match
+	// with empty right fields.
+	JoinLeft
+	// JoinFull keeps every record of both documents, combining those
+	// without a match with empty fields for the other document.
+	JoinFull
+)
+
+func (k JoinKind) String() string {
+	switch k {
+	case JoinInner:
+		return "inner"
+	case JoinLeft:
+		return "left"
+	case JoinFull:
+		return "full"
+	}
+	return "JoinKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// JoinSpec describes how the records of two documents are matched.
+type JoinSpec struct {
+	// Left names the key columns of the left document.
+	Left []string
+	// Right names the key columns of the right document, matched to Left
+	// by position. It defaults to Left.
+	Right []string
+	// Sorted specifies that both documents are ordered by their key
+	// columns, compared as strings in ascending order, so they can be
+	// joined in a single streaming pass. An error is returned should
+	// either document be out of order.
+	Sorted bool
+	// MaxRightBytes is the approximate number of bytes of right records
+	// which may be held in memory when the documents are not sorted. It
+	// defaults to 64MiB. Should the right document exceed it iteration
+	// stops with ErrJoinRightTooLarge.
+	MaxRightBytes int64
+}
+
+// joiner combines the records of two documents which each begin with a
+// header row.
+type joiner struct {
+	left, right Reader
+	spec        JoinSpec
+	kind        JoinKind
+	header      []string
+	numLeft     int
+	numRight    int
+	leftKey     []int
+	rightKey    []int
+	// rightCols holds the index of every right column which is not a key
+	// in the order they are written after the left columns
+	rightCols []int
+}
+
+// Join combines the records of the left document with the records of the
+// right document which have the same key.
+//
+// The first record of each document must be its header row, and the first
+// row yielded is the combined header row: every left column followed by
+// every right column which is not a key. A column name present in both is
+// disambiguated by prefixing it with "left." or "right.". Each following
+// row pairs a left record with a matching right record. When a key matches
+// several records every pairing is yielded. Key columns of a record only
+// present in the right document are filled from the right record. Every
+// record must have as many fields as the header row of its document.
+//
+// Unless Sorted is used the right document is loaded into memory and the
+// left document is streamed. Rows are yielded in the order the left records
+// are read, followed by unmatched right records in the order they were read
+// for a JoinFull.
+//
+// Every yielded row is newly allocated. Should an error occur it is yielded
+// with a nil row and iteration stops. Neither reader is closed.
+func Join(left, right Reader, on JoinSpec, kind JoinKind) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		j, err := newJoiner(left, right, on, kind)
+		if err == nil {
+			if !yield(slices.Clone(j.header), nil) {
+				return
+			}
+
+			emit := func(l, r []string) bool {
+				return yield(j.combine(l, r), nil)
+			}
+			if on.Sorted {
+				err = j.merge(emit)
+			} else {
+				err = j.hash(emit)
+			}
+		}
+
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// WriteJoin combines left and right as Join does and writes the header row
+// and every combined row to dst.
+func WriteJoin(dst *Writer, left, right Reader, on JoinSpec, kind JoinKind) error {
+	for row, err := range Join(left, right, on, kind) {
+		if err != nil {
+			return err
+		}
+
+		if _, err := dst.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newJoiner(left, right Reader, on JoinSpec, kind JoinKind) (*joiner, error) {
+	if len(on.Left) == 0 {
+		return nil, errors.Join(ErrBadConfig, errors.New("no key columns"))
+	}
+
+	if on.Right == nil {
+		on.Right = on.Left
+	} else if len(on.Right) != len(on.Left) {
+		return nil, errors.Join(ErrBadConfig, errors.New("left and right key column counts must match"))
+	}
+
+	if kind > JoinFull {
+		return nil, errors.Join(ErrBadConfig, errors.New("invalid join kind"))
+	}
+
+	if on.MaxRightBytes < 0 {
+		return nil, errors.Join(ErrBadConfig, errors.New("max right bytes must be greater than or equal to zero"))
+	}
+
+	if on.MaxRightBytes == 0 {
+		on.MaxRightBytes = defaultSortMemoryBudget
+	}
+
+	j := &joiner{
+		left:  left,
+		right: right,
+		spec:  on,
+		kind:  kind,
+	}
+
+	leftHeader, leftKey, err := joinHeader(left, on.Left, "left")
+	if err != nil {
+		return nil, err
+	}
+	rightHeader, rightKey, err := joinHeader(right, on.Right, "right")
+	if err != nil {
+		return nil, err
+	}
+
+	j.numLeft = len(leftHeader)
+	j.numRight = len(rightHeader)
+	j.leftKey = leftKey
+	j.rightKey = rightKey
+
+	var rightNames []string
+	for i, h := range rightHeader {
+		if slices.Contains(rightKey, i) {
+			continue
+		}
+
+		j.rightCols = append(j.rightCols, i)
+		rightNames = append(rightNames, h)
+	}
+
+	j.header = make([]string, 0, len(leftHeader)+len(rightNames))
+	for _, h := range leftHeader {
+		if slices.Contains(rightNames, h) {
+			h = "left." + h
+		}
+		j.header = append(j.header, h)
+	}
+	for _, h := range rightNames {
+		if slices.Contains(leftHeader, h) {
+			h = "right." + h
+		}
+		j.header = append(j.header, h)
+	}
+
+	return j, nil
+}
+
+// joinHeader reads the header row of r and resolves the key columns within
+// it.
+func joinHeader(r Reader, keys []string, name string) ([]string, []int, error) {
+	if !r.Scan() {
+		if err := r.Err(); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("input " + name + " has no header row")
+	}
+
+	header := slices.Clone(r.Row())
+
+	key := make([]int, len(keys))
+	for i, k := range keys {
+		idx := slices.Index(header, k)
+		if idx == -1 {
+			return nil, nil, errors.Join(ErrBadConfig, fmt.Errorf("key column %q not found in input %s", k, name))
+		}
+		key[i] = idx
+	}
+
+	return header, key, nil
+}
+
+// combine returns the output row for a left and right record, either of
+// which may be nil.
+func (j *joiner) combine(l, r []string) []string {
+	row := make([]string, len(j.header))
+
+	if l != nil {
+		copy(row[:j.numLeft], l)
+	} else {
+		for i, idx := range j.leftKey {
+			row[idx] = r[j.rightKey[i]]
+		}
+	}
+
+	if r != nil {
+		for i, idx := range j.rightCols {
+			row[j.numLeft+i] = r[idx]
+		}
+	}
+
+	return row
+}
+
+// merge joins two documents sorted by key in a single pass, holding only
+// the right records of the current key in memory.
+func (j *joiner) merge(emit func(l, r []string) bool) error {
+	l := sortedInput{r: j.left, name: "left", width: j.numLeft, key: j.leftKey, dups: true}
+	r := sortedInput{r: j.right, name: "right", width: j.numRight, key: j.rightKey, dups: true}
+
+	if err := l.next(); err != nil {
+		return err
+	}
+	if err := r.next(); err != nil {
+		return err
+	}
+
+	var group [][]string
+	for l.ok || r.ok {
+		c := 1
+		if !r.ok {
+			c = -1
+		} else if l.ok {
+			c = slices.Compare(l.cur, r.cur)
+		}
+
+		switch {
+		case c < 0:
+			if j.kind != JoinInner && !emit(l.row, nil) {
+				return nil
+			}
+			if err := l.next(); err != nil {
+				return err
+			}
+		case c > 0:
+			if j.kind == JoinFull && !emit(nil, r.row) {
+				return nil
+			}
+			if err := r.next(); err != nil {
+				return err
+			}
+		default:
+			key := r.cur
+			group = append(group[:0], r.row)
+			for {
+				if err := r.next(); err != nil {
+					return err
+				}
+				if !r.ok || !slices.Equal(r.cur, key) {
+					break
+				}
+				group = append(group, r.row)
+			}
+
+			for l.ok && slices.Equal(l.cur, key) {
+				for _, row := range group {
+					if !emit(l.row, row) {
+						return nil
+					}
+				}
+				if err := l.next(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// hash joins documents which are not sorted by loading the right document
+// into memory and streaming the left document.
+func (j *joiner) hash(emit func(l, r []string) bool) error {
+	index := map[string][]int{}
+	var rows [][]string
+	var size int64
+
+	for j.right.Scan() {
+		if err := checkRowWidth(j.right.Row(), "right", j.numRight); err != nil {
+			return err
+		}
+
+		row, n := cloneRow(j.right.Row())
+
+		size += int64(n)
+		if size > j.spec.MaxRightBytes {
+			return ErrJoinRightTooLarge
+		}
+
+		k := encodeKey(row, j.rightKey)
+		index[k] = append(index[k], len(rows))
+		rows = append(rows, row)
+	}
+	if err := j.right.Err(); err != nil {
+		return err
+	}
+
+	var matched []bool
+	if j.kind == JoinFull {
+		matched = make([]bool, len(rows))
+	}
+
+	for j.left.Scan() {
+		row := j.left.Row()
+		if err := checkRowWidth(row, "left", j.numLeft); err != nil {
+			return err
+		}
+
+		idxs := index[encodeKey(row, j.leftKey)]
+		if len(idxs) == 0 {
+			if j.kind != JoinInner && !emit(row, nil) {
+				return nil
+			}
+			continue
+		}
+
+		for _, i := range idxs {
+			if matched != nil {
+				matched[i] = true
+			}
+			if !emit(row, rows[i]) {
+				return nil
+			}
+		}
+	}
+	if err := j.left.Err(); err != nil {
+		return err
+	}
+
+	for i, ok := range matched {
+		if !ok && !emit(nil, rows[i]) {
+			return nil
+		}
+	}
+
+	return nil
+}
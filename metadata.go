@@ -0,0 +1,175 @@
+package csv
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MetadataEntry is a key value pair stored within the comment line preamble
+// of a document.
+//
+// See WriteHeaderOpts().MetadataEntries and ExtendedReader.Metadata.
+type MetadataEntry struct {
+	Key   string
+	Value string
+}
+
+const metadataSeparator = ':'
+
+// encodeMetadataEntry renders an entry as the content of a single comment
+// line in the form "key: value".
+//
+// Backslashes, key separators within the key, and any rune the writer would
+// otherwise treat as a newline are escaped so every entry remains on one
+// line regardless of its content.
+func encodeMetadataEntry(e MetadataEntry) string {
+	var sb strings.Builder
+	sb.Grow(len(e.Key) + len(e.Value) + 2)
+
+	appendMetadataEscaped(&sb, e.Key, true)
+	sb.WriteByte(metadataSeparator)
+	sb.WriteByte(' ')
+	appendMetadataEscaped(&sb, e.Value, false)
+
+	return sb.String()
+}
+
+func appendMetadataEscaped(sb *strings.Builder, s string, isKey bool) {
+	for _, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case metadataSeparator:
+			if isKey {
+				sb.WriteString(`\:`)
+			} else {
+				sb.WriteRune(r)
+			}
+		case asciiLineFeed:
+			sb.WriteString(`\n`)
+		case asciiVerticalTab:
+			sb.WriteString(`\v`)
+		case asciiFormFeed:
+			sb.WriteString(`\f`)
+		case asciiCarriageReturn:
+			sb.WriteString(`\r`)
+		case utf8NextLine, utf8LineSeparator:
+			sb.WriteString(`\u`)
+			sb.WriteString(strconv.FormatUint(uint64(r)|0x10000, 16)[1:])
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// decodeMetadataEntry parses the content of a comment line produced by
+// encodeMetadataEntry.
+//
+// A single leading space is ignored both before the key and after the
+// separator. It returns false if the line is not in the key value form.
+func decodeMetadataEntry(line string) (MetadataEntry, bool) {
+	line = strings.TrimPrefix(line, " ")
+
+	var key strings.Builder
+	i := 0
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == metadataSeparator {
+			break
+		}
+
+		if c != '\\' {
+			key.WriteByte(c)
+			continue
+		}
+
+		n, ok := unescapeMetadata(&key, line[i:])
+		if !ok {
+			return MetadataEntry{}, false
+		}
+		i += n - 1
+	}
+
+	if i == len(line) || key.Len() == 0 {
+		return MetadataEntry{}, false
+	}
+
+	rest := strings.TrimPrefix(line[i+1:], " ")
+
+	var value strings.Builder
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c != '\\' {
+			value.WriteByte(c)
+			continue
+		}
+
+		n, ok := unescapeMetadata(&value, rest[i:])
+		if !ok {
+			return MetadataEntry{}, false
+		}
+		i += n - 1
+	}
+
+	return MetadataEntry{key.String(), value.String()}, true
+}
+
+// unescapeMetadata writes the rune represented by the escape sequence at the
+// start of s and returns the number of bytes consumed.
+func unescapeMetadata(sb *strings.Builder, s string) (int, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+
+	switch s[1] {
+	case '\\', metadataSeparator:
+		sb.WriteByte(s[1])
+	case 'n':
+		sb.WriteByte(asciiLineFeed)
+	case 'v':
+		sb.WriteByte(asciiVerticalTab)
+	case 'f':
+		sb.WriteByte(asciiFormFeed)
+	case 'r':
+		sb.WriteByte(asciiCarriageReturn)
+	case 'u':
+		if len(s) < 6 {
+			return 0, false
+		}
+
+		v, err := strconv.ParseUint(s[2:6], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(v)) {
+			return 0, false
+		}
+
+		sb.WriteRune(rune(v))
+		return 6, true
+	default:
+		return 0, false
+	}
+
+	return 2, true
+}
+
+// parseMetadata converts comment lines to key value pairs ignoring any line
+// that is not in the key value form.
+//
+// When a key is repeated the last value takes precedence.
+func parseMetadata(lines []string) map[string]string {
+	var m map[string]string
+
+	for _, v := range lines {
+		e, ok := decodeMetadataEntry(v)
+		if !ok {
+			continue
+		}
+
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[e.Key] = e.Value
+	}
+
+	return m
+}
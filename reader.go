@@ -1,6 +1,7 @@
 package csv
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -50,6 +51,9 @@ const (
 	// stAfterSOR stands for `parse state is after start of a record`
 	stAfterSOR
 	stEOF
+	// stBOMFound indicates that a byte order marker was found at the start
+	// of the document
+	stBOMFound
 
 	rFlagDropBOM
 	rFlagErrOnNoBOM
@@ -60,6 +64,11 @@ const (
 	rFlagEscape
 	rFlagCommentAfterSOR
 	rFlagTRSEmitsRecord
+	rFlagSkipBlankLines
+	rFlagTrimBlankLines
+	// rFlagInMemory indicates that rawBuf is the complete input provided
+	// by the caller so it must never be written to
+	rFlagInMemory
 )
 
 type rState uint8
@@ -82,30 +91,44 @@ var (
 	ErrFieldCount = errors.New("field count error")
 	ErrBadConfig  = errors.New("bad config")
 	ErrSecOp      = errors.New("security error")
+	// ErrSchemaViolation classifies fields which do not satisfy the Schema
+	// option
+	ErrSchemaViolation = errors.New("schema violation")
 
 	// instances
 	ErrTooManyFields                = errors.New("too many fields")
 	ErrSecOpRecordByteCountAboveMax = errors.New("record byte count exceeds max")
 	// ErrSecOpFieldCountAboveMax is a sub-instance of ErrTooManyFields
-	ErrSecOpFieldCountAboveMax     = errors.New("field count exceeds max")
-	ErrSecOpRecordCountAboveMax    = errors.New("record count exceeds max")
-	ErrSecOpCommentBytesAboveMax   = errors.New("comment byte count exceeds max")
-	ErrSecOpCommentsAboveMax       = errors.New("comment line count exceeds max")
-	ErrNotEnoughFields             = errors.New("not enough fields")
-	ErrReaderClosed                = errors.New("reader closed")
-	ErrUnexpectedHeaderRowContents = errors.New("header row values do not match expectations")
-	ErrBadRecordSeparator          = errors.New("record separator can only be one valid utf8 rune long or \"\\r\\n\"")
-	ErrIncompleteQuotedField       = fmt.Errorf("incomplete quoted field: %w", io.ErrUnexpectedEOF)
-	ErrQuoteInUnquotedField        = errors.New("quote found in unquoted field")
-	ErrInvalidQuotedFieldEnding    = errors.New("unexpected character found after end of quoted field") // expecting field separator, record separator, quote char, or end of file if field count matches expectations
-	ErrNoHeaderRow                 = fmt.Errorf("no header row: %w", io.ErrUnexpectedEOF)
-	ErrNoRows                      = fmt.Errorf("no rows: %w", io.ErrUnexpectedEOF)
-	ErrNoByteOrderMarker           = errors.New("no byte order marker")
-	ErrNilReader                   = errors.New("nil reader")
-	ErrInvalidEscSeqInQuotedField  = errors.New("invalid escape sequence in quoted field")
-	ErrNewlineInUnquotedField      = errors.New("newline rune found in unquoted field")
-	ErrUnexpectedQuoteAfterField   = errors.New("unexpected quote after quoted+escaped field")
-	ErrUnsafeCRFileEnd             = fmt.Errorf("ended in a carriage return which must be quoted when record separator is CRLF: %w", io.ErrUnexpectedEOF)
+	ErrSecOpFieldCountAboveMax         = errors.New("field count exceeds max")
+	ErrSecOpRecordCountAboveMax        = errors.New("record count exceeds max")
+	ErrSecOpCommentBytesAboveMax       = errors.New("comment byte count exceeds max")
+	ErrSecOpCommentsAboveMax           = errors.New("comment line count exceeds max")
+	ErrSecOpDecompressedBytesAboveMax  = errors.New("decompressed byte count exceeds max")
+	ErrSecOpDecompressionRatioAboveMax = errors.New("decompression ratio exceeds max")
+	ErrNotEnoughFields                 = errors.New("not enough fields")
+	ErrReaderClosed                    = errors.New("reader closed")
+	ErrUnexpectedHeaderRowContents     = errors.New("header row values do not match expectations")
+	ErrBadRecordSeparator              = errors.New("record separator can only be one valid utf8 rune long or \"\\r\\n\"")
+	ErrIncompleteQuotedField           = fmt.Errorf("incomplete quoted field: %w", io.ErrUnexpectedEOF)
+	ErrQuoteInUnquotedField            = errors.New("quote found in unquoted field")
+	ErrInvalidQuotedFieldEnding        = errors.New("unexpected character found after end of quoted field") // expecting field separator, record separator, quote char, or end of file if field count matches expectations
+	ErrNoHeaderRow                     = fmt.Errorf("no header row: %w", io.ErrUnexpectedEOF)
+	ErrNoRows                          = fmt.Errorf("no rows: %w", io.ErrUnexpectedEOF)
+	ErrNoByteOrderMarker               = errors.New("no byte order marker")
+	ErrNilReader                       = errors.New("nil reader")
+	ErrResetNotSupported               = errors.New("reset is not supported when reading from a byte slice")
+	ErrInvalidEscSeqInQuotedField      = errors.New("invalid escape sequence in quoted field")
+	ErrNewlineInUnquotedField          = errors.New("newline rune found in unquoted field")
+	ErrUnexpectedQuoteAfterField       = errors.New("unexpected quote after quoted+escaped field")
+	ErrSelectedColumnNotFound          = errors.New("selected column not found")
+	ErrSchemaRequired                  = errors.New("required value is empty")
+	ErrSchemaType                      = errors.New("value does not match column type")
+	ErrSchemaLength                    = errors.New("value length is out of range")
+	ErrSchemaRange                     = errors.New("value is out of range")
+	ErrSchemaEnum                      = errors.New("value is not one of the allowed values")
+	ErrSchemaPattern                   = errors.New("value does not match pattern")
+	ErrSchemaUnique                    = errors.New("value is not unique")
+	ErrUnsafeCRFileEnd                 = fmt.Errorf("ended in a carriage return which must be quoted when record separator is CRLF: %w", io.ErrUnexpectedEOF)
 
 	errNewlineInUnquotedFieldCarriageReturn = fmt.Errorf("%w: carriage return", ErrNewlineInUnquotedField)
 	errNewlineInUnquotedFieldLineFeed       = fmt.Errorf("%w: line feed", ErrNewlineInUnquotedField)
@@ -113,6 +136,27 @@ var (
 
 var emptyExpectedHeaders = []string{}
 
+type FieldCountPolicy uint8
+
+const (
+	// FieldCountStrict causes the reader to error with ErrNotEnoughFields or
+	// ErrTooManyFields when a record does not have the expected number of
+	// fields.
+	FieldCountStrict FieldCountPolicy = iota
+	// FieldCountFlexible allows records to have any number of fields and
+	// Row() will return a slice of varying length.
+	FieldCountFlexible
+	// FieldCountPadMissing appends empty fields to records that have too few
+	// fields. Records with too many fields are still an error.
+	FieldCountPadMissing
+	// FieldCountTruncateExtra discards trailing fields from records that have
+	// too many fields. Records with too few fields are still an error.
+	//
+	// Discarded fields are still parsed and validated but their contents are
+	// never buffered.
+	FieldCountTruncateExtra
+)
+
 type posTracedErr struct {
 	errType                error
 	err                    error
@@ -142,6 +186,12 @@ func (e posTracedErr) Is(target error) bool {
 	return errors.Is(e.errType, target) || errors.Is(e.err, target)
 }
 
+// Unwrap allows errors.As to extract structured error details such as a
+// HeaderMismatchError from the positioned error.
+func (e posTracedErr) Unwrap() error {
+	return e.err
+}
+
 func newIOError(byteIndex, recordIndex uint64, fieldIndex uint, err error) posTracedErr {
 	return posTracedErr{
 		errType:     ErrIO,
@@ -235,6 +285,34 @@ func (ReaderOptions) Reader(r io.Reader) ReaderOption {
 	}
 }
 
+// Context enables cancellation of parsing.
+//
+// The context is checked before each record is parsed and before each read
+// from the underlying reader. Once it is done Scan returns false and Err
+// returns an error classified as ErrIO which wraps ctx.Err().
+//
+// A read that is already blocked in the underlying reader is not
+// interrupted, close the source of that reader to abort it.
+func (ReaderOptions) Context(ctx context.Context) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.ctx = ctx
+		cfg.ctxSet = true
+	}
+}
+
+// Progress registers a callback which is invoked with a Progress report
+// each time the interval is exceeded and once more when Scan returns false.
+//
+// The callback is only ever invoked from within Scan after a record has been
+// fully parsed, so it never observes a partially parsed record.
+func (ReaderOptions) Progress(interval ProgressInterval, f func(Progress)) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.progressInterval = interval
+		cfg.onProgress = f
+		cfg.progressSet = true
+	}
+}
+
 // ClearFreedDataMemory ensures that whenever a shared memory buffer
 // that contains data goes out of scope that zero values are written
 // to every byte within the buffer.
@@ -274,6 +352,57 @@ func (ReaderOptions) ExpectHeaders(h ...string) ReaderOption {
 	}
 }
 
+// RequireHeaders causes the first row to be recognized as a header row.
+//
+// Unlike ExpectHeaders, the header row only needs to contain each of the
+// provided names once and columns may appear in any order. Columns not
+// present in the list cause the reader to error unless AllowExtraHeaders
+// is set to true.
+//
+// The number of fields per record is discovered from the header row unless
+// NumFields is also specified.
+//
+// Use ColumnMap() after the header row is processed to find which column
+// each required name resolved to.
+func (ReaderOptions) RequireHeaders(h ...string) ReaderOption {
+	return func(cfg *rCfg) {
+		if h == nil {
+			h = emptyExpectedHeaders
+		}
+		cfg.requiredHeaders = h
+	}
+}
+
+// AllowExtraHeaders permits the header row to contain columns that were not
+// specified via RequireHeaders.
+//
+// It can only be used in combination with RequireHeaders.
+func (ReaderOptions) AllowExtraHeaders(b bool) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.allowExtraHeaders = b
+	}
+}
+
+// HeaderAliases specifies alternate names that are accepted in place of
+// a header name given to ExpectHeaders or RequireHeaders.
+//
+// Keys must be one of the expected or required header names. When an alias
+// matches, ColumnMap() will still report the column under the key name.
+func (ReaderOptions) HeaderAliases(m map[string][]string) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.headerAliases = m
+	}
+}
+
+// CaseInsensitiveHeaders causes header names and aliases given to
+// ExpectHeaders or RequireHeaders to match header row values regardless
+// of letter case.
+func (ReaderOptions) CaseInsensitiveHeaders(b bool) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.caseInsensitiveHeaders = b
+	}
+}
+
 // RemoveHeaderRow causes the first row to be recognized as a header row.
 //
 // The row will be skipped over by Scan() and will not be returned by Row().
@@ -283,6 +412,87 @@ func (ReaderOptions) RemoveHeaderRow(b bool) ReaderOption {
 	}
 }
 
+// SkipLeadingLines causes the first n lines of the stream to be discarded
+// before any byte order marker, header, or record handling takes place.
+//
+// Lines are terminated by a line feed and are not parsed in any way, so
+// quotes and other control runes within them have no effect. Discarded bytes
+// still count towards the byte index reported in errors.
+//
+// See OnSkippedLine to receive the discarded lines.
+func (ReaderOptions) SkipLeadingLines(n int) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.skipLeadingLines = n
+	}
+}
+
+// SkipLeadingRecords causes the first n records of the document to be parsed
+// and discarded before the header row, if any, is processed.
+//
+// Discarded records are exempt from field count validation and do not
+// participate in discovering the number of fields per record.
+//
+// See OnSkippedRecord to receive the discarded records.
+func (ReaderOptions) SkipLeadingRecords(n int) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.skipLeadingRecords = n
+	}
+}
+
+// DropTrailingRecords causes the last n records of the document to never be
+// returned by Scan.
+//
+// This requires a lookahead buffer of n records, so records are only
+// returned once n more records have been parsed after them. Dropped records
+// are only reported to OnSkippedRecord if the document is parsed without
+// error.
+func (ReaderOptions) DropTrailingRecords(n int) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.dropTrailingRecords = n
+	}
+}
+
+// OnSkippedLine registers a callback that receives each line discarded by
+// SkipLeadingLines without its line terminator.
+//
+// The slice is only valid for the duration of the callback.
+func (ReaderOptions) OnSkippedLine(f func(line []byte)) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.onSkippedLine = f
+	}
+}
+
+// OnSkippedRecord registers a callback that receives each record discarded
+// by SkipLeadingRecords or DropTrailingRecords.
+func (ReaderOptions) OnSkippedRecord(f func(row []string)) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.onSkippedRecord = f
+	}
+}
+
+// SkipBlankLines causes lines that contain no bytes between record
+// separators to be discarded instead of being parsed as records.
+//
+// Discarded lines still count towards the record index reported in errors
+// but do not count towards MaxRecords. A line consisting of only a quoted
+// empty field is not blank and will still be returned as a record.
+func (ReaderOptions) SkipBlankLines(b bool) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.skipBlankLines = b
+	}
+}
+
+// TrimBlankLines causes lines that consist of only spaces and tabs to be
+// considered blank when SkipBlankLines is enabled.
+//
+// Spaces and tabs used as the field separator still delimit fields, so lines
+// containing them are never considered blank.
+func (ReaderOptions) TrimBlankLines(b bool) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.trimBlankLines = b
+	}
+}
+
 func (ReaderOptions) RemoveByteOrderMarker(b bool) ReaderOption {
 	return func(cfg *rCfg) {
 		cfg.removeByteOrderMarker = b
@@ -350,6 +560,37 @@ func (ReaderOptions) Comment(r rune) ReaderOption {
 	}
 }
 
+// OnComment registers a callback that receives the content of each comment
+// line as it is parsed, excluding the comment rune and the record separator.
+//
+// recordIndex is the zero based index of the record that follows the
+// comment, so it is zero for comments before the first record and non-zero
+// for comments found after the start of records when
+// CommentsAllowedAfterStartOfRecords is enabled.
+//
+// The slice is only valid for the duration of the callback. MaxComments and
+// MaxCommentBytes are still enforced and comment content beyond the
+// MaxCommentBytes limit is never reported.
+func (ReaderOptions) OnComment(f func(line []byte, recordIndex uint64)) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.onComment = f
+	}
+}
+
+// RetainComments keeps the content of each comment line found before the
+// first record so it can be returned by ExtendedReader.Comments and parsed
+// by ExtendedReader.Metadata.
+//
+// Retained comments are held in memory until Close or Reset, so consider
+// setting MaxComments and MaxCommentBytes when reading untrusted input.
+// ClearFreedDataMemory does not zero the retained comments because they are
+// returned as immutable strings.
+func (ReaderOptions) RetainComments(b bool) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.retainComments = b
+	}
+}
+
 func (ReaderOptions) CommentsAllowedAfterStartOfRecords(b bool) ReaderOption {
 	return func(cfg *rCfg) {
 		cfg.commentsAllowedAfterStartOfRecords = b
@@ -363,6 +604,21 @@ func (ReaderOptions) NumFields(n int) ReaderOption {
 	}
 }
 
+// FieldCountPolicy controls how records are handled when their number of
+// fields does not match the expected number of fields per record.
+//
+// The expected number of fields is the value given to NumFields, the
+// length of the ExpectHeaders list, or the number of fields found in the
+// first record - in that order of precedence.
+//
+// The default policy is FieldCountStrict. Use AdjustedRows() to find how
+// many records were altered or allowed by any other policy.
+func (ReaderOptions) FieldCountPolicy(p FieldCountPolicy) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.fieldCountPolicy = p
+	}
+}
+
 // TODO: what should be done if the file is empty and numFields == -1 (field count discovery mode)?
 //
 // how often are we expecting a file to have no headers, no predetermined field count expectation, and no
@@ -439,6 +695,48 @@ func (ReaderOptions) BorrowFields(b bool) ReaderOption {
 	}
 }
 
+// SelectColumns alters the Row function to only return the fields at the
+// provided zero based column indexes in the order specified.
+//
+// Fields that are not selected are still parsed and validated but their
+// contents are never copied into the record buffer or returned. Bytes of
+// unselected fields do not count towards MaxRecordBytes.
+//
+// If the first record does not contain a selected column the reader will
+// error with ErrSelectedColumnNotFound.
+//
+// When a header row is recognized it is parsed in full so that header
+// validation and ColumnMap() continue to operate on every column. Should the
+// header row be returned by Row() it will also be projected.
+func (ReaderOptions) SelectColumns(indexes ...int) ReaderOption {
+	return func(cfg *rCfg) {
+		if indexes == nil {
+			indexes = []int{}
+		}
+		cfg.selectedColumns = indexes
+	}
+}
+
+// SelectColumnsByName behaves like SelectColumns except columns are
+// identified by header name rather than by index.
+//
+// Names are resolved after the header row is processed in the same fashion
+// as ColumnMap() reports them, so names given to ExpectHeaders or
+// RequireHeaders match any of their aliases. It requires the reader to
+// recognize a header row via ExpectHeaders, RequireHeaders, RemoveHeaderRow,
+// or TrimHeaders.
+//
+// If a name cannot be resolved the reader will error with
+// ErrSelectedColumnNotFound.
+func (ReaderOptions) SelectColumnsByName(names ...string) ReaderOption {
+	return func(cfg *rCfg) {
+		if names == nil {
+			names = []string{}
+		}
+		cfg.selectedColumnNames = names
+	}
+}
+
 func (ReaderOptions) RecordSeparator(s string) ReaderOption {
 	if len(s) == 0 {
 		return badRecordSeparatorRConfig
@@ -587,6 +885,70 @@ func (ReaderOptions) MaxCommentBytes(n int) ReaderOption {
 	}
 }
 
+// Decompress wraps the input stream with a decompressor of the specified
+// format. It defaults to CompressionNone.
+//
+// Decompression happens before byte order marker detection and all byte
+// positions reported by errors and statistics are offsets within the
+// decompressed document.
+//
+// Untrusted input should also be limited via MaxDecompressedBytes and
+// MaxDecompressionRatio.
+func (ReaderOptions) Decompress(c Compression) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.compression = c
+	}
+}
+
+// MaxDecompressedBytes is a security option that limits the number of bytes a compressed input stream may decompress to before a SecOp error is thrown
+func (ReaderOptions) MaxDecompressedBytes(n int64) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.maxDecompressedBytes = n
+		cfg.maxDecompressedBytesSet = true
+	}
+}
+
+// MaxDecompressionRatio is a security option that limits the ratio of decompressed bytes to compressed bytes before a SecOp error is thrown
+//
+// The ratio is only enforced once at least 1MiB has been decompressed.
+func (ReaderOptions) MaxDecompressionRatio(r float64) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.maxDecompressionRatio = r
+		cfg.maxDecompressionRatioSet = true
+	}
+}
+
+// Schema validates every field of each record returned by Scan against the
+// column of s at the same position of the row, so after any column
+// selection. Fields beyond the columns of s are not validated and missing
+// fields are treated as empty.
+//
+// A header row returned by Scan is never validated, so a document with a
+// header row must be read with an option which recognizes it such as
+// ExpectHeaders or RemoveHeaderRow.
+//
+// The first field which does not satisfy its column stops the reader with
+// an error which satisfies errors.Is(err, ErrSchemaViolation) and the
+// specific reason such as ErrSchemaType. The record and field position of
+// the error are also available by extracting a SchemaViolation with
+// errors.As.
+func (ReaderOptions) Schema(s Schema) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.schema = &s
+	}
+}
+
+// MaxSchemaViolations collects up to n violations of the Schema option
+// rather than stopping at the first one. Records with violations are not
+// returned by Scan. Once n violations have been found, or the document ends
+// with at least one, Err returns every violation joined with errors.Join.
+func (ReaderOptions) MaxSchemaViolations(n int) ReaderOption {
+	return func(cfg *rCfg) {
+		cfg.maxSchemaViolations = n
+		cfg.maxSchemaViolationsSet = true
+	}
+}
+
 // MaxNumBytes for an overall csv document is not getting implemented because it's trivial to implement that as a io.Reader wrapper.
 
 func ReaderOpts() ReaderOptions {
@@ -599,14 +961,26 @@ func ReaderOpts() ReaderOptions {
 // with exports that satisfy a returned interface is the most
 // sane and supportable option
 type readerStrat struct {
-	scan  func() bool
-	row   func() []string
-	close func() error
-	err   func() error
+	scan         func() bool
+	row          func() []string
+	close        func() error
+	err          func() error
+	stats        func() ReaderStats
+	reset        func(io.Reader) error
+	columns      map[string]int
+	comments     []string
+	adjustedRows uint64
+	numRecords   uint64
+	snapshot     readerSnapshot
 }
 
 func (r *readerStrat) Scan() bool {
-	return r.scan()
+	if !r.scan() {
+		return false
+	}
+
+	r.numRecords++
+	return true
 }
 
 // Row returns a slice of strings that represents a row of a dataset.
@@ -641,6 +1015,62 @@ func (r *readerStrat) Err() error {
 	return r.err()
 }
 
+// ColumnMap returns the column index of each header name once the header
+// row has been processed by Scan.
+//
+// Header names given to ExpectHeaders or RequireHeaders are reported under
+// the name provided to those options even when an alias or case insensitive
+// match was made. All other columns are reported under their header row
+// value, with the first occurrence of a value taking precedence.
+//
+// It returns nil if the reader is not configured to recognize a header row
+// or the header row has not yet been processed. The returned map must not
+// be modified.
+func (r *readerStrat) ColumnMap() map[string]int {
+	return r.columns
+}
+
+// AdjustedRows returns the number of records that have been padded,
+// truncated, or allowed to vary in length per the FieldCountPolicy option.
+//
+// It always returns zero when the policy is FieldCountStrict.
+func (r *readerStrat) AdjustedRows() uint64 {
+	return r.adjustedRows
+}
+
+// Comments returns the content of each comment line found before the first
+// record, including the header row, once it has been processed by Scan.
+//
+// Each value excludes the comment rune and the record separator. Note that
+// comment lines written via WriteHeaderOpts().CommentLines are prefixed with
+// a space which is retained.
+//
+// It returns nil unless the reader is configured with RetainComments and
+// comments were found. The returned slice must not be modified.
+func (r *readerStrat) Comments() []string {
+	return r.comments
+}
+
+// Metadata parses the leading comment lines returned by Comments as key value
+// pairs in the form written by WriteHeaderOpts().Metadata.
+//
+// Comment lines that are not in the "key: value" form are ignored and the
+// last value of a repeated key takes precedence. It returns nil if no key
+// value pairs were found, which is always the case unless the reader is
+// configured with RetainComments.
+func (r *readerStrat) Metadata() map[string]string {
+	return parseMetadata(r.comments)
+}
+
+// Stats returns a summary of the work performed by the Reader so far.
+//
+// It may be called at any time, including after Close.
+func (r *readerStrat) Stats() ReaderStats {
+	s := r.stats()
+	s.Records = r.numRecords
+	return s
+}
+
 // IntoIter converts the reader state into an iterator.
 // Calling this method more than once returns the same iterator instance.
 //
@@ -658,21 +1088,70 @@ func (r *readerStrat) IntoIter() iter.Seq[[]string] {
 }
 
 func (r *readerStrat) iter(yield func([]string) bool) {
-	for r.scan() {
+	for r.Scan() {
 		if !yield(r.row()) {
 			return
 		}
 	}
 }
 
+// persistentScan returns a scan strategy which invokes f for every call to
+// Scan where f advances the wrapped strategy chain via the provided scan
+// function.
+//
+// Unlike slip closures, the returned strategy remains installed when the
+// strategies it wraps replace themselves or are replaced by setDone.
+func (r *readerStrat) persistentScan(next func() bool, f func(scan func() bool) bool) func() bool {
+	var self func() bool
+
+	initialNext := next
+	r.onReset(func() {
+		next = initialNext
+	})
+
+	scan := func() bool {
+		r.scan = next
+		v := r.scan()
+		next = r.scan
+		r.scan = self
+		return v
+	}
+
+	self = func() bool {
+		return f(scan)
+	}
+
+	return self
+}
+
 type rCfg struct {
-	headers            []string
-	rawBuf             []byte
-	recordBuf          []byte
-	reader             io.Reader
-	recordSepStartRune rune
-	rawBufSize         int
-	numFields          int
+	headers             []string
+	requiredHeaders     []string
+	headerAliases       map[string][]string
+	selectedColumns     []int
+	selectedColumnNames []string
+	onSkippedLine       func([]byte)
+	onSkippedRecord     func([]string)
+	onComment           func([]byte, uint64)
+	retainComments      bool
+	onHeaderRow         func([]string) error
+	onProgress          func(Progress)
+	progressInterval    ProgressInterval
+	schema              *Schema
+	schemaColumns       []schemaColumn
+	schemaPrimaryKey    []int
+	rawBuf              []byte
+	recordBuf           []byte
+	data                []byte
+	reader              io.Reader
+	ctx                 context.Context
+	recordSepStartRune  rune
+	rawBufSize          int
+	numFields           int
+	skipLeadingLines    int
+	skipLeadingRecords  int
+	dropTrailingRecords int
+	maxSchemaViolations int
 
 	// security attributes
 	maxFields       uint
@@ -682,6 +1161,10 @@ type rCfg struct {
 	maxComments     int
 
 	initialRecordBufferSize            int
+	fieldCountPolicy                   FieldCountPolicy
+	compression                        Compression
+	maxDecompressedBytes               int64
+	maxDecompressionRatio              float64
 	fieldSeparator                     rune
 	quote                              rune
 	escape                             rune
@@ -695,6 +1178,8 @@ type rCfg struct {
 	commentSet                         bool
 	errOnNoRows                        bool
 	borrowRow                          bool
+	skipBlankLines                     bool
+	trimBlankLines                     bool
 	borrowFields                       bool
 	trsEmitsRecord                     bool
 	numFieldsSet                       bool
@@ -709,14 +1194,25 @@ type rCfg struct {
 	recordBufSet                       bool
 	rawBufSet                          bool
 	rawBufSizeSet                      bool
+	dataSet                            bool
+	ctxSet                             bool
+	progressSet                        bool
 
 	//
 
-	maxFieldsSet       bool
-	maxRecordBytesSet  bool
-	maxRecordsSet      bool
-	maxCommentBytesSet bool
-	maxCommentsSet     bool
+	maxFieldsSet             bool
+	maxDecompressedBytesSet  bool
+	maxDecompressionRatioSet bool
+	maxRecordBytesSet        bool
+	maxRecordsSet            bool
+	maxCommentBytesSet       bool
+	maxCommentsSet           bool
+	maxSchemaViolationsSet   bool
+
+	//
+
+	allowExtraHeaders      bool
+	caseInsensitiveHeaders bool
 }
 
 type fastReader struct {
@@ -731,14 +1227,37 @@ type fastReader struct {
 	reader             io.Reader
 	recordSepStartRune rune
 	recordBuf          []byte
+	commentBuf         []byte
+	onComment          func([]byte, uint64)
+	retainComments     bool
 	fieldLengths       []int
 	rowBuf             []string
-	headers            []string
+	projection         []int
+	fieldOffsets       []int
 	fieldStart         int
 	numFields          int
 	recordIndex        uint64
 	byteIndex          uint64
 
+	// in-memory field aliasing state, see appendInMemory
+	inMemoryData       []byte
+	fieldAliases       []int
+	fieldAlias         []byte
+	aliasedRecordBytes int
+	fieldCopied        bool
+	aliasFields        bool
+
+	// totalBytes is the size of the document reported by progress updates
+	totalBytes int64
+
+	// statistics
+	numRefills       uint64
+	numComments      uint64
+	numCommentBytes  uint64
+	recordBufGrowths uint64
+	maxRecordBytes   int
+	recordBufCap     int
+
 	// DEV Note: cannot drop fieldIndex as some error field positions are after the last processed field and it would require another way to inform the error tracer
 
 	fieldIndex        uint
@@ -755,10 +1274,36 @@ type fastReader struct {
 
 func (cfg *rCfg) validate() error {
 
-	if cfg.reader == nil {
+	if cfg.dataSet {
+		if cfg.reader != nil {
+			return errors.New("a reader cannot be specified when reading from a byte slice")
+		}
+
+		if cfg.rawBufSet || cfg.rawBufSizeSet {
+			return errors.New("reader buffer options cannot be specified when reading from a byte slice")
+		}
+
+		if cfg.clearMemoryAfterFree {
+			return errors.New("freed data memory cannot be cleared when reading from a byte slice")
+		}
+	} else if cfg.reader == nil {
 		return ErrNilReader
 	}
 
+	if cfg.ctxSet && cfg.ctx == nil {
+		return errors.New("nil context")
+	}
+
+	if cfg.progressSet {
+		if cfg.onProgress == nil {
+			return errors.New("nil progress callback")
+		}
+
+		if cfg.progressInterval.Bytes == 0 && cfg.progressInterval.Records == 0 {
+			return errors.New("progress interval must specify a number of bytes or records")
+		}
+	}
+
 	if cfg.rawBufSet && cfg.rawBufSizeSet {
 		return errors.New("cannot specify both ReaderBuffer and ReaderBufferSize")
 	}
@@ -794,6 +1339,23 @@ func (cfg *rCfg) validate() error {
 		}
 	}
 
+	if cfg.requiredHeaders != nil {
+		if cfg.headers != nil {
+			return errors.New("cannot specify both ExpectHeaders and RequireHeaders")
+		}
+		if len(cfg.requiredHeaders) == 0 {
+			return errors.New("empty set of headers required")
+		}
+	}
+
+	if cfg.allowExtraHeaders && cfg.requiredHeaders == nil {
+		return errors.New("extra headers can only be allowed when specifying RequireHeaders")
+	}
+
+	if (cfg.headerAliases != nil || cfg.caseInsensitiveHeaders) && cfg.headers == nil && cfg.requiredHeaders == nil {
+		return errors.New("header aliases and case insensitive header matching require ExpectHeaders or RequireHeaders")
+	}
+
 	if cfg.recordSepRuneLen == -1 {
 		return ErrBadRecordSeparator
 	}
@@ -934,8 +1496,12 @@ func (cfg *rCfg) validate() error {
 			}
 
 			// config not useful due to other specified options, ignoring it
-			cfg.maxFieldsSet = false
-			cfg.maxFields = 0
+			//
+			// unless records are allowed to contain more fields than expected
+			if cfg.fieldCountPolicy != FieldCountFlexible && cfg.fieldCountPolicy != FieldCountTruncateExtra {
+				cfg.maxFieldsSet = false
+				cfg.maxFields = 0
+			}
 		}
 	}
 
@@ -955,6 +1521,102 @@ func (cfg *rCfg) validate() error {
 		return errors.New("max comment bytes cannot be less than zero")
 	}
 
+	if cfg.onComment != nil && !cfg.commentSet {
+		return errors.New("on comment requires a comment rune")
+	}
+
+	if cfg.retainComments && !cfg.commentSet {
+		return errors.New("retain comments requires a comment rune")
+	}
+
+	if cfg.trimBlankLines && !cfg.skipBlankLines {
+		return errors.New("trim blank lines requires skip blank lines to be enabled")
+	}
+
+	if cfg.skipLeadingLines < 0 {
+		return errors.New("skip leading lines cannot be less than zero")
+	}
+
+	if cfg.skipLeadingRecords < 0 {
+		return errors.New("skip leading records cannot be less than zero")
+	}
+
+	if cfg.dropTrailingRecords < 0 {
+		return errors.New("drop trailing records cannot be less than zero")
+	}
+
+	if cfg.fieldCountPolicy > FieldCountTruncateExtra {
+		return errors.New("invalid field count policy")
+	}
+
+	if cfg.compression > CompressionZlib {
+		return errors.New("invalid compression")
+	}
+
+	if cfg.compression != CompressionNone && cfg.dataSet {
+		return errors.New("decompression cannot be used when reading from a byte slice")
+	}
+
+	if (cfg.maxDecompressedBytesSet || cfg.maxDecompressionRatioSet) && cfg.compression == CompressionNone {
+		return errors.New("decompression limits require Decompress to be enabled")
+	}
+
+	if cfg.maxDecompressedBytesSet && cfg.maxDecompressedBytes <= 0 {
+		return errors.New("max decompressed bytes cannot be less than or equal to zero")
+	}
+
+	if cfg.maxDecompressionRatioSet && !(cfg.maxDecompressionRatio >= 1) {
+		return errors.New("max decompression ratio cannot be less than one")
+	}
+
+	if cfg.fieldCountPolicy == FieldCountFlexible && (cfg.selectedColumns != nil || cfg.selectedColumnNames != nil) {
+		return errors.New("column selection cannot be combined with the flexible field count policy")
+	}
+
+	if cfg.selectedColumns != nil {
+		if cfg.selectedColumnNames != nil {
+			return errors.New("cannot specify both SelectColumns and SelectColumnsByName")
+		}
+		if len(cfg.selectedColumns) == 0 {
+			return errors.New("empty set of columns selected")
+		}
+		for _, v := range cfg.selectedColumns {
+			if v < 0 {
+				return errors.New("selected column indexes must be greater than or equal to zero")
+			}
+			if cfg.numFields > 0 && v >= cfg.numFields {
+				return errors.New("selected column indexes must be less than the number of fields per record")
+			}
+		}
+	}
+
+	if cfg.selectedColumnNames != nil {
+		if len(cfg.selectedColumnNames) == 0 {
+			return errors.New("empty set of columns selected")
+		}
+		if cfg.headers == nil && cfg.requiredHeaders == nil && !cfg.removeHeaderRow && !cfg.trimHeaders {
+			return errors.New("selecting columns by name requires a header row")
+		}
+	}
+
+	if cfg.maxSchemaViolationsSet {
+		if cfg.schema == nil {
+			return errors.New("max schema violations requires a schema")
+		}
+		if cfg.maxSchemaViolations <= 0 {
+			return errors.New("max schema violations cannot be less than or equal to zero")
+		}
+	}
+
+	if cfg.schema != nil {
+		columns, primaryKey, err := compileSchema(*cfg.schema)
+		if err != nil {
+			return err
+		}
+		cfg.schemaColumns = columns
+		cfg.schemaPrimaryKey = primaryKey
+	}
+
 	return nil
 }
 
@@ -984,34 +1646,46 @@ func internalNewReader(options ...ReaderOption) (Reader, internalReader, error)
 	}
 
 	var headers []string
-	if len(cfg.headers) > 0 {
-		headers = make([]string, len(cfg.headers))
-		strLens := make([]int, len(cfg.headers))
-
-		var buf []byte
-		if cfg.trimHeaders {
-			for i := range cfg.headers {
-				v := strings.TrimSpace(cfg.headers[i])
-				strLens[i] = len(v)
-				buf = append(buf, []byte(v)...)
-			}
-		} else {
-			for i := range cfg.headers {
-				v := cfg.headers[i]
-				strLens[i] = len(v)
-				buf = append(buf, []byte(v)...)
-			}
+	{
+		src := cfg.headers
+		if src == nil {
+			src = cfg.requiredHeaders
 		}
 
-		strBuf := string(buf)
+		if len(src) > 0 {
+			headers = make([]string, len(src))
+			strLens := make([]int, len(src))
 
-		var p int
-		for i, s := range strLens {
-			headers[i] = strBuf[p : p+s]
-			p += s
+			var buf []byte
+			if cfg.trimHeaders {
+				for i := range src {
+					v := strings.TrimSpace(src[i])
+					strLens[i] = len(v)
+					buf = append(buf, []byte(v)...)
+				}
+			} else {
+				for i := range src {
+					v := src[i]
+					strLens[i] = len(v)
+					buf = append(buf, []byte(v)...)
+				}
+			}
+
+			strBuf := string(buf)
+
+			var p int
+			for i, s := range strLens {
+				headers[i] = strBuf[p : p+s]
+				p += s
+			}
 		}
 	}
 
+	hm, err := newHeaderMatcher(&cfg, headers)
+	if err != nil {
+		return nil, nil, errors.Join(ErrBadConfig, err)
+	}
+
 	// a mode affects what runes are relevant
 	// and the reachable set of control-rune handlers
 	// because of that change to relevancy
@@ -1070,6 +1744,12 @@ func internalNewReader(options ...ReaderOption) (Reader, internalReader, error)
 	if cfg.errOnQuotesInUnquotedField {
 		bitFlags |= rFlagErrOnQInUF
 	}
+	if cfg.skipBlankLines {
+		bitFlags |= rFlagSkipBlankLines
+	}
+	if cfg.trimBlankLines {
+		bitFlags |= rFlagTrimBlankLines
+	}
 
 	if cfg.recordSepRuneLen != 0 {
 		controlRuneSet.addRuneUniqueUnchecked(cfg.recordSepStartRune)
@@ -1094,7 +1774,7 @@ func internalNewReader(options ...ReaderOption) (Reader, internalReader, error)
 		rowBuf = make([]string, cfg.numFields)
 	}
 
-	r, r2 := newReader(cfg, controlRuneSet, headers, rowBuf, bitFlags)
+	r, r2 := newReader(cfg, controlRuneSet, hm, rowBuf, bitFlags)
 	return r, r2, nil
 }
 
@@ -1201,6 +1881,13 @@ func (r *secOpReader) secOpStreamParsingErr(err error) {
 func (r *fastReader) ioErr(err error) {
 	if r.scanErr == nil {
 		recordIndex, fieldIndex := r.humanIndexes(err)
+
+		// limits enforced while reading the input stream are security errors
+		if v, ok := err.(secOpReadErr); ok {
+			r.scanErr = newSecOpError(r.byteIndex, recordIndex, fieldIndex, v.err)
+			return
+		}
+
 		r.scanErr = newIOError(r.byteIndex, recordIndex, fieldIndex, err)
 	}
 }
@@ -1364,9 +2051,16 @@ func (r *fastReader) checkNumFieldsWithDiscovery(errTrailer error) bool {
 }
 
 func (r *fastReader) resetRecordBuffers() {
+	r.trackRecordBuf()
+
 	r.fieldLengths = r.fieldLengths[:0]
 	r.fieldStart = 0
 	r.recordBuf = r.recordBuf[:0]
+
+	r.fieldAliases = r.fieldAliases[:0]
+	r.fieldAlias = nil
+	r.fieldCopied = false
+	r.aliasedRecordBytes = 0
 }
 
 func (r *fastReader) scan() bool {
@@ -1403,8 +2097,35 @@ func (r *fastReader) setDone() {
 	}
 	r.bitFlags |= stDone
 
-	r.pr.scan = func() bool {
-		return false
+	r.pr.scan = scanDone
+}
+
+func scanDone() bool {
+	return false
+}
+
+// setReader installs reader as the source of the document, wrapping it as
+// required by the configuration.
+func (r *fastReader) setReader(cfg *rCfg, reader io.Reader) {
+	r.reader = reader
+	if reader == nil {
+		return
+	}
+
+	if cfg.onProgress != nil {
+		r.totalBytes = seekerSize(reader)
+	}
+
+	if cfg.compression != CompressionNone {
+		r.reader = newDecompressReader(r.reader, cfg.compression, cfg.maxDecompressedBytes, cfg.maxDecompressionRatio)
+
+		// the size of the input stream is not the size of the document
+		r.totalBytes = -1
+	}
+
+	// a context that can never be done does not need to be checked
+	if cfg.ctx != nil && cfg.ctx.Done() != nil {
+		r.reader = contextReader{cfg.ctx, r.reader}
 	}
 }
 
@@ -1473,8 +2194,8 @@ func (r *fastReader) handleEOF() bool {
 		r.state = rStateStartOfRecord // might be removable, but leaving in because it's not a hot path and it's good practice to ensure the state machine is fully deterministic
 		fallthrough
 	case rStateStartOfRecord:
-		if (r.bitFlags&rFlagTRSEmitsRecord) != 0 && r.numFields == 1 {
-			r.fieldLengths = append(r.fieldLengths, 0)
+		if (r.bitFlags&(rFlagTRSEmitsRecord|rFlagSkipBlankLines)) == rFlagTRSEmitsRecord && r.numFields == 1 {
+			r.endFieldAtEOF()
 			// field start is unchanged because the last one was zero length
 			// r.fieldStart = len(r.recordBuf)
 			return r.checkNumFields(io.ErrUnexpectedEOF)
@@ -1484,20 +2205,33 @@ func (r *fastReader) handleEOF() bool {
 		r.parsingErr(ErrIncompleteQuotedField)
 		return false
 	case rStateInLineComment:
+		r.emitComment()
 		return false
 	case rStateStartOfField:
-		r.fieldLengths = append(r.fieldLengths, 0)
+		r.endFieldAtEOF()
 		// field start is unchanged because the last one was zero length
 		// r.fieldStart = len(r.recordBuf)
 		return r.checkNumFields(io.ErrUnexpectedEOF)
 	case rStateEndOfQuotedField, rStateInField:
-		r.fieldLengths = append(r.fieldLengths, len(r.recordBuf)-r.fieldStart)
+		if r.state == rStateInField && r.isSplitBlankLine(nil) {
+			return false
+		}
+		r.endFieldAtEOF()
 		return r.checkNumFields(io.ErrUnexpectedEOF)
 	}
 
 	panic(panicUnknownReaderStateDuringEOF)
 }
 
+func (r *fastReader) endFieldAtEOF() {
+	if r.aliasFields {
+		r.endInMemoryField()
+		return
+	}
+
+	r.fieldLengths = append(r.fieldLengths, len(r.recordBuf)-r.fieldStart)
+}
+
 // endsInValidUTF8 should get inlined by the compiler
 //
 // keep it small and only one function call
@@ -1515,6 +2249,11 @@ type secOpReader struct {
 	outOfCommentBytes func(int) bool
 	outOfCommentLines func() bool
 	fieldNumOverflow  func() bool
+	// numBlankLines is the number of lines discarded by SkipBlankLines
+	numBlankLines uint64
+	// fieldMask is true for each record field index that is part of the
+	// projection when column selection is enabled
+	fieldMask []bool
 }
 
 func (r *secOpReader) closeWithMemClear() error {
@@ -1528,7 +2267,9 @@ func (r *secOpReader) zeroRecordBuffers() {
 	clear(r.rawBuf[:cap(r.rawBuf)])
 	clear(r.fieldLengths[:cap(r.fieldLengths)])
 	clear(r.recordBuf[:cap(r.recordBuf)])
+	clear(r.commentBuf[:cap(r.commentBuf)])
 	clear(r.rowBuf[:cap(r.rowBuf)])
+	r.pr.clearComments()
 
 	r.resetRecordBuffers()
 }
@@ -1548,7 +2289,9 @@ func (r *secOpReader) incRecordIndexWithMax(max uint64) func() {
 		// all depends on if another character would be added to the record buf after
 		// this statement is reached
 
-		n := r.recordIndex + 1
+		// blank lines that are skipped advance the record index
+		// but do not count towards the max
+		n := r.recordIndex - r.numBlankLines + 1
 		if n == 0 || n > max {
 			// impossible
 
@@ -1560,7 +2303,7 @@ func (r *secOpReader) incRecordIndexWithMax(max uint64) func() {
 			panic(panicMissedHandlingMaxRecordIndex)
 		}
 
-		r.recordIndex = n
+		r.recordIndex++
 
 		if n != max {
 			return
@@ -1683,33 +2426,63 @@ type Reader interface {
 	Scan() bool
 }
 
-type internalReader any
+// ExtendedReader is implemented by every Reader created by this package,
+// including MultiReader. Obtain it with a type assertion:
+//
+//	er, ok := r.(csv.ExtendedReader)
+//
+// It is kept separate from Reader so that other implementations of Reader
+// are not required to provide these methods.
+type ExtendedReader interface {
+	Reader
+	AdjustedRows() uint64
+	ColumnMap() map[string]int
+	Comments() []string
+	Metadata() map[string]string
+	Reset(r io.Reader) error
+	Stats() ReaderStats
+}
 
-func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []string, bitFlags rFlag) (Reader, internalReader) {
+var (
+	_ ExtendedReader = (*readerStrat)(nil)
+	_ ExtendedReader = (*MultiReader)(nil)
+)
+
+type internalReader any
 
+func newReader(cfg rCfg, controlRuneSet runeSet6, hm *headerMatcher, rowBuf []string, bitFlags rFlag) (Reader, internalReader) {
 	r := &readerStrat{}
+	return r, r.init(cfg, controlRuneSet, hm, rowBuf, bitFlags)
+}
+
+// init assembles the parsing strategy for the provided configuration.
+func (r *readerStrat) init(cfg rCfg, controlRuneSet runeSet6, hm *headerMatcher, rowBuf []string, bitFlags rFlag) internalReader {
+
+	*r = readerStrat{}
 
 	fr := &fastReader{
 		controlRuneSet:     controlRuneSet,
 		rawBuf:             cfg.rawBuf[0:0:len(cfg.rawBuf)],
-		reader:             cfg.reader,
 		quote:              cfg.quote,
 		escape:             cfg.escape,
 		numFields:          cfg.numFields,
 		fieldSeparator:     cfg.fieldSeparator,
 		comment:            cfg.comment,
-		headers:            headers,
 		recordBuf:          cfg.recordBuf[0:0:len(cfg.recordBuf)],
 		rowBuf:             rowBuf,
 		recordSepStartRune: cfg.recordSepStartRune,
 		recordSepRuneLen:   cfg.recordSepRuneLen,
 		bitFlags:           bitFlags,
+		onComment:          cfg.onComment,
+		retainComments:     cfg.retainComments,
 		pr:                 r,
 	}
 
 	var sr *secOpReader
 
-	if cfg.clearMemoryAfterFree || cfg.maxRecordBytesSet || cfg.maxRecordsSet || cfg.maxCommentBytesSet || cfg.maxCommentsSet || cfg.maxFieldsSet {
+	projecting := cfg.selectedColumns != nil || cfg.selectedColumnNames != nil
+
+	if cfg.clearMemoryAfterFree || cfg.maxRecordBytesSet || cfg.maxRecordsSet || cfg.maxCommentBytesSet || cfg.maxCommentsSet || cfg.maxFieldsSet || projecting || cfg.fieldCountPolicy != FieldCountStrict || cfg.skipLeadingRecords > 0 || cfg.skipBlankLines {
 		sr = &secOpReader{fastReader: fr}
 
 		if cfg.clearMemoryAfterFree {
@@ -1728,6 +2501,14 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 			}
 		}
 
+		if projecting {
+			sr.appendRecBuf = sr.projectedAppendRecBuf(sr.appendRecBuf)
+		}
+
+		if cfg.fieldCountPolicy == FieldCountTruncateExtra {
+			sr.appendRecBuf = sr.truncatedAppendRecBuf(sr.appendRecBuf)
+		}
+
 		sr.prepareRow = sr.prepareRow_memclearOn
 
 		if !cfg.maxCommentBytesSet {
@@ -1748,18 +2529,34 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 			sr.incRecordIndex = sr.incRecordIndexWithMax(cfg.maxRecords)
 		}
 
-		if cfg.maxFieldsSet {
+		if cfg.fieldCountPolicy == FieldCountFlexible || cfg.fieldCountPolicy == FieldCountTruncateExtra {
+			if cfg.maxFieldsSet {
+				sr.fieldNumOverflow = sr.fieldNumOverflowMaxCheckOnly(cfg.maxFields)
+			} else {
+				sr.fieldNumOverflow = sr.nopFieldNumOverflow
+			}
+		} else if cfg.maxFieldsSet {
 			sr.fieldNumOverflow = sr.fieldNumOverflowWithMaxCheck(cfg.maxFields)
 		} else {
 			sr.fieldNumOverflow = fr.fieldNumOverflow
 		}
 
 		r.scan = sr.scan
+	} else if cfg.dataSet {
+		fr.inMemoryData = cfg.data
+		fr.aliasFields = true
+		r.scan = fr.scanInMemory
 	} else {
 		r.scan = fr.scan
 	}
 
-	if cfg.rawBufSizeSet {
+	fr.setReader(&cfg, cfg.reader)
+
+	if cfg.dataSet {
+		fr.rawBuf = cfg.data[:len(cfg.data):len(cfg.data)]
+		fr.bitFlags |= stEOF | rFlagInMemory
+		fr.totalBytes = int64(len(cfg.data))
+	} else if cfg.rawBufSizeSet {
 		fr.rawBuf = make([]byte, 0, cfg.rawBufSize)
 	} else if !cfg.rawBufSet {
 		fr.rawBuf = make([]byte, 0, defaultReaderBufferSize)
@@ -1768,6 +2565,7 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 	if cfg.initialRecordBufferSize > 0 {
 		fr.recordBuf = make([]byte, 0, cfg.initialRecordBufferSize)
 	}
+	fr.recordBufCap = cap(fr.recordBuf)
 
 	if (fr.bitFlags & (rFlagDropBOM | rFlagErrOnNoBOM)) == 0 {
 		fr.state = rStateStartOfRecord
@@ -1776,7 +2574,7 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 	if fr.numFields == -1 {
 		fr.checkNumFields = fr.checkNumFieldsWithDiscovery
 	} else {
-		if fr.numFields > 0 {
+		if fr.numFields > 0 && cap(fr.fieldLengths) < fr.numFields {
 			fr.fieldLengths = make([]int, 0, fr.numFields)
 		}
 		if (fr.bitFlags & rFlagComment) != 0 {
@@ -1786,7 +2584,24 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 		}
 	}
 
-	if !cfg.borrowRow {
+	switch cfg.fieldCountPolicy {
+	case FieldCountFlexible:
+		fr.checkNumFields = sr.newFlexibleCheckNumFields(fr.numFields)
+	case FieldCountPadMissing:
+		fr.checkNumFields = sr.newFirstCheckNumFields(sr.checkNumFieldsWithPadding)
+	case FieldCountTruncateExtra:
+		fr.checkNumFields = sr.newFirstCheckNumFields(sr.checkNumFieldsWithTruncation)
+	}
+
+	if projecting {
+		if !cfg.borrowRow {
+			r.row = fr.projectedRow
+		} else if cfg.borrowFields {
+			r.row = fr.projectedRowBorrowedAndFieldsBorrowed
+		} else {
+			r.row = fr.projectedRowBorrowedAndFieldsCloned
+		}
+	} else if !cfg.borrowRow {
 		r.row = fr.defaultRow
 	} else if cfg.borrowFields {
 		fr.setRowBorrowedAndFieldsBorrowed()
@@ -1794,12 +2609,32 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 		fr.setRowBorrowedAndFieldsCloned()
 	}
 
+	if fr.aliasFields {
+		r.row = fr.newInMemoryRow(cfg.borrowRow, cfg.borrowFields)
+	}
+
+	if cfg.skipLeadingRecords > 0 {
+		r.scan = sr.newSkipLeadingRecordsScan(cfg.skipLeadingRecords, cfg.onSkippedRecord, r.scan)
+	}
+
 	headersHandled := true
-	checkHeadersMatch := (fr.headers != nil)
-	if checkHeadersMatch || cfg.removeHeaderRow || cfg.trimHeaders {
+	if hm != nil || cfg.removeHeaderRow || cfg.trimHeaders || cfg.onHeaderRow != nil {
 		headersHandled = false
 		trimHeaders := cfg.trimHeaders
 		removeHeaderRow := cfg.removeHeaderRow
+		onHeaderRow := cfg.onHeaderRow
+		selectedColumns := cfg.selectedColumns
+		selectedColumnNames := cfg.selectedColumnNames
+		r.onReset(func() {
+			headersHandled = false
+		})
+		if trimHeaders && selectedColumnNames != nil {
+			names := make([]string, len(selectedColumnNames))
+			for i, v := range selectedColumnNames {
+				names[i] = strings.TrimSpace(v)
+			}
+			selectedColumnNames = names
+		}
 		next := r.scan
 		r.scan = func() bool {
 			r.scan = next
@@ -1808,53 +2643,56 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 				return false
 			}
 
+			fr.materializeInMemory()
+
+			headersStr := string(fr.recordBuf)
+			row := make([]string, len(fr.fieldLengths))
+
 			if trimHeaders {
-				headersStr := string(fr.recordBuf)
 				fr.recordBuf = fr.recordBuf[:0]
 
 				var p int
-				matching := checkHeadersMatch
 				for i, s := range fr.fieldLengths {
 					field := headersStr[p : p+s]
 					p += s
 
 					field = strings.TrimSpace(field)
 					fr.fieldLengths[i] = len(field)
+					row[i] = field
 
 					fr.recordBuf = append(fr.recordBuf, []byte(field)...)
-
-					if matching && field != fr.headers[i] {
-						matching = false
-					}
 				}
+			} else {
+				var p int
+				for i, s := range fr.fieldLengths {
+					row[i] = headersStr[p : p+s]
+					p += s
+				}
+			}
 
-				if checkHeadersMatch && !matching {
+			if onHeaderRow != nil {
+				if err := onHeaderRow(row); err != nil {
 					fr.setDone()
-					fr.parsingErr(ErrUnexpectedHeaderRowContents)
+					fr.parsingErr(err)
 					return false
 				}
-			} else if checkHeadersMatch {
+			}
 
-				var p int
-				for i, s := range fr.fieldLengths {
-					var field string
-					if s > 0 {
-						// usage of unsafe here is actually safe because field is
-						// never modified and no parts of its contents exist
-						// without cloning values to other parts of memory
-						// past the lifecycle of this function
-						//
-						// It will also never be called if the len is zero,
-						// just as an extra precaution.
-						field = unsafe.String(&fr.recordBuf[p], s)
-					}
-					p += s
+			columns, err := hm.match(row)
+			if err != nil {
+				fr.setDone()
+				fr.parsingErr(err)
+				return false
+			}
+			r.columns = columns
 
-					if field != fr.headers[i] {
-						fr.setDone()
-						fr.parsingErr(ErrUnexpectedHeaderRowContents)
-						return false
-					}
+			if selectedColumnNames != nil {
+				if !sr.selectColumnsByName(selectedColumnNames, columns) {
+					return false
+				}
+			} else if selectedColumns != nil {
+				if !sr.selectColumns(selectedColumns) {
+					return false
 				}
 			}
 
@@ -1868,25 +2706,65 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 
 			return r.scan()
 		}
+	} else if cfg.selectedColumns != nil {
+		selectedColumns := cfg.selectedColumns
+		if fr.numFields > 0 && cfg.skipLeadingRecords == 0 {
+			sr.selectColumns(selectedColumns)
+		} else {
+			// the number of fields per record must first be discovered
+			// and any skipped records must not be projected
+			next := r.scan
+			r.scan = func() bool {
+				r.scan = next
+
+				if !r.scan() {
+					return false
+				}
+
+				return sr.selectColumns(selectedColumns)
+			}
+		}
+	}
+
+	if cfg.dropTrailingRecords > 0 {
+		r.scan = fr.newDropTrailingRecordsScan(cfg.dropTrailingRecords, cfg.onSkippedRecord, cfg.borrowRow, cfg.borrowFields, r.scan)
+	}
+
+	if cfg.schema != nil {
+		// a header row returned by Scan is never validated
+		headerReturned := (hm != nil || cfg.trimHeaders || cfg.onHeaderRow != nil) && !cfg.removeHeaderRow
+		r.scan = fr.newSchemaScan(cfg.schemaColumns, cfg.schemaPrimaryKey, cfg.maxSchemaViolations, headerReturned, uint64(cfg.dropTrailingRecords), r.scan)
 	}
 
-	if fr.headers == nil && !cfg.errOnNoRows {
-		if sr != nil {
-			r.close = sr.close
-			r.err = sr.err
+	if cfg.skipLeadingLines > 0 {
+		skipLeadingLines := cfg.skipLeadingLines
+		onSkippedLine := cfg.onSkippedLine
+		next := r.scan
+		r.scan = func() bool {
+			r.scan = next
+
+			if !fr.skipLeadingLines(skipLeadingLines, onSkippedLine) {
+				return false
+			}
 
-			return r, sr
+			return r.scan()
 		}
+	}
 
-		r.close = fr.close
-		r.err = fr.err
+	// a context that can never be done does not need to be checked
+	if cfg.ctx != nil && cfg.ctx.Done() != nil {
+		r.scan = fr.newContextScan(cfg.ctx, r.scan)
+	}
 
-		return r, fr
+	if cfg.onProgress != nil {
+		r.scan = fr.newProgressScan(cfg.progressInterval, cfg.onProgress, r.scan)
 	}
 
-	// verify that true is returned at least once
-	// using a slip closure
-	{
+	r.stats = fr.stats
+
+	if hm != nil || cfg.errOnNoRows {
+		// verify that true is returned at least once
+		// using a slip closure
 		errOnNoRows := cfg.errOnNoRows
 		next := r.scan
 		r.scan = func() bool {
@@ -1903,15 +2781,16 @@ func newReader(cfg rCfg, controlRuneSet runeSet6, headers []string, rowBuf []str
 		}
 	}
 
+	var ir internalReader = fr
+	r.close = fr.close
+	r.err = fr.err
 	if sr != nil {
+		ir = sr
 		r.close = sr.close
 		r.err = sr.err
-
-		return r, sr
 	}
 
-	r.close = fr.close
-	r.err = fr.err
+	r.reset = r.newReset(cfg, fr, sr)
 
-	return r, fr
+	return ir
 }
@@ -0,0 +1,52 @@
+package csv
+
+import "slices"
+
+// isBlankLine reports whether the bytes of a line found at the start of a
+// record should be discarded given the SkipBlankLines and TrimBlankLines
+// options.
+func (r *fastReader) isBlankLine(p []byte) bool {
+	if (r.bitFlags & rFlagSkipBlankLines) == 0 {
+		return false
+	}
+
+	if len(p) == 0 {
+		return true
+	}
+
+	return (r.bitFlags&rFlagTrimBlankLines) != 0 && isSpaceOrTab(p)
+}
+
+// isSplitBlankLine reports whether the first field of the current record
+// consists of only spaces and tabs when combined with the remaining bytes of
+// the line in p.
+//
+// It handles lines of whitespace that were split across reads of the
+// underlying reader and therefore already partially buffered.
+func (r *fastReader) isSplitBlankLine(p []byte) bool {
+	if (r.bitFlags & rFlagTrimBlankLines) == 0 {
+		return false
+	}
+
+	if len(r.fieldLengths) != 0 {
+		return false
+	}
+
+	// when the first column is not projected its bytes were never buffered
+	// so there is no way to tell if the line was blank
+	if r.projection != nil && !slices.Contains(r.projection, 0) {
+		return false
+	}
+
+	return isSpaceOrTab(r.recordBuf) && isSpaceOrTab(p)
+}
+
+func isSpaceOrTab(p []byte) bool {
+	for _, c := range p {
+		if c != ' ' && c != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
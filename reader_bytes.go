@@ -0,0 +1,170 @@
+package csv
+
+import "unsafe"
+
+// NewBytesReader creates a Reader over a document that is already fully
+// loaded into memory, such as embedded data or the result of os.ReadFile.
+//
+// The input is parsed in place rather than being copied through a read
+// buffer and every field that did not require unescaping is returned as a
+// string aliasing data directly without ever being copied. Such fields
+// remain valid after subsequent calls to Scan and Close, so data must never
+// be modified while any returned value is in use.
+//
+// Fields that required unescaping are copied unless BorrowFields is
+// enabled, in which case the usual borrowing rules apply to them. Options
+// which enforce per record limits or adjust records, such as MaxRecordBytes,
+// FieldCountPolicy, SkipLeadingRecords, SkipBlankLines, SelectColumns, and
+// SelectColumnsByName, disable aliasing so that every field is copied.
+//
+// The Reader, ReaderBuffer, ReaderBufferSize, and ClearFreedDataMemory
+// options cannot be used with this constructor.
+func NewBytesReader(data []byte, options ...ReaderOption) (Reader, error) {
+	options = append(options[:len(options):len(options)], func(cfg *rCfg) {
+		cfg.data = data
+		cfg.dataSet = true
+	})
+
+	r, _, err := internalNewReader(options...)
+	return r, err
+}
+
+// scanInMemory is the scan strategy of readers which alias fields of the
+// input, see appendInMemory.
+func (r *fastReader) scanInMemory() bool {
+
+	r.resetRecordBuffers()
+
+	return r.prepareRow_inMemory()
+}
+
+// appendInMemory tracks the bytes of the field being parsed as a span of the
+// input rather than copying them into the record buffer.
+//
+// Should a field not be contiguous within the input, as is the case when a
+// quote or escape sequence is unescaped, the span is copied into the record
+// buffer along with all remaining bytes of the field.
+func (r *fastReader) appendInMemory(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	if r.fieldCopied {
+		r.recordBuf = append(r.recordBuf, p...)
+		return
+	}
+
+	if len(r.fieldAlias) == 0 {
+		r.fieldAlias = p
+		return
+	}
+
+	// every slice of the input shares the capacity of the input, so p
+	// directly follows the span when the capacity remaining after the span
+	// is the capacity of p
+	if cap(r.fieldAlias)-len(r.fieldAlias) == cap(p) {
+		r.fieldAlias = r.fieldAlias[:len(r.fieldAlias)+len(p)]
+		return
+	}
+
+	r.recordBuf = append(r.recordBuf, r.fieldAlias...)
+	r.recordBuf = append(r.recordBuf, p...)
+	r.fieldAlias = nil
+	r.fieldCopied = true
+}
+
+// endInMemoryField records the length of the field being parsed along with
+// the offset of its span within the input, or -1 when it was copied into the
+// record buffer.
+func (r *fastReader) endInMemoryField() {
+	if r.fieldCopied {
+		r.fieldLengths = append(r.fieldLengths, len(r.recordBuf)-r.fieldStart)
+		r.fieldAliases = append(r.fieldAliases, -1)
+		r.fieldCopied = false
+		return
+	}
+
+	n := len(r.fieldAlias)
+	r.fieldLengths = append(r.fieldLengths, n)
+	r.fieldAliases = append(r.fieldAliases, len(r.inMemoryData)-cap(r.fieldAlias))
+	r.aliasedRecordBytes += n
+	r.fieldAlias = nil
+}
+
+// inMemoryFields fills dst with the fields of the current record where s is
+// a string representation of the record buffer which holds the fields that
+// do not alias the input.
+func (r *fastReader) inMemoryFields(dst []string, s string) {
+	var p int
+	for i, n := range r.fieldLengths {
+		if a := r.fieldAliases[i]; a >= 0 {
+			if n == 0 {
+				dst[i] = ""
+				continue
+			}
+
+			dst[i] = unsafe.String(&r.inMemoryData[a], n)
+			continue
+		}
+
+		dst[i] = s[p : p+n]
+		p += n
+	}
+}
+
+// materializeInMemory copies the fields of the current record which alias
+// the input into the record buffer such that it holds every field in order,
+// as it would for readers which do not alias the input.
+func (r *fastReader) materializeInMemory() {
+	if !r.aliasFields {
+		return
+	}
+
+	buf := make([]byte, 0, len(r.recordBuf)+r.aliasedRecordBytes)
+
+	var p int
+	for i, n := range r.fieldLengths {
+		if a := r.fieldAliases[i]; a >= 0 {
+			buf = append(buf, r.inMemoryData[a:a+n]...)
+			r.fieldAliases[i] = -1
+			continue
+		}
+
+		buf = append(buf, r.recordBuf[p:p+n]...)
+		p += n
+	}
+
+	r.recordBuf = append(r.recordBuf[:0], buf...)
+	r.aliasedRecordBytes = 0
+}
+
+// newInMemoryRow returns the row strategy of readers which alias fields of
+// the input.
+func (r *fastReader) newInMemoryRow(borrowRow, borrowFields bool) func() []string {
+	return func() []string {
+		if len(r.fieldLengths) != r.numFields || r.scanErr != nil {
+			return nil
+		}
+
+		var row []string
+		if borrowRow && len(r.rowBuf) == len(r.fieldLengths) {
+			row = r.rowBuf
+		} else {
+			row = make([]string, len(r.fieldLengths))
+			if borrowRow {
+				r.rowBuf = row
+			}
+		}
+
+		var s string
+		if borrowFields {
+			s = unsafe.String(unsafe.SliceData(r.recordBuf), len(r.recordBuf))
+		} else if len(r.recordBuf) > 0 {
+			s = string(r.recordBuf)
+		}
+
+		r.inMemoryFields(row, s)
+
+		return row
+	}
+}
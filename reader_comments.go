@@ -0,0 +1,38 @@
+package csv
+
+// appendComment buffers bytes of the comment line being parsed.
+//
+// Comments are only buffered when there is an OnComment callback to report
+// them to or they precede the first record and RetainComments is enabled.
+func (r *fastReader) appendComment(p []byte) {
+	r.numCommentBytes += uint64(len(p))
+
+	if r.onComment == nil && (!r.retainComments || (r.bitFlags&stAfterSOR) != 0) {
+		return
+	}
+
+	r.commentBuf = append(r.commentBuf, p...)
+}
+
+// emitComment reports the buffered comment line and then resets the buffer.
+func (r *fastReader) emitComment() {
+	r.numComments++
+
+	if r.retainComments && (r.bitFlags&stAfterSOR) == 0 {
+		r.pr.comments = append(r.pr.comments, string(r.commentBuf))
+	}
+
+	if r.onComment != nil {
+		r.onComment(r.commentBuf, r.recordIndex)
+	}
+
+	r.commentBuf = r.commentBuf[:0]
+}
+
+// clearComments discards the retained comments.
+//
+// The strings are not zeroed since they may still be referenced by the
+// caller and strings must never change.
+func (r *readerStrat) clearComments() {
+	r.comments = nil
+}
@@ -0,0 +1,38 @@
+package csv
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader fails every read once its context is done so that the
+// Reader stops at the next refill of its raw buffer.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Read(p)
+}
+
+// newContextScan returns a scan strategy that checks ctx before each record
+// is parsed, failing the scan with an io error wrapping ctx.Err() once the
+// context is done.
+func (r *fastReader) newContextScan(ctx context.Context, next func() bool) func() bool {
+	return r.pr.persistentScan(next, func(scan func() bool) bool {
+		if (r.bitFlags & stDone) == 0 {
+			if err := ctx.Err(); err != nil {
+				r.setDone()
+				r.ioErr(err)
+				return false
+			}
+		}
+
+		return scan()
+	})
+}
@@ -0,0 +1,171 @@
+package csv
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// Compression identifies how an input stream is compressed.
+type Compression uint8
+
+const (
+	// CompressionNone reads the input stream as is.
+	CompressionNone Compression = iota
+	// CompressionAuto sniffs the leading magic bytes of the input stream to
+	// select gzip, bzip2, zlib, or no decompression.
+	//
+	// A zlib stream has only a two byte header which can be mistaken for
+	// plain text starting with 'x' followed by one of a handful of
+	// punctuation runes. Prefer CompressionZlib when the format is known.
+	CompressionAuto
+	// CompressionGzip decompresses a gzip stream of one or more members.
+	CompressionGzip
+	// CompressionBzip2 decompresses a bzip2 stream.
+	CompressionBzip2
+	// CompressionZlib decompresses a zlib stream.
+	CompressionZlib
+)
+
+// decompressionRatioMinBytes is the number of decompressed bytes that must be
+// produced before the MaxDecompressionRatio limit is enforced so that the
+// fixed size of compression headers and read-ahead buffering cannot cause
+// false positives on small inputs.
+const decompressionRatioMinBytes = 1 << 20
+
+// secOpReadErr classifies an error returned by the underlying reader as a
+// security error rather than an io error.
+type secOpReadErr struct {
+	err error
+}
+
+func (e secOpReadErr) Error() string {
+	return e.err.Error()
+}
+
+func (e secOpReadErr) Unwrap() error {
+	return e.err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// decompressReader lazily selects and constructs a decompressor on the first
+// read so that constructing a Reader never blocks on the input stream.
+type decompressReader struct {
+	src         countingReader
+	r           io.Reader
+	err         error
+	n           int64
+	maxBytes    int64
+	maxRatio    float64
+	compression Compression
+}
+
+func newDecompressReader(r io.Reader, c Compression, maxBytes int64, maxRatio float64) *decompressReader {
+	return &decompressReader{
+		src:         countingReader{r: r},
+		compression: c,
+		maxBytes:    maxBytes,
+		maxRatio:    maxRatio,
+	}
+}
+
+func (dr *decompressReader) init() error {
+	c := dr.compression
+
+	var src io.Reader = &dr.src
+	if c == CompressionAuto {
+		br := bufio.NewReader(src)
+		src = br
+
+		magic, err := br.Peek(3)
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		c = sniffCompression(magic)
+	}
+
+	switch c {
+	case CompressionGzip:
+		r, err := gzip.NewReader(src)
+		if err != nil {
+			return err
+		}
+		dr.r = r
+	case CompressionBzip2:
+		dr.r = bzip2.NewReader(src)
+	case CompressionZlib:
+		r, err := zlib.NewReader(src)
+		if err != nil {
+			return err
+		}
+		dr.r = r
+	default:
+		dr.r = src
+	}
+
+	return nil
+}
+
+func sniffCompression(magic []byte) Compression {
+	if len(magic) >= 2 {
+		if magic[0] == 0x1f && magic[1] == 0x8b {
+			return CompressionGzip
+		}
+
+		// deflate method, window no larger than 32KiB, no preset dictionary,
+		// and a valid header checksum
+		if cmf, flg := magic[0], magic[1]; (cmf&0x0f) == 8 && (cmf>>4) <= 7 && (flg&0x20) == 0 && (uint16(cmf)<<8|uint16(flg))%31 == 0 {
+			return CompressionZlib
+		}
+	}
+
+	if len(magic) >= 3 && magic[0] == 'B' && magic[1] == 'Z' && magic[2] == 'h' {
+		return CompressionBzip2
+	}
+
+	return CompressionNone
+}
+
+func (dr *decompressReader) Read(p []byte) (int, error) {
+	if dr.err != nil {
+		return 0, dr.err
+	}
+
+	if dr.r == nil {
+		if err := dr.init(); err != nil {
+			dr.err = err
+			return 0, err
+		}
+	}
+
+	n, err := dr.r.Read(p)
+	dr.n += int64(n)
+
+	if dr.maxBytes > 0 && dr.n > dr.maxBytes {
+		n -= int(dr.n - dr.maxBytes)
+		dr.n = dr.maxBytes
+		dr.err = secOpReadErr{ErrSecOpDecompressedBytesAboveMax}
+		return n, dr.err
+	}
+
+	if dr.maxRatio > 0 && dr.n >= decompressionRatioMinBytes && float64(dr.n) > float64(dr.src.n)*dr.maxRatio {
+		dr.err = secOpReadErr{ErrSecOpDecompressionRatioAboveMax}
+		return n, dr.err
+	}
+
+	return n, err
+}
@@ -0,0 +1,104 @@
+package csv
+
+// newFirstCheckNumFields returns a checkNumFields strategy for the first
+// record that discovers the number of fields per record when not already
+// known and then defers to next for every record thereafter.
+func (r *secOpReader) newFirstCheckNumFields(next func(error) bool) func(error) bool {
+	return func(errTrailer error) bool {
+		if r.numFields == -1 {
+			r.numFields = len(r.fieldLengths)
+		}
+
+		if !next(errTrailer) {
+			return false
+		}
+
+		r.bitFlags |= stAfterSOR
+		r.checkNumFields = next
+		return true
+	}
+}
+
+func (r *secOpReader) checkNumFieldsWithPadding(errTrailer error) bool {
+	if n := len(r.fieldLengths); n < r.numFields {
+		for range r.numFields - n {
+			r.fieldLengths = append(r.fieldLengths, 0)
+		}
+		r.pr.adjustedRows++
+	}
+
+	return r.defaultCheckNumFields(errTrailer)
+}
+
+func (r *secOpReader) checkNumFieldsWithTruncation(errTrailer error) bool {
+	if len(r.fieldLengths) > r.numFields {
+		// bytes of the discarded fields were never buffered
+		// so only the lengths need to be dropped
+		r.fieldLengths = r.fieldLengths[:r.numFields]
+		r.pr.adjustedRows++
+	}
+
+	return r.defaultCheckNumFields(errTrailer)
+}
+
+// newFlexibleCheckNumFields returns a checkNumFields strategy that accepts
+// any number of fields per record.
+//
+// numFields is updated to the length of each record so the row strategies
+// continue to operate as they would for a fixed number of fields.
+func (r *secOpReader) newFlexibleCheckNumFields(expNumFields int) func(error) bool {
+	initialExpNumFields := expNumFields
+	r.pr.onReset(func() {
+		expNumFields = initialExpNumFields
+	})
+
+	return func(_ error) bool {
+		n := len(r.fieldLengths)
+		if expNumFields == -1 {
+			expNumFields = n
+		} else if n != expNumFields {
+			r.pr.adjustedRows++
+		}
+
+		r.numFields = n
+		if r.rowBuf != nil {
+			if cap(r.rowBuf) < n {
+				r.rowBuf = make([]string, n)
+			} else {
+				r.rowBuf = r.rowBuf[:n]
+			}
+		}
+
+		r.bitFlags |= stAfterSOR
+		return true
+	}
+}
+
+// truncatedAppendRecBuf wraps a record buffer append strategy such that bytes
+// belonging to fields beyond the expected number of fields are discarded.
+func (r *secOpReader) truncatedAppendRecBuf(next func([]byte) bool) func([]byte) bool {
+	return func(p []byte) bool {
+		if r.numFields != -1 && len(r.fieldLengths) >= r.numFields {
+			return false
+		}
+
+		return next(p)
+	}
+}
+
+// fieldNumOverflowMaxCheckOnly only enforces MaxFields since the number of
+// fields in a record is allowed to exceed the expected number of fields.
+func (r *secOpReader) fieldNumOverflowMaxCheckOnly(max uint) func() bool {
+	return func() bool {
+		if uint(len(r.fieldLengths)) == max {
+			r.secOpStreamParsingErr(errTooManyFieldsAboveMax{})
+			return true
+		}
+
+		return false
+	}
+}
+
+func (r *secOpReader) nopFieldNumOverflow() bool {
+	return false
+}
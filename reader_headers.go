@@ -0,0 +1,267 @@
+package csv
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HeaderColumn identifies a header name and the zero based position it
+// relates to within a HeaderMismatchError.
+type HeaderColumn struct {
+	Name  string
+	Index int
+}
+
+// HeaderMismatchError describes how a header row failed to satisfy the
+// ExpectHeaders or RequireHeaders options.
+//
+// Missing contains the expected or required names that were not found where
+// Index is the position of the name within the option's list.
+//
+// Unexpected and Duplicate contain header row values where Index is the
+// column the value was found in.
+//
+// It always satisfies errors.Is(err, ErrUnexpectedHeaderRowContents).
+type HeaderMismatchError struct {
+	Missing    []HeaderColumn
+	Unexpected []HeaderColumn
+	Duplicate  []HeaderColumn
+}
+
+func (e HeaderMismatchError) Is(target error) bool {
+	return errors.Is(ErrUnexpectedHeaderRowContents, target)
+}
+
+func (e HeaderMismatchError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(ErrUnexpectedHeaderRowContents.Error())
+
+	sep := ": "
+	appendColumns := func(kind string, cols []HeaderColumn) {
+		for _, c := range cols {
+			sb.WriteString(sep)
+			sep = "; "
+
+			sb.WriteString(kind)
+			sb.WriteString(" header ")
+			sb.WriteString(strconv.Quote(c.Name))
+			sb.WriteString(" at index ")
+			sb.WriteString(strconv.Itoa(c.Index))
+		}
+	}
+
+	appendColumns("missing", e.Missing)
+	appendColumns("unexpected", e.Unexpected)
+	appendColumns("duplicate", e.Duplicate)
+
+	return sb.String()
+}
+
+// headerMatcher resolves a header row against the ExpectHeaders or
+// RequireHeaders options as well as any aliases and case folding rules.
+//
+// A nil headerMatcher accepts any header row.
+type headerMatcher struct {
+	names []string
+	// keys contains the values that are accepted for each name including
+	// the name itself
+	keys [][]string
+	// lookup maps every key to the index of the name it belongs to
+	//
+	// it is only used when the header columns are unordered
+	lookup     map[string]int
+	ordered    bool
+	allowExtra bool
+	foldCase   bool
+}
+
+func newHeaderMatcher(cfg *rCfg, names []string) (*headerMatcher, error) {
+	if names == nil {
+		return nil, nil
+	}
+
+	hm := &headerMatcher{
+		names:      names,
+		keys:       make([][]string, len(names)),
+		ordered:    cfg.requiredHeaders == nil,
+		allowExtra: cfg.allowExtraHeaders,
+		foldCase:   cfg.caseInsensitiveHeaders,
+	}
+
+	nameIdx := make(map[string]int, len(names))
+	for i, v := range names {
+		hm.keys[i] = []string{v}
+		if _, ok := nameIdx[v]; !ok {
+			nameIdx[v] = i
+		}
+	}
+
+	for name, aliases := range cfg.headerAliases {
+		if cfg.trimHeaders {
+			name = strings.TrimSpace(name)
+		}
+
+		i, ok := nameIdx[name]
+		if !ok {
+			return nil, errors.New("header alias key does not match an expected or required header: " + strconv.Quote(name))
+		}
+
+		for _, v := range aliases {
+			if cfg.trimHeaders {
+				v = strings.TrimSpace(v)
+			}
+			hm.keys[i] = append(hm.keys[i], v)
+		}
+	}
+
+	if hm.ordered {
+		return hm, nil
+	}
+
+	hm.lookup = make(map[string]int, len(names))
+	for i, keys := range hm.keys {
+		for _, v := range keys {
+			k := hm.key(v)
+			if j, ok := hm.lookup[k]; ok {
+				if j != i {
+					return nil, errors.New("required header names and aliases must be unique: " + strconv.Quote(v))
+				}
+
+				continue
+			}
+			hm.lookup[k] = i
+		}
+	}
+
+	return hm, nil
+}
+
+// key returns the lookup map key of s, which is the same for all values
+// equal per equal.
+func (hm *headerMatcher) key(s string) string {
+	if hm.foldCase {
+		return foldCase(s)
+	}
+	return s
+}
+
+func (hm *headerMatcher) equal(a, b string) bool {
+	if hm.foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+func (hm *headerMatcher) accepts(i int, s string) bool {
+	for _, v := range hm.keys[i] {
+		if hm.equal(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldCase maps every rune of s to the smallest rune of its Unicode simple
+// case folding orbit so that two strings have the same result exactly when
+// strings.EqualFold reports them as equal.
+func foldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		m := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			m = min(m, f)
+		}
+		return m
+	}, s)
+}
+
+// match verifies the header row values and returns the resolved column
+// index of each header name.
+//
+// Columns matching an expected or required header are reported under that
+// header's name while all other columns are reported under their literal
+// value with the first occurrence taking precedence.
+func (hm *headerMatcher) match(row []string) (map[string]int, error) {
+	columns := make(map[string]int, len(row))
+
+	if hm == nil {
+		for i, v := range row {
+			if _, ok := columns[v]; !ok {
+				columns[v] = i
+			}
+		}
+		return columns, nil
+	}
+
+	var e HeaderMismatchError
+
+	if hm.ordered {
+		// the field count check performed before this point guarantees that
+		// the row length matches the number of expected headers unless the
+		// FieldCountPolicy allows otherwise
+
+		for i, v := range row {
+			if i >= len(hm.names) {
+				e.Unexpected = append(e.Unexpected, HeaderColumn{v, i})
+				continue
+			}
+
+			if hm.accepts(i, v) {
+				if _, ok := columns[hm.names[i]]; !ok {
+					columns[hm.names[i]] = i
+				}
+				continue
+			}
+
+			e.Missing = append(e.Missing, HeaderColumn{hm.names[i], i})
+			e.Unexpected = append(e.Unexpected, HeaderColumn{v, i})
+
+			for j := range i {
+				if hm.equal(row[j], v) {
+					e.Duplicate = append(e.Duplicate, HeaderColumn{v, i})
+					break
+				}
+			}
+		}
+
+		for i := len(row); i < len(hm.names); i++ {
+			e.Missing = append(e.Missing, HeaderColumn{hm.names[i], i})
+		}
+	} else {
+		found := make([]bool, len(hm.names))
+
+		for i, v := range row {
+			j, ok := hm.lookup[hm.key(v)]
+			if !ok {
+				if !hm.allowExtra {
+					e.Unexpected = append(e.Unexpected, HeaderColumn{v, i})
+				} else if _, ok := columns[v]; !ok {
+					columns[v] = i
+				}
+				continue
+			}
+
+			if found[j] {
+				e.Duplicate = append(e.Duplicate, HeaderColumn{v, i})
+				continue
+			}
+			found[j] = true
+
+			columns[hm.names[j]] = i
+		}
+
+		for j, ok := range found {
+			if !ok {
+				e.Missing = append(e.Missing, HeaderColumn{hm.names[j], j})
+			}
+		}
+	}
+
+	if e.Missing != nil || e.Unexpected != nil || e.Duplicate != nil {
+		return nil, e
+	}
+
+	return columns, nil
+}
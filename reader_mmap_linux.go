@@ -0,0 +1,68 @@
+package csv
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// OpenMmap memory maps the file at path read-only and returns a Reader over
+// its contents as described by NewBytesReader.
+//
+// Close unmaps the file. Unlike NewBytesReader, fields returned by the
+// Reader alias the mapping and must not be used after Close is called, doing
+// so will crash the program.
+func OpenMmap(path string, options ...ReaderOption) (Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := fi.Size()
+	if size != int64(int(size)) {
+		return nil, errors.New("file is too large to memory map: " + path)
+	}
+
+	var data []byte
+	if size > 0 {
+		data, err = syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+		}
+	}
+
+	cr, err := NewBytesReader(data, options...)
+	if err != nil {
+		if data != nil {
+			_ = syscall.Munmap(data)
+		}
+		return nil, err
+	}
+
+	if data == nil {
+		return cr, nil
+	}
+
+	rs := cr.(*readerStrat)
+	next := rs.close
+	rs.close = func() error {
+		err := next()
+
+		if data != nil {
+			if mErr := syscall.Munmap(data); mErr != nil {
+				err = errors.Join(err, &os.PathError{Op: "munmap", Path: path, Err: mErr})
+			}
+			data = nil
+		}
+
+		return err
+	}
+
+	return cr, nil
+}
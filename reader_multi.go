@@ -0,0 +1,342 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+)
+
+// SourceError identifies which source of a MultiReader an error relates to.
+//
+// Err retains the byte, record, and field positions of the error relative to
+// the start of that source.
+type SourceError struct {
+	// Index is the zero based position of the source within the sequence
+	// provided to NewMultiReader.
+	Index int
+	// Name is the value returned by the source's Name method, such as the
+	// path of an *os.File, or empty if the source has no Name method.
+	Name string
+	Err  error
+}
+
+func (e SourceError) Error() string {
+	s := "source " + strconv.Itoa(e.Index)
+	if e.Name != "" {
+		s += " (" + e.Name + ")"
+	}
+	return s + ": " + e.Err.Error()
+}
+
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// MultiReader reads a sequence of CSV documents which share the same header
+// row as a single stream of records.
+//
+// It implements ExtendedReader. Errors returned by Err are wrapped in a
+// SourceError.
+type MultiReader struct {
+	r             *readerStrat
+	next          func() (io.Reader, bool)
+	stop          func()
+	header        []string
+	prevStats     ReaderStats
+	err           error
+	sourceName    string
+	sourceIndex   int
+	numRecords    uint64
+	numSrcRecords uint64
+	// matchHeader is true when the reader is not configured with ExpectHeaders
+	// or RequireHeaders and so each header row must match the first source
+	matchHeader bool
+	// dropHeader is true when the parsing strategy returns header rows as
+	// records because RemoveHeaderRow was not enabled
+	dropHeader bool
+	skipHeader bool
+	done       bool
+}
+
+// NewMultiReader creates a Reader which parses each source in turn as if all
+// records came from one document.
+//
+// The first record of every source is treated as a header row. When the
+// options include ExpectHeaders or RequireHeaders each header row is
+// validated against them, otherwise each header row must exactly match the
+// header row of the first source. Header rows of every source after the
+// first are never returned by Scan, and the first is only returned when
+// RemoveHeaderRow is not enabled.
+//
+// All other options apply to each source individually, so options such as
+// SkipLeadingLines or DropTrailingRecords are useful to remove per-file
+// preambles and footers. The Reader option must not be specified.
+//
+// Sources are pulled from the sequence only as they are needed and are never
+// closed by the MultiReader. A sequence which opens files may close each one
+// once yield returns.
+func NewMultiReader(sources iter.Seq[io.Reader], options ...ReaderOption) (*MultiReader, error) {
+	{
+		var cfg rCfg
+		for _, f := range options {
+			f(&cfg)
+		}
+
+		if cfg.reader != nil || cfg.dataSet {
+			return nil, errors.Join(ErrBadConfig, errors.New("a reader cannot be specified when reading from multiple sources"))
+		}
+	}
+
+	next, stop := iter.Pull(sources)
+
+	src, ok := next()
+	if !ok {
+		stop()
+		return nil, errors.Join(ErrBadConfig, errors.New("no sources"))
+	}
+
+	mr := &MultiReader{
+		next:       next,
+		stop:       stop,
+		sourceName: sourceName(src),
+	}
+
+	options = append(options[:len(options):len(options)], ReaderOpts().Reader(src), func(cfg *rCfg) {
+		cfg.onHeaderRow = mr.checkHeader
+		mr.matchHeader = cfg.headers == nil && cfg.requiredHeaders == nil
+		mr.dropHeader = !cfg.removeHeaderRow
+	})
+
+	r, _, err := internalNewReader(options...)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	mr.r = r.(*readerStrat)
+
+	return mr, nil
+}
+
+func sourceName(r io.Reader) string {
+	if v, ok := r.(interface{ Name() string }); ok {
+		return v.Name()
+	}
+
+	return ""
+}
+
+// Scan advances to the next record across all sources, returning false once
+// every source has been read or an error occurs.
+func (mr *MultiReader) Scan() bool {
+	for !mr.done {
+		if !mr.r.Scan() {
+			if err := mr.r.Err(); err != nil {
+				mr.fail(err)
+				return false
+			}
+
+			if !mr.nextSource() {
+				return false
+			}
+
+			continue
+		}
+
+		if mr.skipHeader {
+			mr.skipHeader = false
+			continue
+		}
+
+		mr.numRecords++
+		mr.numSrcRecords++
+		return true
+	}
+
+	return false
+}
+
+// checkHeader is invoked by the parsing strategy with the header row of
+// each source before it is matched against any ExpectHeaders or
+// RequireHeaders options.
+func (mr *MultiReader) checkHeader(header []string) error {
+	mr.skipHeader = mr.dropHeader && mr.sourceIndex > 0
+
+	if !mr.matchHeader {
+		return nil
+	}
+
+	if mr.sourceIndex == 0 {
+		mr.header = slices.Clone(header)
+		return nil
+	}
+
+	if slices.Equal(header, mr.header) {
+		return nil
+	}
+
+	var e HeaderMismatchError
+	for i := range max(len(header), len(mr.header)) {
+		if i < len(mr.header) && (i >= len(header) || header[i] != mr.header[i]) {
+			e.Missing = append(e.Missing, HeaderColumn{mr.header[i], i})
+		}
+		if i < len(header) && (i >= len(mr.header) || header[i] != mr.header[i]) {
+			e.Unexpected = append(e.Unexpected, HeaderColumn{header[i], i})
+		}
+	}
+
+	return e
+}
+
+// nextSource resets the parsing strategy to read from the next source.
+func (mr *MultiReader) nextSource() bool {
+	src, ok := mr.next()
+	if !ok {
+		mr.done = true
+		return false
+	}
+
+	s := mr.Stats()
+
+	mr.sourceIndex++
+	mr.sourceName = sourceName(src)
+
+	if err := mr.r.Reset(src); err != nil {
+		mr.fail(err)
+		return false
+	}
+
+	s.Records = 0
+	mr.prevStats = s
+	mr.numSrcRecords = 0
+	mr.skipHeader = false
+	return true
+}
+
+func (mr *MultiReader) fail(err error) {
+	mr.done = true
+	mr.err = SourceError{
+		Index: mr.sourceIndex,
+		Name:  mr.sourceName,
+		Err:   err,
+	}
+}
+
+// Row returns the fields of the current record, see Reader.Row.
+func (mr *MultiReader) Row() []string {
+	return mr.r.Row()
+}
+
+// Err returns the first error encountered as a SourceError or nil.
+func (mr *MultiReader) Err() error {
+	return mr.err
+}
+
+// Close closes the parsing strategy and stops pulling from the sequence of
+// sources.
+func (mr *MultiReader) Close() error {
+	mr.done = true
+	mr.stop()
+	return mr.r.Close()
+}
+
+// ColumnMap returns the column index of each header name of the current
+// source, see ExtendedReader.ColumnMap.
+func (mr *MultiReader) ColumnMap() map[string]int {
+	return mr.r.ColumnMap()
+}
+
+// AdjustedRows returns the number of records of the current source adjusted
+// per the FieldCountPolicy option.
+func (mr *MultiReader) AdjustedRows() uint64 {
+	return mr.r.AdjustedRows()
+}
+
+// Comments returns the leading comment lines of the current source.
+func (mr *MultiReader) Comments() []string {
+	return mr.r.Comments()
+}
+
+// Metadata returns the metadata of the current source, see
+// ExtendedReader.Metadata.
+func (mr *MultiReader) Metadata() map[string]string {
+	return mr.r.Metadata()
+}
+
+// Stats returns a summary of the work performed across all sources so far.
+//
+// RecordSeparator and ByteOrderMarker describe the current source.
+func (mr *MultiReader) Stats() ReaderStats {
+	s := mr.r.Stats()
+	p := mr.prevStats
+
+	s.Records = mr.numRecords
+	s.Bytes += p.Bytes
+	s.CommentLines += p.CommentLines
+	s.CommentBytes += p.CommentBytes
+	s.BufferRefills += p.BufferRefills
+	s.RecordBufferGrowths += p.RecordBufferGrowths
+	s.MaxRecordBytes = max(s.MaxRecordBytes, p.MaxRecordBytes)
+
+	return s
+}
+
+// Reset discards all state and prepares the MultiReader to read reader as
+// its only source.
+func (mr *MultiReader) Reset(reader io.Reader) error {
+	if err := mr.r.Reset(reader); err != nil {
+		return err
+	}
+
+	mr.stop()
+	mr.next, mr.stop = iter.Pull(func(func(io.Reader) bool) {})
+	mr.header = nil
+	mr.prevStats = ReaderStats{}
+	mr.err = nil
+	mr.sourceName = sourceName(reader)
+	mr.sourceIndex = 0
+	mr.numRecords = 0
+	mr.numSrcRecords = 0
+	mr.skipHeader = false
+	mr.done = false
+
+	return nil
+}
+
+// IntoIter converts the reader state into an iterator, see Reader.IntoIter.
+func (mr *MultiReader) IntoIter() iter.Seq[[]string] {
+	return mr.iter
+}
+
+func (mr *MultiReader) iter(yield func([]string) bool) {
+	for mr.Scan() {
+		if !yield(mr.r.Row()) {
+			return
+		}
+	}
+}
+
+// SourceIndex returns the zero based position of the current source within
+// the sequence of sources.
+func (mr *MultiReader) SourceIndex() int {
+	return mr.sourceIndex
+}
+
+// SourceName returns the name of the current source, see SourceError.Name.
+func (mr *MultiReader) SourceName() string {
+	return mr.sourceName
+}
+
+// RecordNumber returns the one based number of the current record across all
+// sources, or zero before the first call to Scan returns true.
+func (mr *MultiReader) RecordNumber() uint64 {
+	return mr.numRecords
+}
+
+// SourceRecordNumber returns the one based number of the current record
+// within the current source, or zero before the first call to Scan for that
+// source returns true.
+func (mr *MultiReader) SourceRecordNumber() uint64 {
+	return mr.numSrcRecords
+}
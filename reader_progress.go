@@ -0,0 +1,139 @@
+package csv
+
+import (
+	"io"
+	"time"
+)
+
+// ProgressInterval controls how often a Progress callback is invoked.
+//
+// A report is made once at least Bytes bytes have been consumed or Records
+// records have been returned by Scan since the previous report, whichever
+// happens first. A zero value for either field disables that trigger.
+type ProgressInterval struct {
+	Bytes   uint64
+	Records uint64
+}
+
+// Progress is a snapshot of how far a Reader has advanced through a
+// document.
+type Progress struct {
+	// ByteIndex is the number of bytes of the document consumed so far.
+	ByteIndex uint64
+	// RecordIndex is the number of records returned by Scan so far.
+	RecordIndex uint64
+	// TotalBytes is the size of the document in bytes, or -1 when it is
+	// unknown.
+	//
+	// The size is known when reading from a byte slice or when the
+	// underlying reader implements io.Seeker, such as an *os.File.
+	TotalBytes int64
+	// Elapsed is the time since the first call to Scan.
+	Elapsed time.Duration
+	// BytesPerSecond is the average throughput since the first call to
+	// Scan.
+	BytesPerSecond float64
+	// Remaining is the estimated time until the document is fully
+	// consumed based on BytesPerSecond. It is zero when TotalBytes is
+	// unknown or no throughput has been measured yet.
+	Remaining time.Duration
+	// Done is true for the final report made when Scan returns false.
+	Done bool
+}
+
+// seekerSize returns the number of bytes remaining in r from its current
+// offset or -1 if that cannot be determined.
+//
+// The offset of r is restored before returning.
+func seekerSize(r io.Reader) int64 {
+	s, ok := r.(io.Seeker)
+	if !ok {
+		return -1
+	}
+
+	cur, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+
+	if _, err := s.Seek(cur, io.SeekStart); err != nil {
+		return -1
+	}
+
+	return end - cur
+}
+
+// newProgressScan returns a scan strategy that invokes f after each record
+// once the configured interval has elapsed, and once more when scanning
+// ends.
+//
+// Reports are only ever made after a record has been fully parsed and
+// before control returns to the caller of Scan.
+func (r *fastReader) newProgressScan(interval ProgressInterval, f func(Progress), next func() bool) func() bool {
+	var start time.Time
+	var numRecords uint64
+	var finished bool
+	nextByteIndex := interval.Bytes
+	nextRecordIndex := interval.Records
+
+	r.pr.onReset(func() {
+		start = time.Time{}
+		numRecords = 0
+		finished = false
+		nextByteIndex = interval.Bytes
+		nextRecordIndex = interval.Records
+	})
+
+	report := func(done bool) {
+		p := Progress{
+			ByteIndex:   r.byteIndex,
+			RecordIndex: numRecords,
+			TotalBytes:  r.totalBytes,
+			Elapsed:     time.Since(start),
+			Done:        done,
+		}
+
+		if p.Elapsed > 0 {
+			p.BytesPerSecond = float64(p.ByteIndex) / p.Elapsed.Seconds()
+
+			if r.totalBytes >= 0 && p.BytesPerSecond > 0 {
+				if remaining := r.totalBytes - int64(p.ByteIndex); remaining > 0 {
+					p.Remaining = time.Duration(float64(remaining) / p.BytesPerSecond * float64(time.Second))
+				}
+			}
+		}
+
+		f(p)
+	}
+
+	return r.pr.persistentScan(next, func(scan func() bool) bool {
+		if finished {
+			return scan()
+		}
+
+		if start.IsZero() {
+			start = time.Now()
+		}
+
+		if !scan() {
+			finished = true
+			report(true)
+			return false
+		}
+
+		numRecords++
+
+		if (interval.Bytes != 0 && r.byteIndex >= nextByteIndex) || (interval.Records != 0 && numRecords >= nextRecordIndex) {
+			nextByteIndex = r.byteIndex + interval.Bytes
+			nextRecordIndex = numRecords + interval.Records
+			report(false)
+		}
+
+		return true
+	})
+}
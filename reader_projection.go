@@ -0,0 +1,149 @@
+package csv
+
+import (
+	"slices"
+	"strconv"
+	"unsafe"
+)
+
+// selectColumns activates column projection so that only the fields at the
+// provided record indexes are appended to the record buffer and returned by
+// Row.
+//
+// it must only be called once the number of fields per record is known
+func (r *secOpReader) selectColumns(indexes []int) bool {
+	var maxIdx int
+	for _, v := range indexes {
+		if v >= r.numFields {
+			r.setDone()
+			r.parsingErr(errSelectedColumnIndexNotFound(v))
+			return false
+		}
+		maxIdx = max(maxIdx, v)
+	}
+
+	mask := make([]bool, maxIdx+1)
+	for _, v := range indexes {
+		mask[v] = true
+	}
+
+	r.projection = slices.Clone(indexes)
+	r.fieldMask = mask
+	r.fieldOffsets = make([]int, 0, r.numFields)
+	r.rowBuf = make([]string, len(indexes))
+
+	return true
+}
+
+// selectColumnsByName resolves the provided names to record indexes via the
+// column map of the header row and then activates column projection.
+func (r *secOpReader) selectColumnsByName(names []string, columns map[string]int) bool {
+	indexes := make([]int, len(names))
+	for i, v := range names {
+		idx, ok := columns[v]
+		if !ok {
+			r.setDone()
+			r.parsingErr(errSelectedColumnNameNotFound(v))
+			return false
+		}
+		indexes[i] = idx
+	}
+
+	return r.selectColumns(indexes)
+}
+
+func errSelectedColumnIndexNotFound(i int) error {
+	return wrapSelectedColumnNotFound("index " + strconv.Itoa(i))
+}
+
+func errSelectedColumnNameNotFound(s string) error {
+	return wrapSelectedColumnNotFound("name " + strconv.Quote(s))
+}
+
+type errSelectedColumn struct {
+	msg string
+}
+
+func wrapSelectedColumnNotFound(s string) error {
+	return errSelectedColumn{ErrSelectedColumnNotFound.Error() + ": " + s}
+}
+
+func (e errSelectedColumn) Is(target error) bool {
+	return target == ErrSelectedColumnNotFound
+}
+
+func (e errSelectedColumn) Error() string {
+	return e.msg
+}
+
+// projectedAppendRecBuf wraps a record buffer append strategy such that bytes
+// belonging to fields outside of the projection are discarded.
+//
+// The state machine records a zero length for every discarded field since
+// field lengths are derived from the growth of the record buffer.
+func (r *secOpReader) projectedAppendRecBuf(next func([]byte) bool) func([]byte) bool {
+	return func(p []byte) bool {
+		if r.fieldMask != nil {
+			if i := len(r.fieldLengths); i >= len(r.fieldMask) || !r.fieldMask[i] {
+				return false
+			}
+		}
+
+		return next(p)
+	}
+}
+
+// projectRow fills dst with the projected fields of the current record where
+// s is a string representation of the record buffer.
+func (r *fastReader) projectRow(dst []string, s string) []string {
+	r.fieldOffsets = r.fieldOffsets[:0]
+
+	var p int
+	for _, n := range r.fieldLengths {
+		r.fieldOffsets = append(r.fieldOffsets, p)
+		p += n
+	}
+
+	for i, idx := range r.projection {
+		p := r.fieldOffsets[idx]
+		dst[i] = s[p : p+r.fieldLengths[idx]]
+	}
+
+	return dst
+}
+
+func (r *fastReader) projectedRow() []string {
+	if r.fieldLengths == nil || len(r.fieldLengths) != r.numFields || r.scanErr != nil {
+		return nil
+	}
+
+	r.pr.row = r.projectedClonedRow
+	return r.pr.row()
+}
+
+func (r *fastReader) projectedClonedRow() []string {
+	if len(r.fieldLengths) != r.numFields || r.scanErr != nil {
+		return nil
+	}
+
+	return r.projectRow(make([]string, len(r.projection)), string(r.recordBuf))
+}
+
+func (r *fastReader) projectedRowBorrowedAndFieldsCloned() []string {
+	if len(r.fieldLengths) != r.numFields || r.scanErr != nil {
+		return nil
+	}
+
+	return r.projectRow(r.rowBuf, string(r.recordBuf))
+}
+
+func (r *fastReader) projectedRowBorrowedAndFieldsBorrowed() []string {
+	if len(r.fieldLengths) != r.numFields || r.scanErr != nil {
+		return nil
+	}
+
+	// Usage of unsafe here is what empowers borrowing.
+	//
+	// See setRowBorrowedAndFieldsBorrowed for the full usage contract.
+	return r.projectRow(r.rowBuf, unsafe.String(unsafe.SliceData(r.recordBuf), len(r.recordBuf)))
+}
@@ -0,0 +1,105 @@
+package csv
+
+import (
+	"errors"
+	"io"
+)
+
+// readerSnapshot is the parsing state of a Reader as it was at the end of
+// initialization which Reset restores in place.
+type readerSnapshot struct {
+	fr   fastReader
+	sr   secOpReader
+	scan func() bool
+	row  func() []string
+	// hooks restore the state captured by strategy closures
+	hooks []func()
+}
+
+// onReset registers f to be called by Reset after the parsing state has been
+// restored.
+//
+// Strategies which capture mutable state in their closures must register a
+// hook which restores that state to its initial value.
+func (r *readerStrat) onReset(f func()) {
+	r.snapshot.hooks = append(r.snapshot.hooks, f)
+}
+
+// Reset discards all parsing state and prepares the Reader to parse a new
+// document from reader using the same configuration.
+//
+// Discovered field counts, header handling, byte order marker detection,
+// statistics, and any error or closed state are reinitialized while the
+// buffers allocated by the Reader are kept for reuse. When
+// ClearFreedDataMemory is enabled the buffers are zeroed before they are
+// reused.
+//
+// Rows, fields, and comments borrowed from the previous document must not be
+// used after calling Reset.
+//
+// Reset returns ErrResetNotSupported when the Reader was created by
+// NewBytesReader.
+func (r *readerStrat) Reset(reader io.Reader) error {
+	return r.reset(reader)
+}
+
+func (r *readerStrat) newReset(cfg rCfg, fr *fastReader, sr *secOpReader) func(io.Reader) error {
+	if cfg.dataSet {
+		return func(io.Reader) error {
+			return ErrResetNotSupported
+		}
+	}
+
+	r.snapshot.fr = *fr
+	if sr != nil {
+		r.snapshot.sr = *sr
+	}
+	r.snapshot.scan = r.scan
+	r.snapshot.row = r.row
+
+	return func(reader io.Reader) error {
+		if reader == nil {
+			return errors.Join(ErrBadConfig, ErrNilReader)
+		}
+
+		if cfg.clearMemoryAfterFree {
+			sr.zeroRecordBuffers()
+		}
+
+		rawBuf := fr.rawBuf[:0]
+		recordBuf := fr.recordBuf[:0]
+		commentBuf := fr.commentBuf[:0]
+		fieldLengths := fr.fieldLengths[:0]
+		fieldAliases := fr.fieldAliases[:0]
+		rowBuf := fr.rowBuf
+
+		*fr = r.snapshot.fr
+		if sr != nil {
+			*sr = r.snapshot.sr
+		}
+
+		fr.rawBuf = rawBuf
+		fr.recordBuf = recordBuf
+		fr.recordBufCap = cap(recordBuf)
+		fr.commentBuf = commentBuf
+		fr.fieldLengths = fieldLengths
+		fr.fieldAliases = fieldAliases
+		if cfg.numFields > 0 && cap(rowBuf) >= cfg.numFields {
+			fr.rowBuf = rowBuf[:cfg.numFields]
+		}
+		fr.setReader(&cfg, reader)
+
+		r.scan = r.snapshot.scan
+		r.row = r.snapshot.row
+		r.columns = nil
+		r.comments = nil
+		r.adjustedRows = 0
+		r.numRecords = 0
+
+		for _, f := range r.snapshot.hooks {
+			f()
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,465 @@
+package csv
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// SchemaViolation describes a field which does not satisfy the Schema
+// reader option.
+//
+// Reader errors wrap it with the byte, record, and field position of the
+// violation such that it can be extracted with errors.As.
+type SchemaViolation struct {
+	// Record and Field are the one based positions of the field within the
+	// document as reported in error messages.
+	Record uint64
+	Field  uint
+	// Column is the ColumnSchema.Name of the field.
+	Column string
+	// Err is, or wraps, one of ErrSchemaRequired, ErrSchemaType,
+	// ErrSchemaLength, ErrSchemaRange, ErrSchemaEnum, ErrSchemaPattern, or
+	// ErrSchemaUnique.
+	Err error
+}
+
+func (e SchemaViolation) Error() string {
+	if e.Column == "" {
+		return e.Err.Error()
+	}
+
+	return "column " + strconv.Quote(e.Column) + ": " + e.Err.Error()
+}
+
+func (e SchemaViolation) Unwrap() error {
+	return e.Err
+}
+
+// schemaValue is a value parsed according to a ColumnType.
+type schemaValue struct {
+	i int64
+	u uint64
+	f float64
+	t time.Time
+	s string
+}
+
+// schemaColumn is a ColumnSchema prepared for validation.
+type schemaColumn struct {
+	ColumnSchema
+	enum           map[string]struct{}
+	missing        map[string]struct{}
+	min, max       schemaValue
+	hasMin, hasMax bool
+}
+
+// isMissing reports whether s represents a missing value.
+func (c *schemaColumn) isMissing(s string) bool {
+	if c.missing == nil {
+		return s == ""
+	}
+
+	_, ok := c.missing[s]
+	return ok
+}
+
+func (c *schemaColumn) parse(s string) (schemaValue, bool) {
+	var v schemaValue
+	var err error
+
+	switch c.Type {
+	case ColumnString:
+		v.s = s
+	case ColumnBool:
+		if s != "0" && s != "1" {
+			return v, false
+		}
+		v.i = int64(s[0] - '0')
+	case ColumnInt64:
+		v.i, err = strconv.ParseInt(s, 10, 64)
+	case ColumnUint64:
+		v.u, err = strconv.ParseUint(s, 10, 64)
+	case ColumnDecimal:
+		if !isDecimal(s) {
+			return v, false
+		}
+		v.f, err = strconv.ParseFloat(s, 64)
+	case ColumnFloat64:
+		v.f, err = strconv.ParseFloat(s, 64)
+	case ColumnTime:
+		v.t, err = time.Parse(c.Layout, s)
+	case ColumnDuration:
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		v.i = int64(d)
+	}
+
+	return v, err == nil
+}
+
+func (c *schemaColumn) compare(a, b schemaValue) int {
+	switch c.Type {
+	case ColumnString:
+		return strings.Compare(a.s, b.s)
+	case ColumnUint64:
+		return cmp.Compare(a.u, b.u)
+	case ColumnDecimal, ColumnFloat64:
+		return cmp.Compare(a.f, b.f)
+	case ColumnTime:
+		return a.t.Compare(b.t)
+	}
+
+	return cmp.Compare(a.i, b.i)
+}
+
+// validate returns nil when s satisfies the column.
+func (c *schemaColumn) validate(s string) error {
+	if c.isMissing(s) {
+		if c.Required {
+			return ErrSchemaRequired
+		}
+		return nil
+	}
+
+	if c.MinLength > 0 || c.MaxLength > 0 {
+		n := utf8.RuneCountInString(s)
+		if n < c.MinLength {
+			return fmt.Errorf("%w: %d runes is less than min length %d", ErrSchemaLength, n, c.MinLength)
+		}
+		if c.MaxLength > 0 && n > c.MaxLength {
+			return fmt.Errorf("%w: %d runes is greater than max length %d", ErrSchemaLength, n, c.MaxLength)
+		}
+	}
+
+	v, ok := c.parse(s)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSchemaType, c.Type)
+	}
+
+	if c.hasMin && c.compare(v, c.min) < 0 {
+		return fmt.Errorf("%w: less than minimum %s", ErrSchemaRange, c.Minimum)
+	}
+	if c.hasMax && c.compare(v, c.max) > 0 {
+		return fmt.Errorf("%w: greater than maximum %s", ErrSchemaRange, c.Maximum)
+	}
+
+	if c.enum != nil {
+		if _, ok := c.enum[s]; !ok {
+			return ErrSchemaEnum
+		}
+	}
+
+	if c.Pattern != nil && !c.Pattern.MatchString(s) {
+		return fmt.Errorf("%w: %s", ErrSchemaPattern, c.Pattern.String())
+	}
+
+	return nil
+}
+
+// compileSchema validates s and prepares each of its columns for
+// validation. The primary key is returned as column indexes.
+func compileSchema(s Schema) ([]schemaColumn, []int, error) {
+	columns := make([]schemaColumn, len(s.Columns))
+	for i, cs := range s.Columns {
+		c := &columns[i]
+		c.ColumnSchema = cs
+
+		errPrefix := "schema column " + strconv.Itoa(i) + ": "
+
+		if cs.Type > ColumnDuration {
+			return nil, nil, errors.New(errPrefix + "invalid column type")
+		}
+
+		if cs.Type == ColumnTime && cs.Layout == "" {
+			c.Layout = time.RFC3339Nano
+		}
+
+		if cs.MinLength < 0 || cs.MaxLength < 0 {
+			return nil, nil, errors.New(errPrefix + "min length and max length cannot be less than zero")
+		}
+		if cs.MaxLength > 0 && cs.MinLength > cs.MaxLength {
+			return nil, nil, errors.New(errPrefix + "min length cannot exceed max length")
+		}
+
+		if cs.Type == ColumnBool && (cs.Minimum != "" || cs.Maximum != "") {
+			return nil, nil, errors.New(errPrefix + "bool columns cannot have a minimum or maximum")
+		}
+		if cs.Minimum != "" {
+			if c.min, c.hasMin = c.parse(cs.Minimum); !c.hasMin {
+				return nil, nil, errors.New(errPrefix + "minimum is not a valid " + cs.Type.String())
+			}
+		}
+		if cs.Maximum != "" {
+			if c.max, c.hasMax = c.parse(cs.Maximum); !c.hasMax {
+				return nil, nil, errors.New(errPrefix + "maximum is not a valid " + cs.Type.String())
+			}
+		}
+		if c.hasMin && c.hasMax && c.compare(c.min, c.max) > 0 {
+			return nil, nil, errors.New(errPrefix + "minimum cannot exceed maximum")
+		}
+
+		if len(cs.Enum) > 0 {
+			c.enum = make(map[string]struct{}, len(cs.Enum))
+			for _, v := range cs.Enum {
+				c.enum[v] = struct{}{}
+			}
+		}
+
+		if cs.MissingValues != nil {
+			c.missing = make(map[string]struct{}, len(cs.MissingValues))
+			for _, v := range cs.MissingValues {
+				c.missing[v] = struct{}{}
+			}
+		}
+	}
+
+	var primaryKey []int
+	for _, name := range s.PrimaryKey {
+		i := slices.IndexFunc(columns, func(c schemaColumn) bool {
+			return c.Name == name
+		})
+		if i == -1 {
+			return nil, nil, errors.New("schema primary key column " + strconv.Quote(name) + " not found")
+		}
+
+		columns[i].Required = true
+		primaryKey = append(primaryKey, i)
+	}
+
+	return columns, primaryKey, nil
+}
+
+// schemaFields fills dst with the fields Row would return for the current
+// record without copying them out of the record buffer.
+//
+// The fields are only valid until the next call to Scan.
+func (r *fastReader) schemaFields(dst []string) []string {
+	s := unsafe.String(unsafe.SliceData(r.recordBuf), len(r.recordBuf))
+
+	if r.projection != nil {
+		return r.projectRow(dst[:len(r.projection)], s)
+	}
+
+	if r.aliasFields {
+		dst = slices.Grow(dst[:0], len(r.fieldLengths))[:len(r.fieldLengths)]
+		r.inMemoryFields(dst, s)
+		return dst
+	}
+
+	dst = dst[:0]
+	var p int
+	for _, n := range r.fieldLengths {
+		dst = append(dst, s[p:p+n])
+		p += n
+	}
+
+	return dst
+}
+
+// schemaUniqueness holds the values seen so far of the unique columns and
+// the primary key of a schema.
+type schemaUniqueness struct {
+	primaryKey []int
+	keys       map[string]struct{}
+	// values is indexed by column and is nil for columns which are not
+	// unique
+	values []map[string]struct{}
+}
+
+// reset forgets every recorded value.
+func (u *schemaUniqueness) reset() {
+	if u == nil {
+		return
+	}
+
+	clear(u.keys)
+	for _, m := range u.values {
+		clear(m)
+	}
+}
+
+func newSchemaUniqueness(columns []schemaColumn, primaryKey []int) *schemaUniqueness {
+	u := &schemaUniqueness{
+		primaryKey: primaryKey,
+		values:     make([]map[string]struct{}, len(columns)),
+	}
+
+	if len(primaryKey) > 0 {
+		u.keys = map[string]struct{}{}
+	}
+
+	for i := range columns {
+		if columns[i].Unique {
+			u.values[i] = map[string]struct{}{}
+		}
+	}
+
+	return u
+}
+
+// check returns the index of the first column of row whose value was seen
+// before, or -1 after recording every value of row. A primary key which was
+// seen before is reported at its first column with isKey set.
+func (u *schemaUniqueness) check(columns []schemaColumn, row []string) (idx int, isKey bool) {
+	if len(row) < len(columns) {
+		row = append(row[:len(row):len(row)], make([]string, len(columns)-len(row))...)
+	}
+
+	for i, seen := range u.values {
+		if seen == nil || columns[i].isMissing(row[i]) {
+			continue
+		}
+		if _, ok := seen[row[i]]; ok {
+			return i, false
+		}
+	}
+
+	if u.keys != nil {
+		key := encodeKey(row, u.primaryKey)
+		if _, ok := u.keys[key]; ok {
+			return u.primaryKey[0], true
+		}
+		u.keys[key] = struct{}{}
+	}
+
+	for i, seen := range u.values {
+		if seen != nil && !columns[i].isMissing(row[i]) {
+			seen[strings.Clone(row[i])] = struct{}{}
+		}
+	}
+
+	return -1, false
+}
+
+// newSchemaScan returns a scan strategy which validates every record
+// produced by next against columns.
+//
+// When skipFirst is true the first record is the header row which is not
+// validated. lag is the number of records next has parsed beyond the one it
+// returns.
+func (r *fastReader) newSchemaScan(columns []schemaColumn, primaryKey []int, maxViolations int, skipFirst bool, lag uint64, next func() bool) func() bool {
+	var violations []error
+	var fields []string
+
+	var uniq *schemaUniqueness
+	if len(primaryKey) > 0 || slices.ContainsFunc(columns, func(c schemaColumn) bool { return c.Unique }) {
+		uniq = newSchemaUniqueness(columns, primaryKey)
+	}
+
+	initialSkipFirst := skipFirst
+	r.pr.onReset(func() {
+		violations = nil
+		skipFirst = initialSkipFirst
+		uniq.reset()
+	})
+
+	return r.pr.persistentScan(next, func(scan func() bool) bool {
+		for {
+			if !scan() {
+				if r.scanErr == nil && len(violations) > 0 {
+					r.scanErr = errors.Join(violations...)
+				}
+				return false
+			}
+
+			if skipFirst {
+				skipFirst = false
+				return true
+			}
+
+			var row []string
+			if lag > 0 {
+				// rows are served from the lookahead buffer
+				row = r.pr.row()
+			} else {
+				if cap(fields) < max(len(r.fieldLengths), len(r.projection)) {
+					fields = make([]string, 0, max(len(r.fieldLengths), len(r.projection)))
+				}
+				fields = r.schemaFields(fields)
+				row = fields
+			}
+
+			n := len(violations)
+			violations = r.validateSchema(columns, uniq, row, lag, violations, max(maxViolations, 1))
+			clear(fields)
+
+			if len(violations) == n {
+				return true
+			}
+
+			if maxViolations == 0 {
+				r.setDone()
+				r.scanErr = violations[0]
+				return false
+			}
+
+			if len(violations) >= maxViolations {
+				r.setDone()
+				r.scanErr = errors.Join(violations...)
+				return false
+			}
+		}
+	})
+}
+
+// validateSchema appends a positioned error to violations for each field of
+// row which does not satisfy its column until limit violations are held.
+//
+// Uniqueness is only checked, and the values of row only recorded, when
+// every field satisfies its column.
+func (r *fastReader) validateSchema(columns []schemaColumn, uniq *schemaUniqueness, row []string, lag uint64, violations []error, limit int) []error {
+	recordIndex := r.recordIndex
+	if r.state != rStateStartOfRecord {
+		// the final record of the document was not terminated
+		recordIndex++
+	}
+	recordIndex -= lag
+
+	violation := func(i int, err error) {
+		fieldIndex := uint(i)
+		if i < len(r.projection) {
+			fieldIndex = uint(r.projection[i])
+		}
+		fieldIndex++
+
+		violations = append(violations, posTracedErr{
+			errType:     ErrSchemaViolation,
+			err:         SchemaViolation{recordIndex, fieldIndex, columns[i].Name, err},
+			byteIndex:   r.byteIndex,
+			recordIndex: recordIndex,
+			fieldIndex:  fieldIndex,
+		})
+	}
+
+	n := len(violations)
+	for i := range columns {
+		var v string
+		if i < len(row) {
+			v = row[i]
+		}
+
+		if err := columns[i].validate(v); err != nil {
+			violation(i, err)
+			if len(violations) >= limit {
+				break
+			}
+		}
+	}
+
+	if uniq != nil && len(violations) == n {
+		if i, isKey := uniq.check(columns, row); isKey {
+			violation(i, fmt.Errorf("%w: primary key", ErrSchemaUnique))
+		} else if i != -1 {
+			violation(i, ErrSchemaUnique)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,210 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// skipLeadingLines discards up to n line feed terminated lines from the
+// start of the stream before any parsing occurs.
+//
+// Unconsumed bytes remain in the raw buffer for the state machine to
+// process and the byte index is advanced past the discarded lines.
+func (r *fastReader) skipLeadingLines(n int, onLine func([]byte)) bool {
+	var line []byte
+
+	for n > 0 {
+		if r.rawIndex == len(r.rawBuf) {
+			if (r.bitFlags & stEOF) != 0 {
+				break
+			}
+
+			r.rawIndex = 0
+			m, err := r.reader.Read(r.rawBuf[:cap(r.rawBuf)])
+			r.rawBuf = r.rawBuf[:m]
+			if err != nil {
+				r.bitFlags |= stEOF
+				if !errors.Is(err, io.EOF) {
+					if m == 0 {
+						r.setDone()
+						r.ioErr(err)
+						return false
+					}
+
+					r.readErr = err
+				}
+			}
+
+			continue
+		}
+
+		end := len(r.rawBuf)
+		i := bytes.IndexByte(r.rawBuf[r.rawIndex:], asciiLineFeed)
+		if i != -1 {
+			end = r.rawIndex + i + 1
+		}
+
+		if onLine != nil {
+			line = append(line, r.rawBuf[r.rawIndex:end]...)
+		}
+		r.byteIndex += uint64(end - r.rawIndex)
+		r.rawIndex = end
+
+		if i == -1 {
+			continue
+		}
+
+		n--
+
+		if onLine != nil {
+			onLine(trimLineTerminator(line))
+			line = line[:0]
+		}
+	}
+
+	if onLine != nil && len(line) > 0 {
+		onLine(line)
+	}
+
+	return true
+}
+
+func trimLineTerminator(p []byte) []byte {
+	p = bytes.TrimSuffix(p, []byte{asciiLineFeed})
+	return bytes.TrimSuffix(p, []byte{asciiCarriageReturn})
+}
+
+// clonedRecord returns a copy of every field in the current record
+// regardless of the number of fields expected.
+func (r *fastReader) clonedRecord() []string {
+	row := make([]string, len(r.fieldLengths))
+	strBuf := string(r.recordBuf)
+
+	var p int
+	for i, s := range r.fieldLengths {
+		row[i] = strBuf[p : p+s]
+		p += s
+	}
+
+	return row
+}
+
+// newSkipLeadingRecordsScan returns a scan strategy that discards the first n
+// records produced by next.
+//
+// Skipped records are exempt from field count validation and discovery.
+func (r *secOpReader) newSkipLeadingRecordsScan(n int, onRecord func([]string), next func() bool) func() bool {
+	remaining := n
+	r.pr.onReset(func() {
+		remaining = n
+	})
+
+	checkNumFields := r.checkNumFields
+	r.checkNumFields = func(errTrailer error) bool {
+		if remaining > 0 {
+			return true
+		}
+
+		return checkNumFields(errTrailer)
+	}
+
+	fieldNumOverflow := r.fieldNumOverflow
+	r.fieldNumOverflow = func() bool {
+		if remaining > 0 {
+			return false
+		}
+
+		return fieldNumOverflow()
+	}
+
+	return func() bool {
+		r.pr.scan = next
+
+		for ; remaining > 0; remaining-- {
+			if !next() {
+				return false
+			}
+
+			if onRecord != nil {
+				onRecord(r.clonedRecord())
+			}
+
+			r.resetRecordBuffers()
+		}
+
+		return next()
+	}
+}
+
+// newDropTrailingRecordsScan returns a scan strategy that holds back the
+// last n records produced by next so they are never emitted.
+//
+// It also replaces the row strategy of the reader such that rows are
+// returned from the lookahead buffer.
+func (r *fastReader) newDropTrailingRecordsScan(n int, onRecord func([]string), cloneRow, cloneFields bool, next func() bool) func() bool {
+	row := r.pr.row
+	buf := make([][]string, 0, n+1)
+	var cur []string
+
+	var bufferedRow func() []string
+	bufferedRow = func() []string {
+		if r.scanErr != nil {
+			return nil
+		}
+
+		return cur
+	}
+	r.pr.row = bufferedRow
+
+	initialRow := row
+	r.pr.onReset(func() {
+		row = initialRow
+		clear(buf[:cap(buf)])
+		buf = buf[:0]
+		cur = nil
+	})
+
+	return r.pr.persistentScan(next, func(scan func() bool) bool {
+		cur = nil
+
+		for len(buf) <= n {
+			if !scan() {
+				if r.scanErr == nil && onRecord != nil {
+					for _, v := range buf {
+						onRecord(v)
+					}
+				}
+				buf = buf[:0]
+
+				return false
+			}
+
+			// row strategies may replace themselves on first use
+			r.pr.row = row
+			v := r.pr.row()
+			row = r.pr.row
+			r.pr.row = bufferedRow
+
+			if cloneRow {
+				v = slices.Clone(v)
+			}
+			if cloneFields {
+				for i := range v {
+					v[i] = strings.Clone(v[i])
+				}
+			}
+
+			buf = append(buf, v)
+		}
+
+		cur = buf[0]
+		copy(buf, buf[1:])
+		buf[len(buf)-1] = nil
+		buf = buf[:len(buf)-1]
+
+		return true
+	})
+}
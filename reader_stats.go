@@ -0,0 +1,71 @@
+package csv
+
+// ReaderStats is a point in time summary of the work performed by a Reader.
+//
+// Every value is tracked unconditionally at the cost of a few increments per
+// record or buffer refill, so it is always safe to call ExtendedReader.Stats.
+type ReaderStats struct {
+	// Records is the number of records returned by Scan.
+	Records uint64
+	// Bytes is the number of bytes of the document consumed by parsing.
+	Bytes uint64
+	// CommentLines is the number of comment lines parsed.
+	CommentLines uint64
+	// CommentBytes is the number of bytes within comment lines excluding
+	// the comment runes and record separators.
+	CommentBytes uint64
+	// BufferRefills is the number of times the read buffer was refilled
+	// from the underlying reader.
+	BufferRefills uint64
+	// RecordBufferGrowths is the number of records that required the record
+	// buffer to grow.
+	RecordBufferGrowths uint64
+	// MaxRecordBytes is the largest number of bytes buffered for a single
+	// record, which is the same measure limited by the MaxRecordBytes
+	// option.
+	MaxRecordBytes int
+	// RecordSeparator is the record separator in use. It is empty until a
+	// record separator has been found when DiscoverRecordSeparator is
+	// enabled.
+	RecordSeparator string
+	// ByteOrderMarker reports whether a byte order marker was found at the
+	// start of the document. It is only ever true when RemoveByteOrderMarker
+	// or ErrorOnNoByteOrderMarker is enabled.
+	ByteOrderMarker bool
+}
+
+// trackRecordBuf updates the record buffer statistics before the record
+// buffer is reset for the next record.
+func (r *fastReader) trackRecordBuf() {
+	if n := len(r.recordBuf) + r.aliasedRecordBytes; n > r.maxRecordBytes {
+		r.maxRecordBytes = n
+	}
+
+	if c := cap(r.recordBuf); c != r.recordBufCap {
+		r.recordBufCap = c
+		r.recordBufGrowths++
+	}
+}
+
+func (r *fastReader) stats() ReaderStats {
+	r.trackRecordBuf()
+
+	var recordSep string
+	switch r.recordSepRuneLen {
+	case 1:
+		recordSep = string(r.recordSepStartRune)
+	case 2:
+		recordSep = "\r\n"
+	}
+
+	return ReaderStats{
+		Bytes:               r.byteIndex,
+		CommentLines:        r.numComments,
+		CommentBytes:        r.numCommentBytes,
+		BufferRefills:       r.numRefills,
+		RecordBufferGrowths: r.recordBufGrowths,
+		MaxRecordBytes:      r.maxRecordBytes,
+		RecordSeparator:     recordSep,
+		ByteOrderMarker:     (r.bitFlags & stBOMFound) != 0,
+	}
+}
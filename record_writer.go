@@ -510,7 +510,20 @@ func (rw *RecordWriter) Empty() *RecordWriter {
 //
 // This RecordWriter instance cannot be used for further writing
 // after this call.
+//
+// If the parent Writer was configured with a Context that is done then an
+// error classified as ErrIO which wraps ctx.Err() is returned and the record
+// is discarded.
 func (rw *RecordWriter) Write() (int, error) {
+	if ctx := rw.w.ctx; ctx != nil && rw.err == nil && rw.w.err == nil {
+		if err := ctx.Err(); err != nil {
+			err = writeIOErr{err}
+			rw.w.setErr(err)
+			rw.abort(err)
+			return 0, err
+		}
+	}
+
 	if (rw.bitFlags & wFlagClearMemoryAfterFree) == 0 {
 		return rw.write_memclearOff()
 	}
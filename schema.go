@@ -0,0 +1,346 @@
+package csv
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// ColumnType is the type of the values of a column.
+type ColumnType uint8
+
+const (
+	// ColumnString is any value.
+	ColumnString ColumnType = iota
+	// ColumnBool is a 0 or 1 value, as written by RecordWriter.Bool.
+	ColumnBool
+	// ColumnInt64 is a base-10 value parsed with strconv.ParseInt.
+	ColumnInt64
+	// ColumnUint64 is a base-10 value parsed with strconv.ParseUint.
+	ColumnUint64
+	// ColumnDecimal is a base-10 value with an optional sign and an
+	// optional fractional part but no exponent, such as -12.50.
+	ColumnDecimal
+	// ColumnFloat64 is a value parsed with strconv.ParseFloat.
+	ColumnFloat64
+	// ColumnTime is a value parsed with time.Parse using
+	// ColumnSchema.Layout.
+	ColumnTime
+	// ColumnDuration is a value parsed with time.ParseDuration.
+	ColumnDuration
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case ColumnString:
+		return "string"
+	case ColumnBool:
+		return "bool"
+	case ColumnInt64:
+		return "int64"
+	case ColumnUint64:
+		return "uint64"
+	case ColumnDecimal:
+		return "decimal"
+	case ColumnFloat64:
+		return "float64"
+	case ColumnTime:
+		return "time"
+	case ColumnDuration:
+		return "duration"
+	}
+	return "ColumnType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// ColumnSchema describes the values of a column.
+//
+// Missing values, which are empty values unless MissingValues is set, are
+// only checked against Required, every other constraint applies to the
+// values which are present.
+type ColumnSchema struct {
+	Name string
+	Type ColumnType
+	// Layout is the time layout of the values when Type is ColumnTime. It
+	// defaults to time.RFC3339Nano.
+	Layout string
+	// Required is true when values must not be missing.
+	Required bool
+	// Unique is true when no two values present may be equal. Every
+	// distinct value is held in memory while reading.
+	Unique bool
+	// MissingValues, when not nil, replaces the empty string as the list of
+	// values which represent a missing value.
+	MissingValues []string
+	// MinLength and MaxLength bound the number of runes of values. Zero
+	// means no bound.
+	MinLength int
+	MaxLength int
+	// Pattern, when not nil, must match values. Anchor it with ^ and $ to
+	// match the whole value.
+	Pattern *regexp.Regexp
+	// Enum, when not empty, lists the only values allowed.
+	Enum []string
+	// Minimum and Maximum, when not empty, are inclusive bounds parsed and
+	// compared according to Type. String columns compare bytewise and bool
+	// columns cannot be bounded.
+	Minimum string
+	Maximum string
+	// Observed summarizes the sampled values when the column was described
+	// by InferSchema. It is informational and never validated.
+	Observed ColumnObservations
+}
+
+// ColumnObservations summarizes the values of a column sampled by
+// InferSchema.
+type ColumnObservations struct {
+	// Values is the number of non-empty values sampled.
+	Values int
+	// Empty reports that at least one sampled value was empty.
+	Empty bool
+	// MinLength and MaxLength are the rune counts of the shortest and
+	// longest non-empty values.
+	MinLength int
+	MaxLength int
+	// LeadingZeros reports that a numeric looking value begins with a zero
+	// followed by another digit, such as 007, which would be lost should
+	// the value be stored as a number.
+	LeadingZeros bool
+}
+
+// Schema describes the columns of a document in column order.
+type Schema struct {
+	Columns []ColumnSchema
+	// PrimaryKey, when not empty, names the columns whose combined values
+	// must be unique. Its columns are required. Every distinct key is held
+	// in memory while reading.
+	PrimaryKey []string
+}
+
+// inferTimeLayouts are the time layouts InferSchema recognizes in order of
+// preference. RFC3339Nano is how RecordWriter.Time writes times.
+var inferTimeLayouts = [...]string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
+	time.TimeOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// inferTypes are the column types InferSchema chooses between, in order of
+// preference. ColumnString is always a candidate.
+var inferTypes = [...]ColumnType{
+	ColumnBool,
+	ColumnInt64,
+	ColumnUint64,
+	ColumnDecimal,
+	ColumnFloat64,
+	ColumnTime,
+	ColumnDuration,
+}
+
+// columnInference accumulates what the sampled values of a column have in
+// common.
+type columnInference struct {
+	// types and layouts are bit sets indexed by inferTypes and
+	// inferTimeLayouts of the candidates every value so far satisfied
+	types   uint16
+	layouts uint16
+	values  int
+	empty   bool
+	minLen  int
+	maxLen  int
+	zeros   bool
+}
+
+func newColumnInference() columnInference {
+	return columnInference{
+		types:   1<<len(inferTypes) - 1,
+		layouts: 1<<len(inferTimeLayouts) - 1,
+	}
+}
+
+func (c *columnInference) add(s string) {
+	if s == "" {
+		c.empty = true
+		return
+	}
+
+	n := utf8.RuneCountInString(s)
+	if c.values == 0 || n < c.minLen {
+		c.minLen = n
+	}
+	if n > c.maxLen {
+		c.maxLen = n
+	}
+	c.values++
+
+	if !c.zeros && hasLeadingZero(s) && isDecimal(s) {
+		c.zeros = true
+	}
+
+	for i, t := range inferTypes {
+		if c.types&(1<<i) == 0 {
+			continue
+		}
+
+		var ok bool
+		switch t {
+		case ColumnBool:
+			ok = s == "0" || s == "1"
+		case ColumnInt64:
+			_, err := strconv.ParseInt(s, 10, 64)
+			ok = err == nil
+		case ColumnUint64:
+			_, err := strconv.ParseUint(s, 10, 64)
+			ok = err == nil
+		case ColumnDecimal:
+			ok = isDecimal(s)
+		case ColumnFloat64:
+			_, err := strconv.ParseFloat(s, 64)
+			ok = err == nil
+		case ColumnTime:
+			for j, layout := range inferTimeLayouts {
+				if c.layouts&(1<<j) == 0 {
+					continue
+				}
+				if _, err := time.Parse(layout, s); err != nil {
+					c.layouts &^= 1 << j
+				}
+			}
+			ok = c.layouts != 0
+		case ColumnDuration:
+			_, err := time.ParseDuration(s)
+			ok = err == nil
+		}
+
+		if !ok {
+			c.types &^= 1 << i
+		}
+	}
+}
+
+func (c *columnInference) schema(name string) ColumnSchema {
+	cs := ColumnSchema{
+		Name: name,
+		Observed: ColumnObservations{
+			Values:       c.values,
+			Empty:        c.empty,
+			MinLength:    c.minLen,
+			MaxLength:    c.maxLen,
+			LeadingZeros: c.zeros,
+		},
+	}
+
+	if c.values == 0 {
+		return cs
+	}
+
+	for i, t := range inferTypes {
+		if c.types&(1<<i) == 0 {
+			continue
+		}
+
+		cs.Type = t
+		if t == ColumnTime {
+			for j, layout := range inferTimeLayouts {
+				if c.layouts&(1<<j) != 0 {
+					cs.Layout = layout
+					break
+				}
+			}
+		}
+		break
+	}
+
+	return cs
+}
+
+// isDecimal reports whether s is a base-10 number with an optional sign and
+// an optional fractional part but no exponent.
+func isDecimal(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	var digits, dot bool
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits = true
+		case c == '.' && !dot && digits && i+1 < len(s):
+			dot = true
+		default:
+			return false
+		}
+	}
+
+	return digits
+}
+
+// hasLeadingZero reports whether s, ignoring a sign, begins with a zero
+// followed by another digit.
+func hasLeadingZero(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	return len(s) > 1 && s[0] == '0' && s[1] >= '0' && s[1] <= '9'
+}
+
+// InferSchema guesses the type of each column from up to sampleRows records
+// of r following its header row. A sampleRows of zero samples every record.
+//
+// The first record must be the header row naming the columns. Each column
+// is given the first of bool, int64, uint64, decimal, float64, time,
+// duration, and string which every non-empty sampled value satisfies, using
+// the same encodings RecordWriter writes: 0 and 1 for bools, strconv for
+// numbers, and time.RFC3339Nano for times. Times in a few other common
+// layouts are also recognized and reported in ColumnSchema.Layout. Empty
+// values never influence the type and a column without any non-empty value
+// is a string column.
+//
+// No constraint is set since a sample cannot show what every value must
+// satisfy. What was observed, such as whether any value was empty and the
+// rune counts of the shortest and longest values, is reported in
+// ColumnSchema.Observed. Fields beyond the header row are ignored.
+//
+// The reader is not closed.
+func InferSchema(r Reader, sampleRows int) (Schema, error) {
+	if sampleRows < 0 {
+		return Schema{}, errors.Join(ErrBadConfig, errors.New("sample rows must be greater than or equal to zero"))
+	}
+
+	if !r.Scan() {
+		if err := r.Err(); err != nil {
+			return Schema{}, err
+		}
+		return Schema{}, ErrNoHeaderRow
+	}
+
+	names, _ := cloneRow(r.Row())
+	cols := make([]columnInference, len(names))
+	for i := range cols {
+		cols[i] = newColumnInference()
+	}
+
+	for n := 0; (sampleRows == 0 || n < sampleRows) && r.Scan(); n++ {
+		row := r.Row()
+		for i := range min(len(row), len(cols)) {
+			cols[i].add(row[i])
+		}
+	}
+	if err := r.Err(); err != nil {
+		return Schema{}, err
+	}
+
+	s := Schema{Columns: make([]ColumnSchema, len(cols))}
+	for i := range cols {
+		s.Columns[i] = cols[i].schema(names[i])
+	}
+
+	return s, nil
+}
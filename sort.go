@@ -0,0 +1,666 @@
+package csv
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortType selects how the values of a sort key column are compared.
+type SortType uint8
+
+const (
+	// SortString compares values lexically byte by byte.
+	SortString SortType = iota
+	// SortNumeric compares values as float64 numbers.
+	SortNumeric
+	// SortTime compares values as times parsed with SortKey.Layout.
+	SortTime
+)
+
+// defaultSortMemoryBudget is the approximate number of bytes of records
+// held in memory before a sorted run is spilled to a temporary file.
+const defaultSortMemoryBudget = 64 << 20
+
+// defaultSortMaxMergeFanIn is the number of run files merged at once when
+// SortOptions.MaxMergeFanIn is not set.
+const defaultSortMaxMergeFanIn = 64
+
+// sortRowOverhead approximates the memory used by a buffered record beyond
+// the bytes of its fields.
+const (
+	sortRowOverhead   = 64
+	sortFieldOverhead = 16
+)
+
+// SortKey describes one column records are ordered by.
+type SortKey struct {
+	// Column is the name of the column within the header row. When empty
+	// Index is used instead.
+	Column string
+	// Index is the zero based position of the column within each record.
+	Index int
+	// Type selects how values are compared. Empty values never fail to
+	// parse and are ordered before all other values, even when Descending.
+	Type SortType
+	// Layout is the time layout used when Type is SortTime. It defaults to
+	// time.RFC3339.
+	Layout string
+	// Descending reverses the order of the key.
+	Descending bool
+}
+
+// SortOptions configures Sort.
+type SortOptions struct {
+	// MemoryBudget is the approximate number of bytes of records to hold in
+	// memory at once. Should the input exceed it, sorted runs are spilled
+	// to temporary files and merged. It defaults to 64MiB.
+	MemoryBudget int64
+	// MaxMergeFanIn is the maximum number of run files which are open and
+	// merged at once. When there are more runs they are merged in several
+	// passes through intermediate run files. It defaults to 64.
+	MaxMergeFanIn int
+	// TempDir is the directory temporary run files are created in. It
+	// defaults to os.TempDir().
+	TempDir string
+	// Header specifies that the first record of src is a header row which
+	// is written to dst first and used to resolve SortKey.Column.
+	//
+	// When false SortKey.Column is resolved with the ColumnMap method of src
+	// when it is an ExtendedReader.
+	Header bool
+	// Stable keeps records with equal keys in the order they were read.
+	Stable bool
+}
+
+// sortKey is a SortKey resolved to a column index.
+type sortKey struct {
+	name       string
+	index      int
+	typ        SortType
+	layout     string
+	descending bool
+}
+
+// sortValue is the parsed value of a sort key column.
+type sortValue struct {
+	s     string
+	t     time.Time
+	f     float64
+	empty bool
+}
+
+// sortRecord is a buffered record along with its parsed sort key values.
+type sortRecord struct {
+	fields []string
+	values []sortValue
+}
+
+func (k *sortKey) parse(s string) (sortValue, error) {
+	v := sortValue{s: s, empty: s == ""}
+	if v.empty {
+		return v, nil
+	}
+
+	var err error
+	switch k.typ {
+	case SortNumeric:
+		v.f, err = strconv.ParseFloat(s, 64)
+	case SortTime:
+		v.t, err = time.Parse(k.layout, s)
+	}
+
+	return v, err
+}
+
+func (k *sortKey) compare(a, b sortValue) int {
+	// empty values are ordered first regardless of direction
+	if a.empty || b.empty {
+		return cmp.Compare(boolToInt(!a.empty), boolToInt(!b.empty))
+	}
+
+	var c int
+	switch {
+	case k.typ == SortNumeric:
+		c = cmp.Compare(a.f, b.f)
+	case k.typ == SortTime:
+		c = a.t.Compare(b.t)
+	default:
+		c = strings.Compare(a.s, b.s)
+	}
+
+	if k.descending {
+		return -c
+	}
+	return c
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sortKeys is an ordered list of resolved sort keys.
+type sortKeys []sortKey
+
+// validateSortKeys checks keys for errors which do not depend on the
+// header row.
+func validateSortKeys(keys []SortKey) error {
+	if len(keys) == 0 {
+		return errors.New("no sort keys")
+	}
+
+	for _, k := range keys {
+		if k.Type > SortTime {
+			return errors.New("invalid sort type")
+		}
+
+		if k.Column == "" && k.Index < 0 {
+			return errors.New("sort key index must be greater than or equal to zero")
+		}
+	}
+
+	return nil
+}
+
+// resolveSortKeys resolves the column name of each validated key with
+// columns, which may be nil when no header is known.
+func resolveSortKeys(keys []SortKey, columns map[string]int) (sortKeys, error) {
+	result := make(sortKeys, len(keys))
+	for i, k := range keys {
+		sk := sortKey{
+			name:       k.Column,
+			index:      k.Index,
+			typ:        k.Type,
+			layout:     k.Layout,
+			descending: k.Descending,
+		}
+
+		if k.Column != "" {
+			idx, ok := columns[k.Column]
+			if !ok {
+				return nil, fmt.Errorf("sort key column %q not found", k.Column)
+			}
+			sk.index = idx
+		} else {
+			sk.name = strconv.Itoa(k.Index)
+		}
+
+		if sk.layout == "" {
+			sk.layout = time.RFC3339
+		}
+
+		result[i] = sk
+	}
+
+	return result, nil
+}
+
+// cloneRow copies the fields of row into a single allocation, returning the
+// copy and the approximate number of bytes of memory it uses.
+func cloneRow(row []string) ([]string, int) {
+	var n int
+	for _, f := range row {
+		n += len(f)
+	}
+
+	var sb strings.Builder
+	sb.Grow(n)
+	for _, f := range row {
+		sb.WriteString(f)
+	}
+	s := sb.String()
+
+	fields := make([]string, len(row))
+	for i, f := range row {
+		fields[i], s = s[:len(f)], s[len(f):]
+	}
+
+	return fields, n + sortRowOverhead + len(row)*sortFieldOverhead
+}
+
+// record copies row and parses the sort key values of the copy.
+func (keys sortKeys) record(row []string) (sortRecord, int, error) {
+	fields, size := cloneRow(row)
+
+	rec := sortRecord{
+		fields: fields,
+		values: make([]sortValue, len(keys)),
+	}
+
+	for i := range keys {
+		k := &keys[i]
+		if k.index >= len(rec.fields) {
+			return rec, 0, fmt.Errorf("sort key column %s: index out of range", k.name)
+		}
+
+		v, err := k.parse(rec.fields[k.index])
+		if err != nil {
+			return rec, 0, fmt.Errorf("sort key column %s: %w", k.name, err)
+		}
+		rec.values[i] = v
+	}
+
+	return rec, size, nil
+}
+
+func (keys sortKeys) compare(a, b *sortRecord) int {
+	for i := range keys {
+		if c := keys[i].compare(a.values[i], b.values[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Sort reads every record from src and writes them to dst ordered by keys,
+// comparing each key in turn until one differs.
+//
+// Records are buffered in memory up to SortOptions.MemoryBudget. Larger
+// inputs are split into sorted runs which are written to temporary files
+// with a Writer, then read back with a Reader and merged at most
+// SortOptions.MaxMergeFanIn at a time. Every record must
+// have the same number of fields. Temporary files are removed before Sort
+// returns.
+//
+// Neither src nor dst is closed.
+func Sort(src Reader, dst *Writer, keys []SortKey, opts SortOptions) error {
+	if opts.MemoryBudget < 0 {
+		return errors.Join(ErrBadConfig, errors.New("memory budget must be greater than or equal to zero"))
+	}
+
+	if opts.MaxMergeFanIn < 0 || opts.MaxMergeFanIn == 1 {
+		return errors.Join(ErrBadConfig, errors.New("max merge fan-in must be greater than one"))
+	}
+
+	if err := validateSortKeys(keys); err != nil {
+		return errors.Join(ErrBadConfig, err)
+	}
+
+	if opts.MemoryBudget == 0 {
+		opts.MemoryBudget = defaultSortMemoryBudget
+	}
+
+	if opts.MaxMergeFanIn == 0 {
+		opts.MaxMergeFanIn = defaultSortMaxMergeFanIn
+	}
+
+	s := sorter{
+		opts: opts,
+	}
+	defer s.removeRuns()
+
+	return s.sort(src, dst, keys)
+}
+
+type sorter struct {
+	opts SortOptions
+	keys sortKeys
+	recs []sortRecord
+	runs []string
+	// size is the approximate number of bytes used by recs
+	size int64
+}
+
+func (s *sorter) sort(src Reader, dst *Writer, keys []SortKey) error {
+	if !src.Scan() {
+		return src.Err()
+	}
+
+	var header []string
+	var columns map[string]int
+	if er, ok := src.(ExtendedReader); ok {
+		columns = er.ColumnMap()
+	}
+	if s.opts.Header {
+		header = slices.Clone(src.Row())
+		columns = make(map[string]int, len(header))
+		for i, h := range header {
+			if _, ok := columns[h]; !ok {
+				columns[h] = i
+			}
+		}
+	}
+
+	sk, err := resolveSortKeys(keys, columns)
+	if err != nil {
+		return errors.Join(ErrBadConfig, err)
+	}
+	s.keys = sk
+
+	if header != nil {
+		if _, err := dst.WriteRow(header...); err != nil {
+			return err
+		}
+	} else if err := s.add(src.Row()); err != nil {
+		return err
+	}
+
+	for src.Scan() {
+		if err := s.add(src.Row()); err != nil {
+			return err
+		}
+	}
+	if err := src.Err(); err != nil {
+		return err
+	}
+
+	if len(s.runs) == 0 {
+		s.sortRecs()
+		for i := range s.recs {
+			if _, err := dst.WriteRow(s.recs[i].fields...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(s.recs) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+
+	return s.merge(dst)
+}
+
+// add buffers a record, first spilling the buffered records to a run file
+// should the record not fit within the memory budget.
+func (s *sorter) add(row []string) error {
+	rec, n, err := s.keys.record(row)
+	if err != nil {
+		return err
+	}
+
+	if s.size += int64(n); s.size > s.opts.MemoryBudget && len(s.recs) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+		s.size = int64(n)
+	}
+
+	s.recs = append(s.recs, rec)
+	return nil
+}
+
+func (s *sorter) sortRecs() {
+	cmpFunc := func(a, b sortRecord) int {
+		return s.keys.compare(&a, &b)
+	}
+
+	if s.opts.Stable {
+		slices.SortStableFunc(s.recs, cmpFunc)
+	} else {
+		slices.SortFunc(s.recs, cmpFunc)
+	}
+}
+
+// spill sorts the buffered records and writes them to a new run file.
+func (s *sorter) spill() error {
+	s.sortRecs()
+
+	name, err := s.writeRun(func(w *Writer) error {
+		for i := range s.recs {
+			if _, err := w.WriteRow(s.recs[i].fields...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, name)
+
+	clear(s.recs)
+	s.recs = s.recs[:0]
+
+	return nil
+}
+
+// writeRun creates a new run file and writes records to it with write. The
+// file is removed should an error be returned.
+func (s *sorter) writeRun(write func(w *Writer) error) (_ string, retErr error) {
+	f, err := os.CreateTemp(s.opts.TempDir, "csv-sort-*.csv")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+
+		if retErr != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	cw, err := newSpillWriter(bw)
+	if err != nil {
+		return "", err
+	}
+
+	if err := write(cw); err != nil {
+		return "", err
+	}
+
+	if err := cw.Close(); err != nil {
+		return "", err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// newSpillWriter returns a Writer for the temporary files records are
+// spilled to. They are read back by a Reader returned by newSpillReader so
+// both must use the same dialect.
+func newSpillWriter(w io.Writer) (*Writer, error) {
+	return NewWriter(
+		WriterOpts().Writer(w),
+		WriterOpts().Quote('"'),
+		WriterOpts().FieldSeparator(','),
+		WriterOpts().RecordSeparator("\n"),
+		WriterOpts().ErrorOnNonUTF8(false),
+	)
+}
+
+// newSpillReader returns a Reader of a temporary file written by a Writer
+// returned by newSpillWriter. Records may differ in length since the
+// spilled records need not have come from a strict Reader.
+func newSpillReader(r io.Reader) (Reader, error) {
+	return NewReader(
+		ReaderOpts().Reader(r),
+		ReaderOpts().Quote('"'),
+		ReaderOpts().FieldSeparator(','),
+		ReaderOpts().RecordSeparator("\n"),
+		ReaderOpts().FieldCountPolicy(FieldCountFlexible),
+	)
+}
+
+func (s *sorter) removeRuns() {
+	for _, name := range s.runs {
+		_ = os.Remove(name)
+	}
+}
+
+// sortRun is a run file being merged.
+type sortRun struct {
+	f   *os.File
+	r   Reader
+	rec sortRecord
+	idx int
+}
+
+func (run *sortRun) close() error {
+	return errors.Join(run.r.Close(), run.f.Close())
+}
+
+// sortRunHeap orders runs by their current record, breaking ties by the
+// order the runs were written so that merging remains stable.
+type sortRunHeap struct {
+	keys sortKeys
+	runs []*sortRun
+}
+
+func (h *sortRunHeap) Len() int {
+	return len(h.runs)
+}
+
+func (h *sortRunHeap) Less(i, j int) bool {
+	a, b := h.runs[i], h.runs[j]
+	if c := h.keys.compare(&a.rec, &b.rec); c != 0 {
+		return c < 0
+	}
+	return a.idx < b.idx
+}
+
+func (h *sortRunHeap) Swap(i, j int) {
+	h.runs[i], h.runs[j] = h.runs[j], h.runs[i]
+}
+
+func (h *sortRunHeap) Push(x any) {
+	h.runs = append(h.runs, x.(*sortRun))
+}
+
+func (h *sortRunHeap) Pop() any {
+	n := len(h.runs) - 1
+	v := h.runs[n]
+	h.runs[n] = nil
+	h.runs = h.runs[:n]
+	return v
+}
+
+// next advances the run to its next record, returning false once the run
+// is exhausted.
+func (run *sortRun) next(keys sortKeys) (bool, error) {
+	if !run.r.Scan() {
+		return false, run.r.Err()
+	}
+
+	rec, _, err := keys.record(run.r.Row())
+	if err != nil {
+		return false, err
+	}
+	run.rec = rec
+
+	return true, nil
+}
+
+// merge merges every run file into dst, first merging groups of
+// consecutive runs into intermediate run files until no more than
+// MaxMergeFanIn runs remain.
+//
+// Only consecutive runs are merged together so merging remains stable.
+func (s *sorter) merge(dst *Writer) error {
+	fanIn := s.opts.MaxMergeFanIn
+
+	for len(s.runs) > fanIn {
+		var runs []string
+		for i := 0; i < len(s.runs); i += fanIn {
+			group := s.runs[i:min(i+fanIn, len(s.runs))]
+			if len(group) == 1 {
+				runs = append(runs, group[0])
+				continue
+			}
+
+			name, err := s.writeRun(func(w *Writer) error {
+				return s.mergeRuns(group, w)
+			})
+			if err != nil {
+				// keep track of every remaining file so they are removed
+				s.runs = append(runs, s.runs[i:]...)
+				return err
+			}
+			runs = append(runs, name)
+
+			for _, name := range group {
+				_ = os.Remove(name)
+			}
+		}
+		s.runs = runs
+	}
+
+	return s.mergeRuns(s.runs, dst)
+}
+
+// mergeRuns performs a k-way merge of the named run files into dst, closing
+// each run as soon as it is exhausted.
+func (s *sorter) mergeRuns(names []string, dst *Writer) error {
+	h := sortRunHeap{
+		keys: s.keys,
+		runs: make([]*sortRun, 0, len(names)),
+	}
+	defer func() {
+		for _, run := range h.runs {
+			_ = run.close()
+		}
+	}()
+
+	for i, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+
+		r, err := newSpillReader(f)
+		if err != nil {
+			return errors.Join(err, f.Close())
+		}
+
+		run := &sortRun{f: f, r: r, idx: i}
+		h.runs = append(h.runs, run)
+
+		ok, err := run.next(s.keys)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			h.runs = h.runs[:len(h.runs)-1]
+			if err := run.close(); err != nil {
+				return err
+			}
+		}
+	}
+
+	heap.Init(&h)
+
+	for h.Len() > 0 {
+		run := h.runs[0]
+		if _, err := dst.WriteRow(run.rec.fields...); err != nil {
+			return err
+		}
+
+		ok, err := run.next(s.keys)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			heap.Fix(&h, 0)
+			continue
+		}
+
+		heap.Pop(&h)
+		if err := run.close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
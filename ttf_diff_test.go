@@ -0,0 +1,432 @@
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func docReader(t *testing.T, doc string) csv.Reader {
+	t.Helper()
+
+	cr, err := csv.NewReader(csv.ReaderOpts().Reader(strings.NewReader(doc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cr.Close()
+	})
+
+	return cr
+}
+
+// flexibleDocReader returns a Reader of doc which accepts records of any
+// length.
+func flexibleDocReader(t *testing.T, doc string) csv.Reader {
+	t.Helper()
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(strings.NewReader(doc)),
+		csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cr.Close()
+	})
+
+	return cr
+}
+
+func TestFunctionalDiff(t *testing.T) {
+	t.Parallel()
+
+	// b reorders the columns, drops "note", and adds "extra"
+	const oldDoc = "id,name,qty,note\n1,apple,3,x\n2,pear,5,y\n4,plum,1,z\n5,fig,2,w\n"
+	const newDoc = "qty,id,name,extra\n3,1,apple,a\n6,2,pear,b\n1,3,kiwi,c\n2,5,date,d\n"
+
+	expChanges := []csv.Change{
+		{
+			Type:    csv.ChangeModified,
+			Key:     []string{"2"},
+			Old:     []string{"2", "pear", "5", "y"},
+			New:     []string{"6", "2", "pear", "b"},
+			Columns: []csv.ColumnChange{{"qty", "5", "6"}},
+		},
+		{
+			Type: csv.ChangeAdded,
+			Key:  []string{"3"},
+			New:  []string{"1", "3", "kiwi", "c"},
+		},
+		{
+			Type: csv.ChangeRemoved,
+			Key:  []string{"4"},
+			Old:  []string{"4", "plum", "1", "z"},
+		},
+		{
+			Type:    csv.ChangeModified,
+			Key:     []string{"5"},
+			Old:     []string{"5", "fig", "2", "w"},
+			New:     []string{"2", "5", "date", "d"},
+			Columns: []csv.ColumnChange{{"name", "fig", "date"}},
+		},
+	}
+
+	for _, tc := range []struct {
+		when string
+		opts []csv.DiffOption
+	}{
+		{"the inputs are sorted", []csv.DiffOption{csv.DiffOpts().Sorted(true)}},
+		{"the inputs fit in memory", nil},
+		{"the inputs exceed the memory budget", []csv.DiffOption{csv.DiffOpts().MemoryBudget(1)}},
+	} {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			dir := t.TempDir()
+
+			var changes []csv.Change
+			for c, err := range csv.Diff(docReader(t, oldDoc), docReader(t, newDoc), []string{"id"}, append(tc.opts, csv.DiffOpts().TempDir(dir))...) {
+				is.Nil(err)
+				changes = append(changes, c)
+			}
+
+			slices.SortFunc(changes, func(a, b csv.Change) int {
+				return slices.Compare(a.Key, b.Key)
+			})
+			is.Equal(expChanges, changes)
+
+			entries, err := os.ReadDir(dir)
+			is.Nil(err)
+			is.Empty(entries)
+		})
+	}
+}
+
+func TestFunctionalWriteDiff(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf))
+	is.Nil(err)
+
+	err = csv.WriteDiff(cw,
+		docReader(t, "k1,k2,v,gone\na,1,x,g\na,2,y,h\nb,1,z,i\n"),
+		docReader(t, "k2,k1,v,new\n1,a,x,n\n2,a,Y,m\n1,c,w,o\n"),
+		[]string{"k1", "k2"},
+		csv.DiffOpts().Sorted(false),
+	)
+	is.Nil(err)
+	is.Nil(cw.Close())
+
+	is.Equal("change,k2,k1,v,new,gone\n"+
+		"modified,2,a,Y,m,\n"+
+		"added,1,c,w,o,\n"+
+		"removed,1,b,z,,i\n", buf.String())
+}
+
+func TestFunctionalDiffStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	for _, sorted := range []bool{false, true} {
+		t.Run(fmt.Sprintf("when Sorted=%v", sorted), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var n int
+			for _, err := range csv.Diff(docReader(t, "k\n1\n2\n"), docReader(t, "k\n3\n4\n"), []string{"k"}, csv.DiffOpts().Sorted(sorted)) {
+				is.Nil(err)
+				n++
+				break
+			}
+			is.Equal(1, n)
+		})
+	}
+}
+
+func TestFunctionalDiffErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		a, b   string
+		keys   []string
+		opts   []csv.DiffOption
+		errIs  error
+		errStr string
+	}{
+		{
+			when:   "no key columns are specified",
+			a:      "k\n",
+			b:      "k\n",
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nno key columns",
+		},
+		{
+			when:   "the memory budget is negative",
+			a:      "k\n",
+			b:      "k\n",
+			keys:   []string{"k"},
+			opts:   []csv.DiffOption{csv.DiffOpts().MemoryBudget(-1)},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nmemory budget must be greater than or equal to zero",
+		},
+		{
+			when:   "a key column is missing",
+			a:      "k\n",
+			b:      "x\n",
+			keys:   []string{"k"},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nkey column \"k\" not found in input b",
+		},
+		{
+			when:   "an input has no header",
+			a:      "",
+			b:      "k\n",
+			keys:   []string{"k"},
+			errStr: "input a has no header row",
+		},
+		{
+			when:   "the old input has duplicate keys",
+			a:      "k\n1\n1\n",
+			b:      "k\n",
+			keys:   []string{"k"},
+			errStr: "input a: duplicate key [\"1\"]",
+		},
+		{
+			when:   "the new input has duplicate keys",
+			a:      "k\n1\n",
+			b:      "k\n1\n1\n",
+			keys:   []string{"k"},
+			errStr: "input b: duplicate key [\"1\"]",
+		},
+		{
+			when:   "the new input has duplicate added keys",
+			a:      "k\n",
+			b:      "k\n2\n2\n",
+			keys:   []string{"k"},
+			errStr: "input b: duplicate key [\"2\"]",
+		},
+		{
+			when:   "a sorted input has duplicate keys",
+			a:      "k\n1\n1\n",
+			b:      "k\n",
+			keys:   []string{"k"},
+			opts:   []csv.DiffOption{csv.DiffOpts().Sorted(true)},
+			errStr: "input a: duplicate key [\"1\"]",
+		},
+		{
+			when:   "a sorted input is out of order",
+			a:      "k\n",
+			b:      "k\n2\n1\n",
+			keys:   []string{"k"},
+			opts:   []csv.DiffOption{csv.DiffOpts().Sorted(true)},
+			errStr: "input b: not sorted by key at [\"1\"]",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var errs []error
+			for _, err := range csv.Diff(docReader(t, tc.a), docReader(t, tc.b), tc.keys, tc.opts...) {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if !is.Len(errs, 1) {
+				return
+			}
+			if tc.errIs != nil {
+				is.ErrorIs(errs[0], tc.errIs)
+			}
+			is.Equal(tc.errStr, errs[0].Error())
+		})
+	}
+}
+
+func TestFunctionalDiffFieldCountMismatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		when   string
+		a, b   string
+		opts   []csv.DiffOption
+		errStr string
+	}{
+		{
+			when:   "a sorted new record is short",
+			a:      "k,v\n1,x\n",
+			b:      "v,k\nx\n",
+			opts:   []csv.DiffOption{csv.DiffOpts().Sorted(true)},
+			errStr: "input b: invalid field count in record: expected 2 fields, got 1",
+		},
+		{
+			when:   "an old record is short",
+			a:      "k,v\n1\n",
+			b:      "k,v\n1,x\n",
+			errStr: "input a: invalid field count in record: expected 2 fields, got 1",
+		},
+		{
+			when:   "a new record is long",
+			a:      "k,v\n1,x\n",
+			b:      "k,v\n1,x,y\n",
+			errStr: "input b: invalid field count in record: expected 2 fields, got 3",
+		},
+		{
+			when:   "a partitioned old record is short",
+			a:      "k,v\n1,x\n2,y\n3\n",
+			b:      "k,v\n",
+			opts:   []csv.DiffOption{csv.DiffOpts().MemoryBudget(1)},
+			errStr: "input a: invalid field count in record: expected 2 fields, got 1",
+		},
+		{
+			when:   "a partitioned new record is short",
+			a:      "k,v\n1,x\n2,y\n",
+			b:      "k,v\n1\n",
+			opts:   []csv.DiffOption{csv.DiffOpts().MemoryBudget(1)},
+			errStr: "input b: invalid field count in record: expected 2 fields, got 1",
+		},
+	} {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var errs []error
+			for _, err := range csv.Diff(flexibleDocReader(t, tc.a), flexibleDocReader(t, tc.b), []string{"k"}, append(tc.opts, csv.DiffOpts().TempDir(t.TempDir()))...) {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if !is.Len(errs, 1) {
+				return
+			}
+			is.ErrorIs(errs[0], csv.ErrInvalidFieldCountInRecord)
+			is.Equal(tc.errStr, errs[0].Error())
+		})
+	}
+}
+
+func TestFunctionalDiffSplitsLargePartitions(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var a, b strings.Builder
+	a.WriteString("k,v\n")
+	b.WriteString("k,v\n")
+	expModified := map[string]bool{}
+	for i := range 300 {
+		k := strconv.Itoa(i)
+		a.WriteString(k + ",x\n")
+		if i%10 == 0 {
+			b.WriteString(k + ",y\n")
+			expModified[k] = true
+		} else {
+			b.WriteString(k + ",x\n")
+		}
+	}
+
+	dir := t.TempDir()
+
+	modified := map[string]bool{}
+	for c, err := range csv.Diff(docReader(t, a.String()), docReader(t, b.String()), []string{"k"}, csv.DiffOpts().MemoryBudget(700), csv.DiffOpts().TempDir(dir)) {
+		is.Nil(err)
+		is.Equal(csv.ChangeModified, c.Type)
+		modified[c.Key[0]] = true
+	}
+	is.Equal(expModified, modified)
+
+	entries, err := os.ReadDir(dir)
+	is.Nil(err)
+	is.Empty(entries)
+}
+
+func TestFunctionalDiffPartitionedQuotedFields(t *testing.T) {
+	t.Parallel()
+
+	quotedReader := func(doc string) csv.Reader {
+		cr, err := csv.NewReader(
+			csv.ReaderOpts().Reader(strings.NewReader(doc)),
+			csv.ReaderOpts().Quote('"'),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			cr.Close()
+		})
+
+		return cr
+	}
+
+	// enough records share each partition that partitions are split again
+	var filler strings.Builder
+	for i := range 300 {
+		filler.WriteString("\"" + strconv.Itoa(i) + ",k\",\"x \"\"y\"\"\"\n")
+	}
+
+	oldDoc := "k,v\n\"a,b\",\"say \"\"hi\"\"\"\n\"c\nd\",1\n" + filler.String()
+	newDoc := "k,v\n\"a,b\",\"say \"\"bye\"\"\"\n\"c\nd\",\"2,3\"\n" + filler.String()
+
+	expChanges := []csv.Change{
+		{
+			Type:    csv.ChangeModified,
+			Key:     []string{"a,b"},
+			Old:     []string{"a,b", `say "hi"`},
+			New:     []string{"a,b", `say "bye"`},
+			Columns: []csv.ColumnChange{{"v", `say "hi"`, `say "bye"`}},
+		},
+		{
+			Type:    csv.ChangeModified,
+			Key:     []string{"c\nd"},
+			Old:     []string{"c\nd", "1"},
+			New:     []string{"c\nd", "2,3"},
+			Columns: []csv.ColumnChange{{"v", "1", "2,3"}},
+		},
+	}
+
+	for _, budget := range []int64{0, 800} {
+		t.Run(fmt.Sprintf("when MemoryBudget=%d", budget), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			dir := t.TempDir()
+
+			var changes []csv.Change
+			for c, err := range csv.Diff(quotedReader(oldDoc), quotedReader(newDoc), []string{"k"}, csv.DiffOpts().MemoryBudget(budget), csv.DiffOpts().TempDir(dir)) {
+				is.Nil(err)
+				changes = append(changes, c)
+			}
+
+			slices.SortFunc(changes, func(a, b csv.Change) int {
+				return slices.Compare(a.Key, b.Key)
+			})
+			is.Equal(expChanges, changes)
+
+			entries, err := os.ReadDir(dir)
+			is.Nil(err)
+			is.Empty(entries)
+		})
+	}
+}
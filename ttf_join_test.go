@@ -0,0 +1,272 @@
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalJoin(t *testing.T) {
+	t.Parallel()
+
+	// both documents are sorted by key, the right document names its key
+	// differently and shares the "name" column with the left document
+	const left = "id,name,qty\n1,apple,3\n2,pear,5\n2,pear,6\n4,plum,1\n"
+	const right = "name,sku,price\nfruit,1,0.5\nbig,2,0.7\nsmall,2,0.6\nnut,3,2.0\n"
+
+	const header = "id,left.name,qty,right.name,price\n"
+	const matched = "1,apple,3,fruit,0.5\n" +
+		"2,pear,5,big,0.7\n" +
+		"2,pear,5,small,0.6\n" +
+		"2,pear,6,big,0.7\n" +
+		"2,pear,6,small,0.6\n"
+
+	tcs := []struct {
+		kind    csv.JoinKind
+		exp     string
+		expHash string
+	}{
+		{
+			kind: csv.JoinInner,
+			exp:  header + matched,
+		},
+		{
+			kind: csv.JoinLeft,
+			exp:  header + matched + "4,plum,1,,\n",
+		},
+		{
+			kind:    csv.JoinFull,
+			exp:     header + matched + "3,,,nut,2.0\n4,plum,1,,\n",
+			expHash: header + matched + "4,plum,1,,\n3,,,nut,2.0\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		for _, sorted := range []bool{false, true} {
+			t.Run(fmt.Sprintf("when kind=%s and Sorted=%v", tc.kind, sorted), func(t *testing.T) {
+				t.Parallel()
+
+				is := assert.New(t)
+
+				exp := tc.exp
+				if !sorted && tc.expHash != "" {
+					exp = tc.expHash
+				}
+
+				var buf bytes.Buffer
+				cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf))
+				is.Nil(err)
+
+				err = csv.WriteJoin(cw, docReader(t, left), docReader(t, right), csv.JoinSpec{
+					Left:   []string{"id"},
+					Right:  []string{"sku"},
+					Sorted: sorted,
+				}, tc.kind)
+				is.Nil(err)
+				is.Nil(cw.Close())
+
+				is.Equal(exp, buf.String())
+			})
+		}
+	}
+}
+
+func TestFunctionalJoinRows(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var rows [][]string
+	for row, err := range csv.Join(
+		docReader(t, "k1,k2,v\na,1,x\nb,2,y\n"),
+		docReader(t, "k2,w,k1\n2,z,b\n"),
+		csv.JoinSpec{Left: []string{"k1", "k2"}},
+		csv.JoinInner,
+	) {
+		is.Nil(err)
+		rows = append(rows, row)
+	}
+
+	is.Equal([][]string{
+		{"k1", "k2", "v", "w"},
+		{"b", "2", "y", "z"},
+	}, rows)
+}
+
+func TestFunctionalJoinStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	for _, sorted := range []bool{false, true} {
+		t.Run(fmt.Sprintf("when Sorted=%v", sorted), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var n int
+			for _, err := range csv.Join(docReader(t, "k\n1\n2\n"), docReader(t, "k\n1\n2\n"), csv.JoinSpec{Left: []string{"k"}, Sorted: sorted}, csv.JoinInner) {
+				is.Nil(err)
+				n++
+				if n == 2 {
+					break
+				}
+			}
+			is.Equal(2, n)
+		})
+	}
+}
+
+func TestFunctionalJoinErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when        string
+		left, right string
+		spec        csv.JoinSpec
+		kind        csv.JoinKind
+		errIs       error
+		errStr      string
+	}{
+		{
+			when:   "no key columns are specified",
+			left:   "k\n",
+			right:  "k\n",
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nno key columns",
+		},
+		{
+			when:   "the key column counts differ",
+			left:   "k\n",
+			right:  "k\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}, Right: []string{"k", "j"}},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nleft and right key column counts must match",
+		},
+		{
+			when:   "the join kind is invalid",
+			left:   "k\n",
+			right:  "k\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}},
+			kind:   csv.JoinFull + 1,
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\ninvalid join kind",
+		},
+		{
+			when:   "the max right bytes is negative",
+			left:   "k\n",
+			right:  "k\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}, MaxRightBytes: -1},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nmax right bytes must be greater than or equal to zero",
+		},
+		{
+			when:   "a key column is missing",
+			left:   "k\n",
+			right:  "x\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nkey column \"k\" not found in input right",
+		},
+		{
+			when:   "an input has no header",
+			left:   "",
+			right:  "k\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}},
+			errStr: "input left has no header row",
+		},
+		{
+			when:   "the right input exceeds the max right bytes",
+			left:   "k\n1\n",
+			right:  "k\n1\n2\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}, MaxRightBytes: 1},
+			errIs:  csv.ErrJoinRightTooLarge,
+			errStr: csv.ErrJoinRightTooLarge.Error(),
+		},
+		{
+			when:   "a sorted input is out of order",
+			left:   "k\n2\n1\n",
+			right:  "k\n",
+			spec:   csv.JoinSpec{Left: []string{"k"}, Sorted: true},
+			errStr: "input left: not sorted by key at [\"1\"]",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var errs []error
+			for _, err := range csv.Join(docReader(t, tc.left), docReader(t, tc.right), tc.spec, tc.kind) {
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if !is.Len(errs, 1) {
+				return
+			}
+			if tc.errIs != nil {
+				is.ErrorIs(errs[0], tc.errIs)
+			}
+			is.Equal(tc.errStr, errs[0].Error())
+		})
+	}
+}
+
+func TestFunctionalJoinFieldCountMismatch(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		when        string
+		left, right string
+		kind        csv.JoinKind
+		errStr      string
+	}{
+		{
+			when:   "a left record is short",
+			left:   "k,a\n1\n",
+			right:  "k,b\n1,x\n",
+			errStr: "input left: invalid field count in record: expected 2 fields, got 1",
+		},
+		{
+			when:   "a left record is long",
+			left:   "k,a\n1,x,y\n",
+			right:  "k,b\n1,x\n",
+			errStr: "input left: invalid field count in record: expected 2 fields, got 3",
+		},
+		{
+			when:   "a right record is short",
+			left:   "k,a\n1,x\n",
+			right:  "k,b\n2\n",
+			kind:   csv.JoinFull,
+			errStr: "input right: invalid field count in record: expected 2 fields, got 1",
+		},
+	} {
+		for _, sorted := range []bool{false, true} {
+			t.Run(fmt.Sprintf("when %s with Sorted=%t", tc.when, sorted), func(t *testing.T) {
+				t.Parallel()
+
+				is := assert.New(t)
+
+				spec := csv.JoinSpec{Left: []string{"k"}, Sorted: sorted}
+
+				var errs []error
+				for _, err := range csv.Join(flexibleDocReader(t, tc.left), flexibleDocReader(t, tc.right), spec, tc.kind) {
+					if err != nil {
+						errs = append(errs, err)
+					}
+				}
+
+				if !is.Len(errs, 1) {
+					return
+				}
+				is.ErrorIs(errs[0], csv.ErrInvalidFieldCountInRecord)
+				is.Equal(tc.errStr, errs[0].Error())
+			})
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package csv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterHeaderMetadataPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalWriterTestCase{
+		{
+			when: "metadata is written",
+			then: "each entry should be written as a comment line ordered by key",
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().Metadata(map[string]string{"version": "2", "source": "bank"}),
+			},
+			res: "# source: bank\n# version: 2\n",
+		},
+		{
+			when: "metadata entries are written after comment lines",
+			then: "entries should keep their order and follow the comment lines",
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().CommentLines("exported data"),
+				csv.WriteHeaderOpts().MetadataEntries(
+					csv.MetadataEntry{Key: "z", Value: "1"},
+					csv.MetadataEntry{Key: "a", Value: "2"},
+				),
+				csv.WriteHeaderOpts().Headers("a", "b"),
+			},
+			res: "# exported data\n# z: 1\n# a: 2\na,b\n",
+		},
+		{
+			when: "metadata contains separators, backslashes, and newlines",
+			then: "they should be escaped",
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().MetadataEntries(
+					csv.MetadataEntry{Key: `a:b\c`, Value: "x: y\r\nz\u2028"},
+				),
+			},
+			res: `# a\:b\\c: x: y\r\nz\u2028` + "\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalWriterHeaderMetadataErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalWriterTestCase{
+		{
+			when: "metadata is written without a comment rune",
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().Metadata(map[string]string{"a": "b"}),
+			},
+			whErrIs:  []error{csv.ErrBadConfig},
+			whErrStr: csv.ErrBadConfig.Error() + "\nmetadata requires a comment rune",
+		},
+		{
+			when: "a metadata key is empty",
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().MetadataEntries(csv.MetadataEntry{Value: "b"}),
+			},
+			whErrIs:  []error{csv.ErrBadConfig},
+			whErrStr: csv.ErrBadConfig.Error() + "\nmetadata keys must not be empty",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalMetadataRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	exp := map[string]string{
+		"schema version":  "3",
+		"generated-at":    "2024-01-02T03:04:05Z",
+		"key:with:colons": "value: with colon",
+		`back\slash`:      `C:\data\`,
+		"multi-line":      "line 1\nline 2\r\nline 3\u0085\u2028\v\f",
+		"empty":           "",
+		"padded":          "  spaced  ",
+	}
+
+	var buf bytes.Buffer
+
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&buf),
+	)
+	assert.Nil(t, err)
+
+	_, err = cw.WriteHeader(
+		csv.WriteHeaderOpts().CommentRune('#'),
+		csv.WriteHeaderOpts().CommentLines("not metadata"),
+		csv.WriteHeaderOpts().Metadata(exp),
+		csv.WriteHeaderOpts().Headers("a", "b"),
+	)
+	assert.Nil(t, err)
+	_, err = cw.WriteRow("1", "2")
+	assert.Nil(t, err)
+	assert.Nil(t, cw.Close())
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(&buf),
+		csv.ReaderOpts().Comment('#'),
+		csv.ReaderOpts().RetainComments(true),
+		csv.ReaderOpts().ExpectHeaders("a", "b"),
+		csv.ReaderOpts().RemoveHeaderRow(true),
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, cr.(csv.ExtendedReader).Metadata())
+
+	var rows [][]string
+	for cr.Scan() {
+		rows = append(rows, cr.Row())
+	}
+	assert.Nil(t, cr.Err())
+	assert.Nil(t, cr.Close())
+
+	assert.Equal(t, [][]string{{"1", "2"}}, rows)
+	assert.Equal(t, exp, cr.(csv.ExtendedReader).Metadata())
+}
+
+func TestFunctionalReaderMetadataParsing(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when  string
+		input string
+		exp   map[string]string
+	}{
+		{"comments are not key value pairs", "# hello\n#\n#: no key\na\n", nil},
+		{"comments lack the writer's spacing", "#k:v\n#k2:  v2\na\n", map[string]string{"k": "v", "k2": " v2"}},
+		{"a key is repeated", "# k: 1\n# k: 2\na\n", map[string]string{"k": "2"}},
+		{"an escape sequence is invalid", "# k: \\q\n# k2: \\u12\n# ok: 1\na\n", map[string]string{"ok": "1"}},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader(tc.input)),
+				csv.ReaderOpts().Comment('#'),
+				csv.ReaderOpts().RetainComments(true),
+			)
+			assert.Nil(t, err)
+
+			for cr.Scan() {
+			}
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, tc.exp, cr.(csv.ExtendedReader).Metadata())
+			assert.Nil(t, cr.Close())
+		})
+	}
+}
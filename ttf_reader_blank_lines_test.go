@@ -0,0 +1,225 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderBlankLinePaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "skipping blank lines",
+			then: "empty lines should not be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("\na,b\n\n\n1,2\n\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "skipping blank lines with a CRLF record separator",
+			then: "empty lines should not be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\r\n\r\n1,2\r\n\r\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RecordSeparator("\r\n"),
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "skipping blank lines with one field per record",
+			then: "empty lines should not be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\n\nb\n\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().NumFields(1),
+				csv.ReaderOpts().TerminalRecordSeparatorEmitsRecord(true),
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows: [][]string{{"a"}, {"b"}},
+		},
+		{
+			when: "skipping blank lines and a line is a quoted empty field",
+			then: "the quoted empty field should be returned as a record",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\n\n\"\"\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows: [][]string{{"a"}, {""}},
+		},
+		{
+			when: "skipping blank lines without trimming and a line contains only whitespace",
+			then: "the whitespace should be returned as a field",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\n \t\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows: [][]string{{"a"}, {" \t"}},
+		},
+		{
+			when: "skipping and trimming blank lines",
+			then: "lines of only spaces and tabs should not be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n \t \n1,2\n\t\n3,4\n  ")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().TrimBlankLines(true),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}},
+		},
+		{
+			when: "skipping and trimming blank lines that span buffer reads",
+			then: "lines of only spaces and tabs should not be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n" + strings.Repeat(" ", 64) + "\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().ReaderBufferSize(csv.ReaderMinBufferSize),
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().TrimBlankLines(true),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "skipping and trimming blank lines and a field separator is a tab",
+			then: "lines with tabs should be returned as records",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\tb\n \n \t \n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldSeparator('\t'),
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().TrimBlankLines(true),
+			},
+			rows: [][]string{{"a", "b"}, {" ", " "}},
+		},
+		{
+			when: "skipping blank lines and max records is set",
+			then: "blank lines should not count towards the limit",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\n\n\nb\n\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().MaxRecords(2),
+			},
+			rows: [][]string{{"a"}, {"b"}},
+		},
+		{
+			when: "skipping blank lines and a parsing error occurs",
+			then: "the record index should include the blank lines",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n\n\n1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+			},
+			rows:       [][]string{{"a", "b"}},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrNotEnoughFields},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 4, field 1: " + csv.ErrNotEnoughFields.Error() + ": expected 2 fields but found 1",
+		},
+		{
+			when: "skipping blank lines before a header row",
+			then: "the header row should be the first non-blank line",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("\n\na,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{{"1", "2"}},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderBlankLineInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "trimming blank lines without skipping them",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().TrimBlankLines(true),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ntrim blank lines requires skip blank lines to be enabled",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.newOptsF = func() []csv.ReaderOption {
+			return []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+			}
+		}
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderBlankLinesMaxRecords(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given blank lines are skipped and more records than allowed exist", func(t *testing.T) {
+		t.Run("should return a max records error", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("a\n\nb\n\nc\n")),
+				csv.ReaderOpts().SkipBlankLines(true),
+				csv.ReaderOpts().MaxRecords(2),
+			)
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.ErrorIs(t, cr.Err(), csv.ErrSecOp)
+			assert.Equal(t, [][]string{{"a"}, {"b"}}, rows)
+			assert.Nil(t, cr.Close())
+		})
+	})
+}
@@ -0,0 +1,182 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func aliases(data []byte, s string) bool {
+	if len(s) == 0 || len(data) == 0 {
+		return false
+	}
+
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(data)))
+	p := uintptr(unsafe.Pointer(unsafe.StringData(s)))
+	return p >= start && p+uintptr(len(s)) <= start+uintptr(len(data))
+}
+
+func TestFunctionalBytesReaderParity(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when  string
+		input string
+		opts  []csv.ReaderOption
+	}{
+		{"input is simple", "a,b,c\n1,2,3\n4,5,6", nil},
+		{"input uses CRLF", "a,b\r\n1,2\r\n", []csv.ReaderOption{csv.ReaderOpts().RecordSeparator("\r\n")}},
+		{"input is discovered to use CRLF", "a,b\r\n1,2\r\n", []csv.ReaderOption{csv.ReaderOpts().DiscoverRecordSeparator(true)}},
+		{"input has quoted fields", "\"a\",b,\"\"\n\"x\"\"y\",\"1,2\",\"\"\n,,\n", []csv.ReaderOption{csv.ReaderOpts().Quote('"')}},
+		{"input has escaped fields", "\"a\\\"b\",c\n", []csv.ReaderOption{csv.ReaderOpts().Quote('"'), csv.ReaderOpts().Escape('\\')}},
+		{"input has a byte order marker", "\xEF\xBB\xBFa,b\n1,2\n", []csv.ReaderOption{csv.ReaderOpts().RemoveByteOrderMarker(true)}},
+		{"input has comments and blank lines", "#c\na;b\n\n1;2\n", []csv.ReaderOption{csv.ReaderOpts().Comment('#'), csv.ReaderOpts().FieldSeparator(';'), csv.ReaderOpts().SkipBlankLines(true)}},
+		{"input has multi-byte separators", "a§b c§d ", []csv.ReaderOption{csv.ReaderOpts().FieldSeparator('§'), csv.ReaderOpts().RecordSeparator(" ")}},
+		{"rows are borrowed", "a,b\n1,2\n3,4\n", []csv.ReaderOption{csv.ReaderOpts().BorrowRow(true)}},
+		{"rows are ragged", "a,b\n1\n2,3,4\n", []csv.ReaderOption{csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible), csv.ReaderOpts().BorrowRow(true)}},
+		{"columns are selected", "a,b,c\n1,2,3\n", []csv.ReaderOption{csv.ReaderOpts().SelectColumns(2, 0)}},
+		{"headers are removed", "a,b\n1,2\n", []csv.ReaderOption{csv.ReaderOpts().ExpectHeaders("a", "b"), csv.ReaderOpts().RemoveHeaderRow(true)}},
+		{"trailing records are dropped", "1,2\n3,4\nt,t\n", []csv.ReaderOption{csv.ReaderOpts().DropTrailingRecords(1)}},
+		{"headers are trimmed and returned", " a , b \n1,2\n", []csv.ReaderOption{csv.ReaderOpts().ExpectHeaders("a", "b"), csv.ReaderOpts().TrimHeaders(true)}},
+		{"quoted headers are unescaped", "\"a\"\"\",b\n1,2\n", []csv.ReaderOption{csv.ReaderOpts().Quote('"'), csv.ReaderOpts().ExpectHeaders("a\"", "b")}},
+		{"records are validated by a schema", "\"1\",a\"\"\n2,b\nx,c\n", []csv.ReaderOption{csv.ReaderOpts().Quote('"'), csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Name: "n", Type: csv.ColumnInt64}, {Name: "s", MaxLength: 2}}})}},
+		{"the last field is quoted at EOF", "a,\"b\"\"\"", []csv.ReaderOption{csv.ReaderOpts().Quote('"')}},
+		{"input is empty", "", nil},
+	}
+
+	read := func(t *testing.T, newReader func() (csv.Reader, error)) ([][]string, error) {
+		t.Helper()
+
+		cr, err := newReader()
+		assert.Nil(t, err)
+
+		var rows [][]string
+		for cr.Scan() {
+			rows = append(rows, append([]string(nil), cr.Row()...))
+		}
+		assert.Nil(t, cr.Close())
+		return rows, cr.Err()
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Run("should return the same rows as NewReader", func(t *testing.T) {
+				exp, expErr := read(t, func() (csv.Reader, error) {
+					return csv.NewReader(append([]csv.ReaderOption{csv.ReaderOpts().Reader(strings.NewReader(tc.input))}, tc.opts...)...)
+				})
+				act, actErr := read(t, func() (csv.Reader, error) {
+					return csv.NewBytesReader([]byte(tc.input), tc.opts...)
+				})
+
+				assert.Equal(t, exp, act)
+				assert.Equal(t, expErr, actErr)
+			})
+		})
+	}
+}
+
+func TestFunctionalBytesReaderAliasing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given unquoted and quoted fields", func(t *testing.T) {
+		t.Run("should alias the input unless unescaping was required", func(t *testing.T) {
+			data := []byte("abc,\"d,e\",\"f\"\"g\"\nh,i,j\n")
+			orig := string(data)
+
+			cr, err := csv.NewBytesReader(data, csv.ReaderOpts().Quote('"'))
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+			assert.Nil(t, cr.Close())
+
+			assert.Equal(t, [][]string{{"abc", "d,e", "f\"g"}, {"h", "i", "j"}}, rows)
+			assert.Equal(t, orig, string(data))
+
+			assert.True(t, aliases(data, rows[0][0]))
+			assert.True(t, aliases(data, rows[0][1]))
+			assert.False(t, aliases(data, rows[0][2]))
+			for _, v := range rows[1] {
+				assert.True(t, aliases(data, v))
+			}
+		})
+	})
+
+	t.Run("given borrowed rows and fields", func(t *testing.T) {
+		t.Run("should alias the input and borrow only unescaped fields", func(t *testing.T) {
+			data := []byte("a,\"b\"\"\"\nc,d\n")
+
+			cr, err := csv.NewBytesReader(data,
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().BorrowRow(true),
+				csv.ReaderOpts().BorrowFields(true),
+			)
+			assert.Nil(t, err)
+
+			assert.True(t, cr.Scan())
+			row := cr.Row()
+			assert.Equal(t, []string{"a", "b\""}, row)
+			assert.True(t, aliases(data, row[0]))
+			assert.False(t, aliases(data, row[1]))
+			first := row[0]
+
+			assert.True(t, cr.Scan())
+			assert.Equal(t, []string{"c", "d"}, cr.Row())
+			assert.Equal(t, "a", first)
+
+			assert.False(t, cr.Scan())
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, 3, cr.(csv.ExtendedReader).Stats().MaxRecordBytes)
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given the input ends within the minimum buffer size", func(t *testing.T) {
+		t.Run("should not modify the input", func(t *testing.T) {
+			data := []byte("aaaaaaaaaa,b\nc,d")
+			orig := string(data)
+
+			cr, err := csv.NewBytesReader(data)
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, [][]string{{"aaaaaaaaaa", "b"}, {"c", "d"}}, rows)
+			assert.Equal(t, orig, string(data))
+		})
+	})
+}
+
+func TestFunctionalBytesReaderInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		opts   []csv.ReaderOption
+		expStr string
+	}{
+		{"a reader is specified", []csv.ReaderOption{csv.ReaderOpts().Reader(strings.NewReader(""))}, "a reader cannot be specified when reading from a byte slice"},
+		{"a reader buffer size is specified", []csv.ReaderOption{csv.ReaderOpts().ReaderBufferSize(csv.ReaderMinBufferSize)}, "reader buffer options cannot be specified when reading from a byte slice"},
+		{"freed memory clearing is enabled", []csv.ReaderOption{csv.ReaderOpts().ClearFreedDataMemory(true)}, "freed data memory cannot be cleared when reading from a byte slice"},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Run("should return a bad config error", func(t *testing.T) {
+				cr, err := csv.NewBytesReader(nil, tc.opts...)
+				assert.Nil(t, cr)
+				assert.ErrorIs(t, err, csv.ErrBadConfig)
+				assert.Equal(t, csv.ErrBadConfig.Error()+"\n"+tc.expStr, err.Error())
+			})
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package csv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+type commentRecord struct {
+	line        string
+	recordIndex uint64
+}
+
+func TestFunctionalReaderCommentCapture(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when        string
+		then        string
+		input       string
+		opts        []csv.ReaderOption
+		expComments []string
+		expCaptured []commentRecord
+		expRows     [][]string
+		expErrIs    []error
+	}{
+		{
+			when:        "leading comment lines exist",
+			then:        "they should be reported and returned by Comments",
+			input:       "# source: bank\n#generated, \"today\"\na,b\n1,2\n",
+			expComments: []string{" source: bank", "generated, \"today\""},
+			expCaptured: []commentRecord{{" source: bank", 0}, {"generated, \"today\"", 0}},
+			expRows:     [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when:        "a leading comment line ends at EOF",
+			then:        "it should be reported",
+			input:       "#only",
+			expComments: []string{"only"},
+			expCaptured: []commentRecord{{"only", 0}},
+		},
+		{
+			when:        "a leading comment line uses a CRLF record separator",
+			then:        "the record separator should not be reported",
+			input:       "#x\r\n#y\r\na\r\n",
+			opts:        []csv.ReaderOption{csv.ReaderOpts().RecordSeparator("\r\n")},
+			expComments: []string{"x", "y"},
+			expCaptured: []commentRecord{{"x", 0}, {"y", 0}},
+			expRows:     [][]string{{"a"}},
+		},
+		{
+			when:        "comments exist after the start of records",
+			then:        "they should be reported with their record position but not returned by Comments",
+			input:       "#lead\na\n#after 1\nb\nc\n#after 3",
+			opts:        []csv.ReaderOption{csv.ReaderOpts().CommentsAllowedAfterStartOfRecords(true)},
+			expComments: []string{"lead"},
+			expCaptured: []commentRecord{{"lead", 0}, {"after 1", 1}, {"after 3", 3}},
+			expRows:     [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			when:        "comments span multiple buffer reads",
+			then:        "the full comment should be reported",
+			input:       "#" + strings.Repeat("0123456789", 10) + "\na\n",
+			opts:        []csv.ReaderOption{csv.ReaderOpts().ReaderBufferSize(csv.ReaderMinBufferSize)},
+			expComments: []string{strings.Repeat("0123456789", 10)},
+			expCaptured: []commentRecord{{strings.Repeat("0123456789", 10), 0}},
+			expRows:     [][]string{{"a"}},
+		},
+		{
+			when:        "max comments is exceeded",
+			then:        "a security error should be returned",
+			input:       "#1\n#2\na\n",
+			opts:        []csv.ReaderOption{csv.ReaderOpts().MaxComments(1)},
+			expComments: []string{"1"},
+			expCaptured: []commentRecord{{"1", 0}},
+			expErrIs:    []error{csv.ErrSecOp, csv.ErrSecOpCommentsAboveMax},
+		},
+		{
+			when:     "max comment bytes is exceeded",
+			then:     "a security error should be returned",
+			input:    "#123456\na\n",
+			opts:     []csv.ReaderOption{csv.ReaderOpts().MaxCommentBytes(4)},
+			expErrIs: []error{csv.ErrSecOp, csv.ErrSecOpCommentBytesAboveMax},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Run("then "+tc.then, func(t *testing.T) {
+				var captured []commentRecord
+
+				cr, err := csv.NewReader(append([]csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader(tc.input)),
+					csv.ReaderOpts().Comment('#'),
+					csv.ReaderOpts().RetainComments(true),
+					csv.ReaderOpts().Quote('"'),
+					csv.ReaderOpts().OnComment(func(line []byte, recordIndex uint64) {
+						captured = append(captured, commentRecord{string(bytes.Clone(line)), recordIndex})
+					}),
+				}, tc.opts...)...)
+				assert.Nil(t, err)
+
+				var rows [][]string
+				for cr.Scan() {
+					rows = append(rows, cr.Row())
+				}
+
+				if len(tc.expErrIs) > 0 {
+					for _, v := range tc.expErrIs {
+						assert.ErrorIs(t, cr.Err(), v)
+					}
+				} else {
+					assert.Nil(t, cr.Err())
+				}
+
+				assert.Equal(t, tc.expComments, cr.(csv.ExtendedReader).Comments())
+				assert.Equal(t, tc.expCaptured, captured)
+				assert.Equal(t, tc.expRows, rows)
+				assert.Nil(t, cr.Close())
+			})
+		})
+	}
+}
+
+func TestFunctionalReaderCommentsWithoutCallback(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given comments and no OnComment callback", func(t *testing.T) {
+		t.Run("should return only the leading comments", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("#a\n#b\nx\n#c\ny\n")),
+				csv.ReaderOpts().Comment('#'),
+				csv.ReaderOpts().RetainComments(true),
+				csv.ReaderOpts().CommentsAllowedAfterStartOfRecords(true),
+			)
+			assert.Nil(t, err)
+
+			assert.Nil(t, cr.(csv.ExtendedReader).Comments())
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, []string{"a", "b"}, cr.(csv.ExtendedReader).Comments())
+			assert.Equal(t, [][]string{{"x"}, {"y"}}, rows)
+			assert.Nil(t, cr.Close())
+		})
+	})
+}
+
+func TestFunctionalReaderCommentsNotRetained(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given comments and RetainComments is not enabled", func(t *testing.T) {
+		t.Run("should return no comments", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("#a\n#b\nx\n")),
+				csv.ReaderOpts().Comment('#'),
+			)
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+			assert.Nil(t, cr.(csv.ExtendedReader).Comments())
+			assert.Equal(t, [][]string{{"x"}}, rows)
+			assert.Equal(t, uint64(2), cr.(csv.ExtendedReader).Stats().CommentLines)
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given retained comments and ClearFreedDataMemory is enabled", func(t *testing.T) {
+		t.Run("should release the comments on Close without changing returned strings", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("#ab\nx\n")),
+				csv.ReaderOpts().Comment('#'),
+				csv.ReaderOpts().RetainComments(true),
+				csv.ReaderOpts().ClearFreedDataMemory(true),
+			)
+			assert.Nil(t, err)
+
+			for cr.Scan() {
+			}
+			assert.Nil(t, cr.Err())
+
+			comments := cr.(csv.ExtendedReader).Comments()
+			assert.Equal(t, []string{"ab"}, comments)
+
+			assert.Nil(t, cr.Close())
+			assert.Equal(t, []string{"ab"}, comments)
+			assert.Nil(t, cr.(csv.ExtendedReader).Comments())
+		})
+	})
+}
+
+func TestFunctionalReaderCommentInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "an OnComment callback is set without a comment rune",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().OnComment(func([]byte, uint64) {}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\non comment requires a comment rune",
+		},
+		{
+			when: "comments are retained without a comment rune",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RetainComments(true),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nretain comments requires a comment rune",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.newOptsF = func() []csv.ReaderOption {
+			return []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+			}
+		}
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderCommentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given comment lines written by WriteHeader", func(t *testing.T) {
+		t.Run("should be returned by Comments with the writer's space prefix", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			cw, err := csv.NewWriter(
+				csv.WriterOpts().Writer(&buf),
+			)
+			assert.Nil(t, err)
+
+			_, err = cw.WriteHeader(
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().CommentLines("origin: test", "version: 1"),
+				csv.WriteHeaderOpts().Headers("a", "b"),
+			)
+			assert.Nil(t, err)
+			assert.Nil(t, cw.Close())
+
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(&buf),
+				csv.ReaderOpts().Comment('#'),
+				csv.ReaderOpts().RetainComments(true),
+			)
+			assert.Nil(t, err)
+
+			for cr.Scan() {
+			}
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, []string{" origin: test", " version: 1"}, cr.(csv.ExtendedReader).Comments())
+			assert.Nil(t, cr.Close())
+		})
+	})
+}
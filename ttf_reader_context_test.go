@@ -0,0 +1,172 @@
+package csv_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// cancelingReader cancels its context once the wrapped reader has been read
+// from numReads times.
+type cancelingReader struct {
+	r        io.Reader
+	cancel   context.CancelFunc
+	numReads int
+}
+
+func (cr *cancelingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p[:min(len(p), 8)])
+	cr.numReads--
+	if cr.numReads == 0 {
+		cr.cancel()
+	}
+	return n, err
+}
+
+func TestFunctionalReaderContextPaths(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var cancelAfterFirstRow context.CancelFunc
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "context is nil",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("a\n")),
+				csv.ReaderOpts().Context(nil),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nnil context",
+		},
+		{
+			when: "context can never be done",
+			then: "all rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\nb\n")),
+					csv.ReaderOpts().Context(context.Background()),
+				}
+			},
+			rows: [][]string{{"a"}, {"b"}},
+		},
+		{
+			when: "context is not done",
+			then: "all rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\nb\n")),
+					csv.ReaderOpts().Context(ctx),
+				}
+			},
+			rows: [][]string{{"a"}, {"b"}},
+		},
+		{
+			when: "context is done before the first scan",
+			then: "an io error wrapping the context error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\nb\n")),
+					csv.ReaderOpts().Context(canceledCtx),
+				}
+			},
+			iterErrIs:  []error{csv.ErrIO, context.Canceled},
+			iterErrStr: csv.ErrIO.Error() + " at byte 0, record 0, field 0: " + context.Canceled.Error(),
+		},
+		{
+			when: "context is done between records",
+			then: "an io error wrapping the context error should be returned after the first row",
+			newOptsF: func() []csv.ReaderOption {
+				var ctx context.Context
+				ctx, cancelAfterFirstRow = context.WithCancel(context.Background())
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\nb\n")),
+					csv.ReaderOpts().Context(ctx),
+				}
+			},
+			forEachRow: func(_ *testing.T, _ []string) {
+				cancelAfterFirstRow()
+			},
+			rows:       [][]string{{"a"}},
+			iterErrIs:  []error{csv.ErrIO, context.Canceled},
+			iterErrStr: csv.ErrIO.Error() + " at byte 2, record 2, field 1: " + context.Canceled.Error(),
+		},
+		{
+			when: "context is done while refilling the read buffer within a record",
+			then: "an io error wrapping the context error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				ctx, cancel := context.WithCancel(context.Background())
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(&cancelingReader{
+						r:        strings.NewReader("a," + strings.Repeat("b", 64) + "\n"),
+						cancel:   cancel,
+						numReads: 1,
+					}),
+					csv.ReaderOpts().Context(ctx),
+				}
+			},
+			iterErrIs:  []error{csv.ErrIO, context.Canceled},
+			iterErrStr: csv.ErrIO.Error() + " at byte 8, record 1, field 2: " + context.Canceled.Error(),
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderContextDoneAfterEOF(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cr, err := csv.NewBytesReader([]byte("a\nb\n"), csv.ReaderOpts().Context(ctx))
+	is.Nil(err)
+
+	var rows [][]string
+	for cr.Scan() {
+		rows = append(rows, cr.Row())
+	}
+	is.Equal([][]string{{"a"}, {"b"}}, rows)
+	is.Nil(cr.Err())
+
+	cancel()
+
+	is.False(cr.Scan())
+	is.Nil(cr.Err())
+	is.Nil(cr.Close())
+}
+
+func TestFunctionalBytesReaderContextDoneBetweenRecords(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cr, err := csv.NewBytesReader([]byte("a\nb\n"), csv.ReaderOpts().Context(ctx))
+	is.Nil(err)
+
+	is.True(cr.Scan())
+	is.Equal([]string{"a"}, cr.Row())
+
+	cancel()
+
+	is.False(cr.Scan())
+	is.ErrorIs(cr.Err(), csv.ErrIO)
+	is.ErrorIs(cr.Err(), context.Canceled)
+	is.Nil(cr.Close())
+}
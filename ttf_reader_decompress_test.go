@@ -0,0 +1,235 @@
+package csv_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// bzip2Doc is "\xEF\xBB\xBFa,b\n1,2\n" compressed with bzip2 since the
+// standard library only implements a bzip2 decompressor.
+const bzip2Doc = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\xc5\xbb\x90\xb9\x00\x00\x05\x59\x09\x00\x10\x00\x04\x30\x00\x30\x00\x00\x08\x80\x00\xa0\x00\x31\x06\x4c\x41\x01\xe9\x1a\x20\x42\xea\x6c\xf1\x77\x24\x53\x85\x09\x0c\x5b\xb9\x0b\x90"
+
+func gzipDoc(members ...string) []byte {
+	var buf bytes.Buffer
+	for _, s := range members {
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write([]byte(s)); err != nil {
+			panic(err)
+		}
+		if err := w.Close(); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func zlibDoc(s string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write([]byte(s)); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFunctionalReaderDecompressPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "compression is invalid",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Decompress(csv.CompressionZlib + 1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ninvalid compression",
+		},
+		{
+			when: "limits are set without decompression",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().MaxDecompressedBytes(1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ndecompression limits require Decompress to be enabled",
+		},
+		{
+			when: "max decompressed bytes is zero",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				csv.ReaderOpts().MaxDecompressedBytes(0),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nmax decompressed bytes cannot be less than or equal to zero",
+		},
+		{
+			when: "max decompression ratio is less than one",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				csv.ReaderOpts().MaxDecompressionRatio(0.5),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nmax decompression ratio cannot be less than one",
+		},
+		{
+			when: "gzip input is read with auto detection",
+			then: "every member should be decompressed",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(bytes.NewReader(gzipDoc("a,b\n", "1,2\n"))),
+					csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				}
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "bzip2 input with a byte order marker is read with auto detection",
+			then: "the byte order marker should be found after decompression",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader(bzip2Doc)),
+					csv.ReaderOpts().Decompress(csv.CompressionAuto),
+					csv.ReaderOpts().ErrorOnNoByteOrderMarker(true),
+					csv.ReaderOpts().RemoveByteOrderMarker(true),
+				}
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "zlib input is read with auto detection",
+			then: "the input should be decompressed",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(bytes.NewReader(zlibDoc("a,b\n1,2\n"))),
+					csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				}
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}},
+		},
+		{
+			when: "uncompressed input starting with x is read with auto detection",
+			then: "the input should be read as is",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,y\n1,2\n")),
+					csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				}
+			},
+			rows: [][]string{{"x", "y"}, {"1", "2"}},
+		},
+		{
+			when: "empty input is read with auto detection",
+			then: "no rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("")),
+					csv.ReaderOpts().Decompress(csv.CompressionAuto),
+				}
+			},
+		},
+		{
+			when: "uncompressed input is read as gzip",
+			then: "an io error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c,d,e,f\n")),
+					csv.ReaderOpts().Decompress(csv.CompressionGzip),
+				}
+			},
+			iterErrIs:  []error{csv.ErrIO, gzip.ErrHeader},
+			iterErrStr: "io error at byte 0, record 0, field 0: gzip: invalid header",
+		},
+		{
+			when: "decompressed bytes exceed the max",
+			then: "a security error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(bytes.NewReader(gzipDoc("a,b\n1,2\n3,4\n"))),
+					csv.ReaderOpts().Decompress(csv.CompressionGzip),
+					csv.ReaderOpts().MaxDecompressedBytes(10),
+				}
+			},
+			rows:       [][]string{{"a", "b"}, {"1", "2"}},
+			iterErrIs:  []error{csv.ErrSecOp, csv.ErrSecOpDecompressedBytesAboveMax},
+			iterErrStr: "security error at byte 10, record 3, field 2: decompressed byte count exceeds max",
+		},
+		{
+			when: "decompressing a byte slice",
+			then: "a bad config error should be returned",
+			selfInit: func(tc *functionalReaderTestCase) {
+				_, err := csv.NewBytesReader(nil, csv.ReaderOpts().Decompress(csv.CompressionGzip))
+				if err == nil || err.Error() != csv.ErrBadConfig.Error()+"\ndecompression cannot be used when reading from a byte slice" {
+					panic(err)
+				}
+				tc.newOpts = []csv.ReaderOption{csv.ReaderOpts().Reader(strings.NewReader(""))}
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderDecompressionRatio(t *testing.T) {
+	t.Parallel()
+
+	const numRows = 1 << 20
+	bomb := gzipDoc(strings.Repeat("0\n", numRows))
+
+	read := func(maxRatio float64) (int, error) {
+		cr, err := csv.NewReader(
+			csv.ReaderOpts().Reader(bytes.NewReader(bomb)),
+			csv.ReaderOpts().Decompress(csv.CompressionAuto),
+			csv.ReaderOpts().MaxDecompressionRatio(maxRatio),
+			csv.ReaderOpts().BorrowRow(true),
+		)
+		if err != nil {
+			return 0, err
+		}
+		defer cr.Close()
+
+		var n int
+		for cr.Scan() {
+			n++
+		}
+		return n, cr.Err()
+	}
+
+	t.Run("when the ratio exceeds the max", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		n, err := read(100)
+		is.ErrorIs(err, csv.ErrSecOp)
+		is.ErrorIs(err, csv.ErrSecOpDecompressionRatioAboveMax)
+		is.Less(n, numRows)
+	})
+
+	t.Run("when the ratio is within the max", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		n, err := read(10000)
+		is.Nil(err)
+		is.Equal(numRows, n)
+	})
+}
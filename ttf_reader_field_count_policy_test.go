@@ -0,0 +1,234 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderFieldCountPolicyPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "field count policy is flexible",
+			then: "rows of varying length should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1\n2,3,4,5\n6,7,8")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible),
+			},
+			rows: [][]string{{"a", "b", "c"}, {"1"}, {"2", "3", "4", "5"}, {"6", "7", "8"}},
+		},
+		{
+			when: "field count policy is flexible and rows are borrowed",
+			then: "rows of varying length should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2,3,4\n5\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible),
+				csv.ReaderOpts().BorrowRow(true),
+				csv.ReaderOpts().BorrowFields(true),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2", "3", "4"}, {"5"}},
+		},
+		{
+			when: "field count policy is flexible and expected headers are shorter than the header row",
+			then: "a header mismatch error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+			},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 6, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: unexpected header "c" at index 2`,
+		},
+		{
+			when: "field count policy is pad missing",
+			then: "short rows should be padded with empty fields",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1\n2,3\n4,5,6\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountPadMissing),
+			},
+			rows: [][]string{{"a", "b", "c"}, {"1", "", ""}, {"2", "3", ""}, {"4", "5", "6"}},
+		},
+		{
+			when: "field count policy is pad missing and headers are expected",
+			then: "short rows should be padded to the header length",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1,2")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountPadMissing),
+				csv.ReaderOpts().ExpectHeaders("a", "b", "c"),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{{"1", "2", ""}},
+		},
+		{
+			when: "field count policy is pad missing and a row has too many fields",
+			then: "a too many fields error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2,3\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountPadMissing),
+			},
+			rows:      [][]string{{"a", "b"}},
+			iterErrIs: []error{csv.ErrParsing, csv.ErrFieldCount, csv.ErrTooManyFields},
+		},
+		{
+			when: "field count policy is truncate extra",
+			then: "long rows should be truncated",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2,3,4\n\"5\",\"6\",\"7\"\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountTruncateExtra),
+			},
+			rows: [][]string{{"a", "b"}, {"1", "2"}, {"5", "6"}},
+		},
+		{
+			when: "field count policy is truncate extra and max fields is set",
+			then: "a max fields error should be returned when exceeded",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2,3\n1,2,3,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountTruncateExtra),
+				csv.ReaderOpts().NumFields(2),
+				csv.ReaderOpts().MaxFields(3),
+			},
+			rows:      [][]string{{"a", "b"}, {"1", "2"}},
+			iterErrIs: []error{csv.ErrSecOp, csv.ErrTooManyFields, csv.ErrSecOpFieldCountAboveMax},
+		},
+		{
+			when: "field count policy is truncate extra and a row has too few fields",
+			then: "a not enough fields error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountTruncateExtra),
+			},
+			rows:      [][]string{{"a", "b"}},
+			iterErrIs: []error{csv.ErrParsing, csv.ErrFieldCount, csv.ErrNotEnoughFields},
+		},
+		{
+			when: "field count policy is truncate extra with column selection",
+			then: "only selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1,2,3,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountTruncateExtra),
+				csv.ReaderOpts().SelectColumns(2, 0),
+			},
+			rows: [][]string{{"c", "a"}, {"3", "1"}},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderFieldCountPolicyInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "field count policy is unknown",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountTruncateExtra + 1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ninvalid field count policy",
+		},
+		{
+			when: "field count policy is flexible and columns are selected",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().FieldCountPolicy(csv.FieldCountFlexible),
+				csv.ReaderOpts().SelectColumns(0),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ncolumn selection cannot be combined with the flexible field count policy",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.newOptsF = func() []csv.ReaderOption {
+			return []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+			}
+		}
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderAdjustedRows(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		policy csv.FieldCountPolicy
+		opts   []csv.ReaderOption
+		input  string
+		exp    uint64
+	}{
+		{"strict", csv.FieldCountStrict, nil, "a,b\n1,2\n", 0},
+		{"flexible", csv.FieldCountFlexible, nil, "a,b\n1\n2,3\n4,5,6\n", 2},
+		{"flexible with num fields", csv.FieldCountFlexible, []csv.ReaderOption{csv.ReaderOpts().NumFields(3)}, "a,b\n1\n2,3,4\n", 2},
+		{"pad missing", csv.FieldCountPadMissing, nil, "a,b\n1\n2,3\n4\n", 2},
+		{"truncate extra", csv.FieldCountTruncateExtra, nil, "a,b\n1,2,3\n2,3\n4,5,6,7\n", 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when field count policy is "+tc.when, func(t *testing.T) {
+			t.Run("should report the number of adjusted rows", func(t *testing.T) {
+				cr, err := csv.NewReader(append([]csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader(tc.input)),
+					csv.ReaderOpts().FieldCountPolicy(tc.policy),
+				}, tc.opts...)...)
+				assert.Nil(t, err)
+
+				for cr.Scan() {
+				}
+				assert.Nil(t, cr.Err())
+				assert.Equal(t, tc.exp, cr.(csv.ExtendedReader).AdjustedRows())
+				assert.Nil(t, cr.Close())
+			})
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package csv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderFlexibleHeaderPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "requiring headers that are present in a different order",
+			then: "the header row and data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("c,a,b\n3,1,2")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("a", "b", "c"),
+			},
+			rows: [][]string{strings.Split("c,a,b", ","), strings.Split("3,1,2", ",")},
+		},
+		{
+			when: "requiring a subset of headers and allowing extra headers",
+			then: "the data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,b,y,a\n1,2,3,4")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("a", "b"),
+				csv.ReaderOpts().AllowExtraHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{strings.Split("1,2,3,4", ",")},
+		},
+		{
+			when: "requiring headers with aliases and case insensitive matching",
+			then: "the data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("E-Mail,NAME\nx@y.z,x")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("name", "email"),
+				csv.ReaderOpts().HeaderAliases(map[string][]string{"email": {"e-mail", "mail"}}),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{strings.Split("x@y.z,x", ",")},
+		},
+		{
+			when: "expecting ordered headers with an alias and trimming",
+			then: "the data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader(" id , Cost \n1,2")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().ExpectHeaders("id", "price"),
+				csv.ReaderOpts().HeaderAliases(map[string][]string{"price": {"cost"}}),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().TrimHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{strings.Split("1,2", ",")},
+		},
+		{
+			when: "matching headers case insensitively by unicode case folding",
+			then: "the data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("ſtate,Name\nx,1")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("NAME", "STATE"),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{strings.Split("x,1", ",")},
+		},
+		{
+			when: "expecting ordered headers matched by unicode case folding",
+			then: "the data rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("ſtate\nx")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().ExpectHeaders("STATE"),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{{"x"}},
+		},
+		{
+			when: "requiring headers and one is missing while another is unexpected",
+			then: "a structured header mismatch error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,x\n1,2")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("a", "b"),
+			},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 4, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: missing header "b" at index 1; unexpected header "x" at index 1`,
+		},
+		{
+			when: "requiring headers and one is duplicated via an alias",
+			then: "a structured header mismatch error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,B2\n1,2,3")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("a", "b"),
+				csv.ReaderOpts().HeaderAliases(map[string][]string{"b": {"b2"}}),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+			},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 7, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: duplicate header "B2" at index 2`,
+		},
+		{
+			when: "requiring headers but the document is empty",
+			then: "a no header row error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("a"),
+			},
+			iterErrIs: []error{csv.ErrParsing, csv.ErrNoHeaderRow},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderColumnMap(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given headers are required with aliases and extra columns are allowed", func(t *testing.T) {
+		t.Run("should resolve canonical names and literal extra names to their column index", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("Zip,x,Mail,x\n1,2,3,4\n")),
+				csv.ReaderOpts().RequireHeaders("email", "zip"),
+				csv.ReaderOpts().HeaderAliases(map[string][]string{"email": {"mail"}}),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().AllowExtraHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			)
+			assert.Nil(t, err)
+			assert.Nil(t, cr.(csv.ExtendedReader).ColumnMap())
+
+			assert.True(t, cr.Scan())
+			assert.Equal(t, map[string]int{"email": 2, "zip": 0, "x": 1}, cr.(csv.ExtendedReader).ColumnMap())
+			assert.Equal(t, []string{"1", "2", "3", "4"}, cr.Row())
+
+			assert.False(t, cr.Scan())
+			assert.Nil(t, cr.Err())
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given the header row is only removed", func(t *testing.T) {
+		t.Run("should map literal header values to their column index", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2\n")),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			)
+			assert.Nil(t, err)
+
+			assert.True(t, cr.Scan())
+			assert.Equal(t, map[string]int{"a": 0, "b": 1}, cr.(csv.ExtendedReader).ColumnMap())
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given the header row is not recognized", func(t *testing.T) {
+		t.Run("should return a nil column map", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2\n")),
+			)
+			assert.Nil(t, err)
+
+			assert.True(t, cr.Scan())
+			assert.Nil(t, cr.(csv.ExtendedReader).ColumnMap())
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given expected headers do not match", func(t *testing.T) {
+		t.Run("should expose the mismatch details via errors.As", func(t *testing.T) {
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("a,c\n1,2\n")),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+			)
+			assert.Nil(t, err)
+
+			assert.False(t, cr.Scan())
+
+			var e csv.HeaderMismatchError
+			assert.True(t, errors.As(cr.Err(), &e))
+			assert.Equal(t, []csv.HeaderColumn{{Name: "b", Index: 1}}, e.Missing)
+			assert.Equal(t, []csv.HeaderColumn{{Name: "c", Index: 1}}, e.Unexpected)
+			assert.Nil(t, e.Duplicate)
+			assert.Nil(t, cr.Close())
+		})
+	})
+}
+
+func TestFunctionalReaderFlexibleHeaderInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		opts   []csv.ReaderOption
+		errStr string
+	}{
+		{
+			when:   "expecting and requiring headers",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().ExpectHeaders("a"), csv.ReaderOpts().RequireHeaders("a")},
+			errStr: "cannot specify both ExpectHeaders and RequireHeaders",
+		},
+		{
+			when:   "requiring an empty set of headers",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().RequireHeaders()},
+			errStr: "empty set of headers required",
+		},
+		{
+			when:   "allowing extra headers without requiring headers",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().ExpectHeaders("a"), csv.ReaderOpts().AllowExtraHeaders(true)},
+			errStr: "extra headers can only be allowed when specifying RequireHeaders",
+		},
+		{
+			when:   "specifying aliases without expected or required headers",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().HeaderAliases(map[string][]string{"a": {"b"}})},
+			errStr: "header aliases and case insensitive header matching require ExpectHeaders or RequireHeaders",
+		},
+		{
+			when:   "specifying an alias for an unknown header",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().RequireHeaders("a"), csv.ReaderOpts().HeaderAliases(map[string][]string{"b": {"c"}})},
+			errStr: `header alias key does not match an expected or required header: "b"`,
+		},
+		{
+			when:   "specifying an alias that collides with another required header",
+			opts:   []csv.ReaderOption{csv.ReaderOpts().RequireHeaders("a", "b"), csv.ReaderOpts().HeaderAliases(map[string][]string{"b": {"A"}}), csv.ReaderOpts().CaseInsensitiveHeaders(true)},
+			errStr: `required header names and aliases must be unique: "A"`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Run("should return a bad config error", func(t *testing.T) {
+				cr, err := csv.NewReader(append([]csv.ReaderOption{csv.ReaderOpts().Reader(strings.NewReader(""))}, tc.opts...)...)
+				assert.Nil(t, cr)
+				assert.ErrorIs(t, err, csv.ErrBadConfig)
+				assert.Equal(t, errors.Join(csv.ErrBadConfig, errors.New(tc.errStr)).Error(), err.Error())
+			})
+		})
+	}
+}
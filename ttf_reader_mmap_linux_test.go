@@ -0,0 +1,68 @@
+package csv_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalOpenMmap(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	t.Run("given a file", func(t *testing.T) {
+		t.Run("should read its records", func(t *testing.T) {
+			path := filepath.Join(dir, "data.csv")
+			assert.Nil(t, os.WriteFile(path, []byte("a,b\n1,2\n"), 0o600))
+
+			cr, err := csv.OpenMmap(path, csv.ReaderOpts().ExpectHeaders("a", "b"))
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+
+			// fields alias the mapping so they must be checked before Close
+			assert.Equal(t, [][]string{{"a", "b"}, {"1", "2"}}, rows)
+
+			assert.Nil(t, cr.Close())
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given an empty file", func(t *testing.T) {
+		t.Run("should return no rows", func(t *testing.T) {
+			path := filepath.Join(dir, "empty.csv")
+			assert.Nil(t, os.WriteFile(path, nil, 0o600))
+
+			cr, err := csv.OpenMmap(path)
+			assert.Nil(t, err)
+			assert.False(t, cr.Scan())
+			assert.Nil(t, cr.Err())
+			assert.Nil(t, cr.Close())
+		})
+	})
+
+	t.Run("given a file that does not exist", func(t *testing.T) {
+		t.Run("should return an error", func(t *testing.T) {
+			_, err := csv.OpenMmap(filepath.Join(dir, "missing.csv"))
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	})
+
+	t.Run("given an invalid option", func(t *testing.T) {
+		t.Run("should return a bad config error", func(t *testing.T) {
+			path := filepath.Join(dir, "opts.csv")
+			assert.Nil(t, os.WriteFile(path, []byte("a\n"), 0o600))
+
+			_, err := csv.OpenMmap(path, csv.ReaderOpts().NumFields(0))
+			assert.ErrorIs(t, err, csv.ErrBadConfig)
+		})
+	})
+}
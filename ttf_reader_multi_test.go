@@ -0,0 +1,201 @@
+package csv_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringSources(docs ...string) func(func(io.Reader) bool) {
+	return func(yield func(io.Reader) bool) {
+		for _, s := range docs {
+			if !yield(strings.NewReader(s)) {
+				return
+			}
+		}
+	}
+}
+
+func TestFunctionalMultiReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when headers are validated against the first source", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		mr, err := csv.NewMultiReader(stringSources("a,b\n1,2\n", "a,b\n", "a,b\n3,4\n5,6\n"))
+		is.Nil(err)
+
+		var rows [][]string
+		var numbers [][3]uint64
+		for mr.Scan() {
+			rows = append(rows, mr.Row())
+			numbers = append(numbers, [3]uint64{uint64(mr.SourceIndex()), mr.RecordNumber(), mr.SourceRecordNumber()})
+		}
+		is.Nil(mr.Err())
+		is.Nil(mr.Close())
+
+		is.Equal([][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}, {"5", "6"}}, rows)
+		is.Equal([][3]uint64{{0, 1, 1}, {0, 2, 2}, {2, 3, 1}, {2, 4, 2}}, numbers)
+
+		s := mr.Stats()
+		is.Equal(uint64(4), s.Records)
+		is.Equal(uint64(len("a,b\n1,2\n")+len("a,b\n")+len("a,b\n3,4\n5,6\n")), s.Bytes)
+	})
+
+	t.Run("when a header does not match the first source", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		mr, err := csv.NewMultiReader(stringSources("a,b\n1,2\n", "a,c\n3,4\n"),
+			csv.ReaderOpts().RemoveHeaderRow(true),
+		)
+		is.Nil(err)
+
+		var rows [][]string
+		for row := range mr.IntoIter() {
+			rows = append(rows, row)
+		}
+		is.Equal([][]string{{"1", "2"}}, rows)
+
+		err = mr.Err()
+		is.ErrorIs(err, csv.ErrParsing)
+		is.ErrorIs(err, csv.ErrUnexpectedHeaderRowContents)
+
+		var se csv.SourceError
+		is.True(errors.As(err, &se))
+		is.Equal(1, se.Index)
+		is.Equal("", se.Name)
+
+		var hme csv.HeaderMismatchError
+		is.True(errors.As(err, &hme))
+		is.Equal([]csv.HeaderColumn{{Name: "b", Index: 1}}, hme.Missing)
+		is.Equal([]csv.HeaderColumn{{Name: "c", Index: 1}}, hme.Unexpected)
+
+		is.Equal(`source 1: parsing error at byte 4, record 2, field 1: header row values do not match expectations: missing header "b" at index 1; unexpected header "c" at index 1`, err.Error())
+	})
+
+	t.Run("when headers are required by the options", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		mr, err := csv.NewMultiReader(stringSources("a,b\n1,2\n", "b,a\n4,3\n", "c,a\n"),
+			csv.ReaderOpts().RequireHeaders("a", "b"),
+		)
+		is.Nil(err)
+
+		var rows [][]string
+		for mr.Scan() {
+			row := mr.Row()
+			cm := mr.ColumnMap()
+			rows = append(rows, []string{row[cm["a"]], row[cm["b"]]})
+		}
+		is.Equal([][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}}, rows)
+
+		err = mr.Err()
+		is.ErrorIs(err, csv.ErrUnexpectedHeaderRowContents)
+		is.Equal(2, mr.SourceIndex())
+	})
+
+	t.Run("when sources are files", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		dir := t.TempDir()
+		var names []string
+		for i, doc := range []string{"h\n1\n", "h\n2\n", "h\n3\"\n"} {
+			name := filepath.Join(dir, string(rune('a'+i))+".csv")
+			is.Nil(os.WriteFile(name, []byte(doc), 0o600))
+			names = append(names, name)
+		}
+
+		var numClosed int
+		sources := func(yield func(io.Reader) bool) {
+			for _, name := range names {
+				f, err := os.Open(name)
+				if err != nil {
+					panic(err)
+				}
+
+				ok := yield(f)
+				_ = f.Close()
+				numClosed++
+				if !ok {
+					return
+				}
+			}
+		}
+
+		mr, err := csv.NewMultiReader(sources, csv.ReaderOpts().Quote('"'))
+		is.Nil(err)
+
+		var rows [][]string
+		for mr.Scan() {
+			rows = append(rows, mr.Row())
+		}
+		is.Equal([][]string{{"h"}, {"1"}, {"2"}}, rows)
+		is.Equal(2, numClosed)
+
+		err = mr.Err()
+		is.ErrorIs(err, csv.ErrQuoteInUnquotedField)
+		is.Equal("source 2 ("+names[2]+"): parsing error at byte 4, record 2, field 1: quote found in unquoted field", err.Error())
+		is.Equal(names[2], mr.SourceName())
+
+		is.Nil(mr.Close())
+		is.Equal(3, numClosed)
+	})
+
+	t.Run("when reset to a single source", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		mr, err := csv.NewMultiReader(stringSources("a\n1\n", "a\n2\n"))
+		is.Nil(err)
+
+		is.True(mr.Scan())
+		is.Nil(mr.Reset(strings.NewReader("a\n3\n")))
+
+		var rows [][]string
+		for row := range mr.IntoIter() {
+			rows = append(rows, row)
+		}
+		is.Nil(mr.Err())
+		is.Equal([][]string{{"a"}, {"3"}}, rows)
+	})
+
+	t.Run("when misconfigured", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		_, err := csv.NewMultiReader(stringSources())
+		is.ErrorIs(err, csv.ErrBadConfig)
+		is.Equal(csv.ErrBadConfig.Error()+"\nno sources", err.Error())
+
+		_, err = csv.NewMultiReader(stringSources("a\n"), csv.ReaderOpts().Reader(strings.NewReader("")))
+		is.ErrorIs(err, csv.ErrBadConfig)
+		is.Equal(csv.ErrBadConfig.Error()+"\na reader cannot be specified when reading from multiple sources", err.Error())
+
+		_, err = csv.NewMultiReader(stringSources("a\n"), csv.ReaderOpts().MaxFields(0))
+		is.ErrorIs(err, csv.ErrBadConfig)
+
+		var r csv.Reader
+		r, err = csv.NewMultiReader(slices.Values([]io.Reader{strings.NewReader("a\n"), nil}))
+		is.Nil(err)
+		for r.Scan() {
+		}
+		is.ErrorIs(r.Err(), csv.ErrNilReader)
+	})
+}
@@ -288,7 +288,7 @@ func TestFunctionalReaderParsingErrorPaths(t *testing.T) {
 				csv.ReaderOpts().TrimHeaders(true),
 			},
 			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
-			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error(),
+			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: missing header "c" at index 1; unexpected header "b" at index 1`,
 		},
 		{
 			when: "not whitespace-trimming the header row values by default, they do not match because of whitespace",
@@ -302,7 +302,7 @@ func TestFunctionalReaderParsingErrorPaths(t *testing.T) {
 				csv.ReaderOpts().ExpectHeaders("a", "b"),
 			},
 			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
-			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error(),
+			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: missing header "a" at index 0; missing header "b" at index 1; unexpected header " a " at index 0; unexpected header " b " at index 1`,
 		},
 		{
 			when: "not whitespace-trimming the header row values, they do not match because of whitespace",
@@ -317,7 +317,7 @@ func TestFunctionalReaderParsingErrorPaths(t *testing.T) {
 				csv.ReaderOpts().TrimHeaders(false),
 			},
 			iterErrIs:  []error{csv.ErrParsing, csv.ErrUnexpectedHeaderRowContents},
-			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error(),
+			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: missing header "a" at index 0; missing header "b" at index 1; unexpected header " a " at index 0; unexpected header " b " at index 1`,
 		},
 		{
 			when: "erroring on no rows, there are 2 columns expecting a header row, and there is only a header row",
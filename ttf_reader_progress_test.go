@@ -0,0 +1,189 @@
+package csv_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// nonSeekingReader hides any io.Seeker implementation of the wrapped reader.
+type nonSeekingReader struct {
+	r io.Reader
+}
+
+func (nsr nonSeekingReader) Read(p []byte) (int, error) {
+	return nsr.r.Read(p)
+}
+
+func TestFunctionalReaderProgressPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "progress callback is nil",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("a\n")),
+				csv.ReaderOpts().Progress(csv.ProgressInterval{Records: 1}, nil),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nnil progress callback",
+		},
+		{
+			when: "progress interval is zero",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("a\n")),
+				csv.ReaderOpts().Progress(csv.ProgressInterval{}, func(csv.Progress) {}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nprogress interval must specify a number of bytes or records",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderProgress(t *testing.T) {
+	t.Parallel()
+
+	const doc = "h\na\nb\nc\nd\ne\n"
+
+	collect := func(t *testing.T, interval csv.ProgressInterval, opts ...csv.ReaderOption) ([]csv.Progress, [][]string) {
+		t.Helper()
+
+		is := assert.New(t)
+
+		var reports []csv.Progress
+		var rows [][]string
+
+		cr, err := csv.NewReader(append(opts,
+			csv.ReaderOpts().ExpectHeaders("h"),
+			csv.ReaderOpts().RemoveHeaderRow(true),
+			csv.ReaderOpts().Progress(interval, func(p csv.Progress) {
+				// the last record returned by Scan must be fully parsed
+				// by the time a report is made
+				is.Equal(int(p.RecordIndex), len(rows)+1-boolToInt(p.Done))
+				reports = append(reports, p)
+			}),
+		)...)
+		is.Nil(err)
+
+		for cr.Scan() {
+			rows = append(rows, cr.Row())
+		}
+		is.Nil(cr.Err())
+		is.Nil(cr.Close())
+
+		// scanning after the end must not report again
+		is.False(cr.Scan())
+
+		return reports, rows
+	}
+
+	t.Run("when reporting every two records from a reader of unknown size", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		reports, rows := collect(t, csv.ProgressInterval{Records: 2},
+			csv.ReaderOpts().Reader(nonSeekingReader{strings.NewReader(doc)}),
+		)
+		is.Equal([][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, rows)
+
+		is.Equal(3, len(reports))
+		for i, recordIndex := range []uint64{2, 4, 5} {
+			p := reports[i]
+			is.Equal(recordIndex, p.RecordIndex)
+			is.Equal(int64(-1), p.TotalBytes)
+			is.Equal(time.Duration(0), p.Remaining)
+			is.Equal(i == 2, p.Done)
+		}
+		is.Equal(uint64(len(doc)), reports[2].ByteIndex)
+	})
+
+	t.Run("when reporting every four bytes from a seekable reader", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		sr := strings.NewReader("skip" + doc)
+		_, err := sr.Seek(4, io.SeekStart)
+		is.Nil(err)
+
+		reports, rows := collect(t, csv.ProgressInterval{Bytes: 4},
+			csv.ReaderOpts().Reader(sr),
+		)
+		is.Equal([][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, rows)
+
+		is.Equal(4, len(reports))
+		for i, recordIndex := range []uint64{1, 3, 5, 5} {
+			p := reports[i]
+			is.Equal(recordIndex, p.RecordIndex)
+			is.Equal(uint64(recordIndex+1)*2, p.ByteIndex)
+			is.Equal(int64(len(doc)), p.TotalBytes)
+			is.Equal(i == 3, p.Done)
+		}
+	})
+
+	t.Run("when reading from a byte slice", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		var reports []csv.Progress
+		cr, err := csv.NewBytesReader([]byte(doc),
+			csv.ReaderOpts().Progress(csv.ProgressInterval{Records: 100}, func(p csv.Progress) {
+				reports = append(reports, p)
+			}),
+		)
+		is.Nil(err)
+
+		for cr.Scan() {
+		}
+		is.Nil(cr.Err())
+
+		is.Equal(1, len(reports))
+		is.Equal(uint64(6), reports[0].RecordIndex)
+		is.Equal(uint64(len(doc)), reports[0].ByteIndex)
+		is.Equal(int64(len(doc)), reports[0].TotalBytes)
+		is.True(reports[0].Done)
+	})
+
+	t.Run("when reading from a file", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		fp := filepath.Join(t.TempDir(), "progress.csv")
+		is.Nil(os.WriteFile(fp, []byte(doc), 0o600))
+
+		f, err := os.Open(fp)
+		is.Nil(err)
+		defer f.Close()
+
+		reports, _ := collect(t, csv.ProgressInterval{Records: 5},
+			csv.ReaderOpts().Reader(f),
+		)
+
+		is.Equal(2, len(reports))
+		for _, p := range reports {
+			is.Equal(int64(len(doc)), p.TotalBytes)
+		}
+	})
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,290 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderColumnProjectionPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "selecting columns by index out of order",
+			then: "only the selected fields should be returned in the requested order",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c,d\n1,2,3,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(3, 1),
+			},
+			rows: [][]string{{"d", "b"}, {"4", "2"}},
+		},
+		{
+			when: "selecting the same column by index more than once",
+			then: "the field should be repeated",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(0, 0),
+			},
+			rows: [][]string{{"a", "a"}, {"1", "1"}},
+		},
+		{
+			when: "selecting columns by index with a known number of fields",
+			then: "only the selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,\"x,y\",3\n4,\"\"\"\",6")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().NumFields(3),
+				csv.ReaderOpts().SelectColumns(2, 0),
+			},
+			rows: [][]string{{"3", "1"}, {"6", "4"}},
+		},
+		{
+			when: "selecting columns by index and the header row is returned",
+			then: "the header row should be projected",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader(" a , b \n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().TrimHeaders(true),
+				csv.ReaderOpts().SelectColumns(1),
+			},
+			rows: [][]string{{"b"}, {"2"}},
+		},
+		{
+			when: "selecting columns by name with required headers in any order",
+			then: "only the selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,Mail,id,y\n1,a@b.c,7,2\n3,d@e.f,8,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RequireHeaders("id", "email"),
+				csv.ReaderOpts().HeaderAliases(map[string][]string{"email": {"mail"}}),
+				csv.ReaderOpts().CaseInsensitiveHeaders(true),
+				csv.ReaderOpts().AllowExtraHeaders(true),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().SelectColumnsByName("email", "id", "y"),
+			},
+			rows: [][]string{{"a@b.c", "7", "2"}, {"d@e.f", "8", "4"}},
+		},
+		{
+			when: "selecting columns by name and borrowing rows",
+			then: "only the selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\n1,2,3\n4,5,6\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().SelectColumnsByName("c", "a"),
+				csv.ReaderOpts().BorrowRow(true),
+			},
+			rows: [][]string{{"3", "1"}, {"6", "4"}},
+		},
+		{
+			when: "selecting columns by index and borrowing rows and fields",
+			then: "only the selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2,3\n4,,6\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(1, 2),
+				csv.ReaderOpts().BorrowRow(true),
+				csv.ReaderOpts().BorrowFields(true),
+			},
+			rows: [][]string{{"2", "3"}, {"", "6"}},
+		},
+		{
+			when: "selecting columns and unselected field bytes exceed max record bytes",
+			then: "only the selected fields should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2222222222\n3,4444444444\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().NumFields(2),
+				csv.ReaderOpts().SelectColumns(0),
+				csv.ReaderOpts().MaxRecordBytes(4),
+			},
+			rows: [][]string{{"1"}, {"3"}},
+		},
+		{
+			when: "selecting columns and a record is missing fields",
+			then: "a not enough fields error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2,3\n4,5\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(0),
+			},
+			rows:       [][]string{{"1"}},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrFieldCount, csv.ErrNotEnoughFields},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 10, record 2, field 2: " + csv.ErrNotEnoughFields.Error() + ": expected 3 fields but found 2",
+		},
+		{
+			when: "selecting a column index beyond the discovered number of fields",
+			then: "a selected column not found error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2\n3,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(0, 2),
+			},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrSelectedColumnNotFound},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 4, record 2, field 1: " + csv.ErrSelectedColumnNotFound.Error() + ": index 2",
+		},
+		{
+			when: "selecting a column name that is not in the header row",
+			then: "a selected column not found error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().SelectColumnsByName("a", "c"),
+			},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrSelectedColumnNotFound},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 4, record 2, field 1: " + csv.ErrSelectedColumnNotFound.Error() + `: name "c"`,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderColumnProjectionInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "selecting columns by index and by name",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().SelectColumns(0),
+				csv.ReaderOpts().SelectColumnsByName("a"),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ncannot specify both SelectColumns and SelectColumnsByName",
+		},
+		{
+			when: "selecting an empty set of columns by index",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nempty set of columns selected",
+		},
+		{
+			when: "selecting an empty set of columns by name",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().SelectColumnsByName(),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nempty set of columns selected",
+		},
+		{
+			when: "selecting a negative column index",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(-1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nselected column indexes must be greater than or equal to zero",
+		},
+		{
+			when: "selecting a column index beyond the specified number of fields",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().NumFields(2),
+				csv.ReaderOpts().SelectColumns(2),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nselected column indexes must be less than the number of fields per record",
+		},
+		{
+			when: "selecting columns by name without a header row",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumnsByName("a"),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nselecting columns by name requires a header row",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.newOptsF = func() []csv.ReaderOption {
+			return []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+			}
+		}
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderColumnProjectionDoesNotBufferUnselectedFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given a wide record and one selected column", func(t *testing.T) {
+		t.Run("should not grow the record buffer beyond the selected field", func(t *testing.T) {
+			wide := strings.Repeat("x", 1024)
+			buf := make([]byte, 0, 8)
+
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("1,"+wide+","+wide+"\n2,"+wide+","+wide+"\n")),
+				csv.ReaderOpts().NumFields(3),
+				csv.ReaderOpts().SelectColumns(0),
+				csv.ReaderOpts().InitialRecordBuffer(buf[:cap(buf)]),
+				csv.ReaderOpts().BorrowRow(true),
+				csv.ReaderOpts().BorrowFields(true),
+			)
+			assert.Nil(t, err)
+
+			var rows []string
+			for cr.Scan() {
+				row := cr.Row()
+				assert.Equal(t, 1, len(row))
+				rows = append(rows, strings.Clone(row[0]))
+			}
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, []string{"1", "2"}, rows)
+			// the provided record buffer never had to grow so it still holds
+			// the selected field of the last record
+			assert.Equal(t, "2", string(buf[:1]))
+			assert.Nil(t, cr.Close())
+		})
+	})
+}
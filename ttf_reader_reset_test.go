@@ -0,0 +1,177 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderReset(t *testing.T) {
+	t.Parallel()
+
+	readAll := func(cr csv.Reader) [][]string {
+		var rows [][]string
+		for row := range cr.IntoIter() {
+			rows = append(rows, row)
+		}
+		return rows
+	}
+
+	t.Run("when the number of fields was discovered", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		cr, err := csv.NewReader(
+			csv.ReaderOpts().Reader(strings.NewReader("a,b\nc,d\n")),
+		)
+		is.Nil(err)
+
+		is.Equal([][]string{{"a", "b"}, {"c", "d"}}, readAll(cr))
+		is.Nil(cr.Err())
+
+		is.Nil(cr.(csv.ExtendedReader).Reset(strings.NewReader("e\nf\n")))
+		is.Equal(csv.ReaderStats{RecordSeparator: "\n"}, cr.(csv.ExtendedReader).Stats())
+
+		is.Equal([][]string{{"e"}, {"f"}}, readAll(cr))
+		is.Nil(cr.Err())
+		is.Equal(uint64(2), cr.(csv.ExtendedReader).Stats().Records)
+	})
+
+	t.Run("when headers and byte order markers are handled", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		op := csv.ReaderOpts()
+		cr, err := csv.NewReader(
+			op.Reader(strings.NewReader("\xEF\xBB\xBFb,a\n1,2\n")),
+			op.ErrorOnNoByteOrderMarker(true),
+			op.RemoveByteOrderMarker(true),
+			op.RequireHeaders("a", "b"),
+			op.RemoveHeaderRow(true),
+		)
+		is.Nil(err)
+
+		is.Equal([][]string{{"1", "2"}}, readAll(cr))
+		is.Nil(cr.Err())
+		is.Equal(map[string]int{"a": 1, "b": 0}, cr.(csv.ExtendedReader).ColumnMap())
+
+		is.Nil(cr.(csv.ExtendedReader).Reset(strings.NewReader("\xEF\xBB\xBFa,b\n3,4\n")))
+		is.Nil(cr.(csv.ExtendedReader).ColumnMap())
+
+		is.Equal([][]string{{"3", "4"}}, readAll(cr))
+		is.Nil(cr.Err())
+		is.Equal(map[string]int{"a": 0, "b": 1}, cr.(csv.ExtendedReader).ColumnMap())
+
+		is.Nil(cr.(csv.ExtendedReader).Reset(strings.NewReader("a,b\n5,6\n")))
+
+		is.Nil(readAll(cr))
+		is.ErrorIs(cr.Err(), csv.ErrNoByteOrderMarker)
+	})
+
+	t.Run("when the reader errored and was closed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, memclear := range []bool{false, true} {
+			is := assert.New(t)
+
+			op := csv.ReaderOpts()
+			cr, err := csv.NewReader(
+				op.Reader(strings.NewReader("a\"b\n")),
+				op.Quote('"'),
+				op.ClearFreedDataMemory(memclear),
+				op.BorrowRow(true),
+				op.BorrowFields(true),
+			)
+			is.Nil(err)
+
+			is.False(cr.Scan())
+			is.ErrorIs(cr.Err(), csv.ErrQuoteInUnquotedField)
+			is.Nil(cr.Close())
+
+			is.Nil(cr.(csv.ExtendedReader).Reset(strings.NewReader("\"x\"\ny\n")))
+
+			is.True(cr.Scan())
+			is.Equal([]string{"x"}, cr.Row())
+			is.True(cr.Scan())
+			is.Equal([]string{"y"}, cr.Row())
+			is.False(cr.Scan())
+			is.Nil(cr.Err())
+			is.Nil(cr.Close())
+		}
+	})
+
+	t.Run("when strategies with internal state are configured", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		const doc = "junk\nid\n1\n2\ntotal\n"
+
+		var reports []csv.Progress
+
+		op := csv.ReaderOpts()
+		cr, err := csv.NewReader(
+			op.Reader(strings.NewReader(doc)),
+			op.SkipLeadingRecords(1),
+			op.DropTrailingRecords(1),
+			op.FieldCountPolicy(csv.FieldCountFlexible),
+			op.RequireHeaders("id"),
+			op.RemoveHeaderRow(true),
+			op.Schema(csv.Schema{Columns: []csv.ColumnSchema{{Name: "id", Type: csv.ColumnInt64, Unique: true}}}),
+			op.Progress(csv.ProgressInterval{Records: 100}, func(p csv.Progress) {
+				reports = append(reports, p)
+			}),
+		)
+		is.Nil(err)
+
+		for range 2 {
+			reports = nil
+
+			is.Equal([][]string{{"1"}, {"2"}}, readAll(cr))
+			is.Nil(cr.Err())
+			is.Equal(map[string]int{"id": 0}, cr.(csv.ExtendedReader).ColumnMap())
+
+			if is.Len(reports, 1) {
+				is.True(reports[0].Done)
+				is.Equal(uint64(2), reports[0].RecordIndex)
+				is.Equal(int64(len(doc)), reports[0].TotalBytes)
+			}
+
+			is.Nil(cr.(csv.ExtendedReader).Reset(strings.NewReader(doc)))
+		}
+	})
+
+	t.Run("when resetting to a nil reader", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		cr, err := csv.NewReader(
+			csv.ReaderOpts().Reader(strings.NewReader("a\n")),
+		)
+		is.Nil(err)
+
+		err = cr.(csv.ExtendedReader).Reset(nil)
+		is.ErrorIs(err, csv.ErrBadConfig)
+		is.ErrorIs(err, csv.ErrNilReader)
+
+		is.Equal([][]string{{"a"}}, readAll(cr))
+	})
+
+	t.Run("when reading from a byte slice", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		cr, err := csv.NewBytesReader([]byte("a\n"))
+		is.Nil(err)
+
+		is.ErrorIs(cr.(csv.ExtendedReader).Reset(strings.NewReader("b\n")), csv.ErrResetNotSupported)
+		is.Equal([][]string{{"a"}}, readAll(cr))
+	})
+
+}
@@ -56,7 +56,7 @@ func TestFunctionalReaderScanErrorFieldIndex(t *testing.T) {
 				csv.ErrParsing,
 				csv.ErrUnexpectedHeaderRowContents,
 			},
-			iterErrStr: csv.ErrParsing.Error() + " at byte 7, record 1, field 4: " + csv.ErrUnexpectedHeaderRowContents.Error(),
+			iterErrStr: csv.ErrParsing.Error() + " at byte 7, record 1, field 4: " + csv.ErrUnexpectedHeaderRowContents.Error() + `: missing header "e" at index 3; unexpected header "d" at index 3`,
 		},
 	}
 
@@ -0,0 +1,400 @@
+package csv_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchema = csv.Schema{Columns: []csv.ColumnSchema{
+	{Name: "id", Type: csv.ColumnUint64, Required: true, Maximum: "100"},
+	{Name: "code", Pattern: regexp.MustCompile(`^[A-Z]{2}$`), MaxLength: 2},
+	{Name: "status", Enum: []string{"new", "done"}},
+	{Name: "when", Type: csv.ColumnTime, Layout: "2006-01-02", Minimum: "2024-01-01"},
+}}
+
+func TestFunctionalReaderSchemaPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "every field satisfies the schema",
+			then: "every record should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("id,code,status,when\n1,AB,new,2024-01-02\n2,,,\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().ExpectHeaders("id", "code", "status", "when"),
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			rows: [][]string{{"id", "code", "status", "when"}, {"1", "AB", "new", "2024-01-02"}, {"2", "", "", ""}},
+		},
+		{
+			when: "a required field is empty",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("id,code,status,when\n1,AB,new,2024-01-02\n,AB,new,2024-01-02\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			rows:       [][]string{{"1", "AB", "new", "2024-01-02"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRequired},
+			iterErrAs:  []any{csv.SchemaViolation{}},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 59, record 3, field 1: column \"id\": " + csv.ErrSchemaRequired.Error(),
+		},
+		{
+			when: "a field is not of the column type",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("-1,AB,new,2024-01-02\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 21, record 1, field 1: column \"id\": " + csv.ErrSchemaType.Error() + ": uint64",
+		},
+		{
+			when: "a field is greater than the maximum",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("101,AB,new,2024-01-02")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRange},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 21, record 1, field 1: column \"id\": " + csv.ErrSchemaRange.Error() + ": greater than maximum 100",
+		},
+		{
+			when: "a time is less than the minimum",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,AB,new,2023-12-31\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRange},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 20, record 1, field 4: column \"when\": " + csv.ErrSchemaRange.Error() + ": less than minimum 2024-01-01",
+		},
+		{
+			when: "a field is too long",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,ABC,new,2024-01-02\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaLength},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 21, record 1, field 2: column \"code\": " + csv.ErrSchemaLength.Error() + ": 3 runes is greater than max length 2",
+		},
+		{
+			when: "a field does not match the pattern",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,ab,new,2024-01-02\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaPattern},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 20, record 1, field 2: column \"code\": " + csv.ErrSchemaPattern.Error() + ": ^[A-Z]{2}$",
+		},
+		{
+			when: "a field is not an allowed value",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,AB,old,2024-01-02\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(testSchema),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaEnum},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 20, record 1, field 3: column \"status\": " + csv.ErrSchemaEnum.Error(),
+		},
+		{
+			when: "columns are selected",
+			then: "the schema should describe the selected columns and violations should report the record field",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b,c\nx,1,y\nx,z,y\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SelectColumns(1),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnInt64}}}),
+			},
+			rows:       [][]string{{"1"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 18, record 3, field 2: " + csv.ErrSchemaType.Error() + ": int64",
+		},
+		{
+			when: "trailing records are dropped",
+			then: "violations should report the position of the returned record and dropped records should not be validated",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1\nx\n2\ntotal\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().DropTrailingRecords(1),
+				csv.ReaderOpts().MaxSchemaViolations(5),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnInt64}}}),
+			},
+			rows:       [][]string{{"1"}, {"2"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 6, record 2, field 1: " + csv.ErrSchemaType.Error() + ": int64",
+		},
+		{
+			when: "violations are collected",
+			then: "invalid records should be skipped and every violation returned at the end",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,1\n1,2\ny,z\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().MaxSchemaViolations(3),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnInt64}, {Type: csv.ColumnInt64}}}),
+			},
+			rows:      [][]string{{"1", "2"}},
+			iterErrIs: []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 4, record 1, field 1: " + csv.ErrSchemaType.Error() + ": int64\n" +
+				csv.ErrSchemaViolation.Error() + " at byte 12, record 3, field 1: " + csv.ErrSchemaType.Error() + ": int64\n" +
+				csv.ErrSchemaViolation.Error() + " at byte 12, record 3, field 2: " + csv.ErrSchemaType.Error() + ": int64",
+		},
+		{
+			when: "the max number of violations is reached",
+			then: "reading should stop",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x\ny\n1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().MaxSchemaViolations(2),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnBool}}}),
+			},
+			iterErrIs: []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 2, record 1, field 1: " + csv.ErrSchemaType.Error() + ": bool\n" +
+				csv.ErrSchemaViolation.Error() + " at byte 4, record 2, field 1: " + csv.ErrSchemaType.Error() + ": bool",
+		},
+		{
+			when: "a unique field repeats a value",
+			then: "a schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1\n\n2\n\n1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Name: "id", Unique: true}}}),
+			},
+			rows:       [][]string{{"1"}, {""}, {"2"}, {""}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaUnique},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 8, record 5, field 1: column \"id\": " + csv.ErrSchemaUnique.Error(),
+		},
+		{
+			when: "a primary key repeats a value",
+			then: "a schema violation should be returned at the first key column",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,a,1\nx,a,2\ny,a,1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(csv.Schema{
+					Columns:    []csv.ColumnSchema{{Name: "v"}, {Name: "k"}, {Name: "n"}},
+					PrimaryKey: []string{"k", "n"},
+				}),
+			},
+			rows:       [][]string{{"x", "a", "1"}, {"x", "a", "2"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaUnique},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 18, record 3, field 2: column \"k\": " + csv.ErrSchemaUnique.Error() + ": primary key",
+		},
+		{
+			when: "a primary key field is empty",
+			then: "a required schema violation should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Schema(csv.Schema{
+					Columns:    []csv.ColumnSchema{{Name: "k"}, {Name: "n"}},
+					PrimaryKey: []string{"k", "n"},
+				}),
+			},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaRequired},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 3, record 1, field 2: column \"n\": " + csv.ErrSchemaRequired.Error(),
+		},
+		{
+			when: "missing values are specified",
+			then: "they should be missing and the empty string should be validated",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("NA\n1\n\"\"\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnInt64, MissingValues: []string{"NA"}}}}),
+			},
+			rows:       [][]string{{"NA"}, {"1"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 8, record 3, field 1: " + csv.ErrSchemaType.Error() + ": int64",
+		},
+		{
+			when: "an invalid record repeats a unique value",
+			then: "its values should not be recorded",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,x\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().MaxSchemaViolations(2),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Unique: true}, {Type: csv.ColumnInt64}}}),
+			},
+			rows:       [][]string{{"1", "2"}},
+			iterErrIs:  []error{csv.ErrSchemaViolation, csv.ErrSchemaType},
+			iterErrStr: csv.ErrSchemaViolation.Error() + " at byte 4, record 1, field 2: " + csv.ErrSchemaType.Error() + ": int64",
+		},
+		{
+			when: "max schema violations is specified without a schema",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().MaxSchemaViolations(1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nmax schema violations requires a schema",
+		},
+		{
+			when: "max schema violations is zero",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{}),
+				csv.ReaderOpts().MaxSchemaViolations(0),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nmax schema violations cannot be less than or equal to zero",
+		},
+		{
+			when: "a schema column type is invalid",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{}, {Type: csv.ColumnDuration + 1}}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema column 1: invalid column type",
+		},
+		{
+			when: "a schema column min length exceeds its max length",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{MinLength: 2, MaxLength: 1}}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema column 0: min length cannot exceed max length",
+		},
+		{
+			when: "a schema column minimum is not of the column type",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnDuration, Minimum: "soon"}}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema column 0: minimum is not a valid duration",
+		},
+		{
+			when: "a schema column minimum exceeds its maximum",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnFloat64, Minimum: "1.5", Maximum: "1e-3"}}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema column 0: minimum cannot exceed maximum",
+		},
+		{
+			when: "a bool schema column has a minimum",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Type: csv.ColumnBool, Minimum: "0"}}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema column 0: bool columns cannot have a minimum or maximum",
+		},
+		{
+			when: "a schema primary key column does not exist",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+				csv.ReaderOpts().Schema(csv.Schema{Columns: []csv.ColumnSchema{{Name: "id"}}, PrimaryKey: []string{"key"}}),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nschema primary key column \"key\" not found",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderSchemaViolation(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(strings.NewReader("id,code,status,when\n1,AB,new,2024-01-02\n3,AB,new,2020-01-01\n")),
+		csv.ReaderOpts().ExpectHeaders("id", "code", "status", "when"),
+		csv.ReaderOpts().RemoveHeaderRow(true),
+		csv.ReaderOpts().Schema(testSchema),
+	)
+	is.Nil(err)
+
+	for cr.Scan() {
+	}
+
+	var v csv.SchemaViolation
+	if is.True(errors.As(cr.Err(), &v)) {
+		is.Equal(uint64(3), v.Record)
+		is.Equal(uint(4), v.Field)
+		is.Equal("when", v.Column)
+		is.ErrorIs(v, csv.ErrSchemaRange)
+	}
+}
@@ -0,0 +1,262 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderSkipPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "skipping leading lines before a header row",
+			then: "the preamble lines should not be parsed",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("Report \"Q1\"\r\nGenerated: today, now\na,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingLines(2),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{{"1", "2"}},
+		},
+		{
+			when: "skipping leading lines before a byte order marker",
+			then: "the byte order marker should be detected after the skipped lines",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("title\n\xEF\xBB\xBFa,b\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingLines(1),
+				csv.ReaderOpts().ErrorOnNoByteOrderMarker(true),
+				csv.ReaderOpts().RemoveByteOrderMarker(true),
+			},
+			rows: [][]string{{"a", "b"}},
+		},
+		{
+			when: "skipping more leading lines than exist",
+			then: "no rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a\nb")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingLines(3),
+			},
+		},
+		{
+			when: "skipping leading lines and a parsing error occurs",
+			then: "the byte index should include the skipped lines",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x\na,b\n1\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingLines(1),
+			},
+			rows:       [][]string{{"a", "b"}},
+			iterErrIs:  []error{csv.ErrParsing, csv.ErrNotEnoughFields},
+			iterErrStr: csv.ErrParsing.Error() + " at byte 8, record 2, field 1: " + csv.ErrNotEnoughFields.Error() + ": expected 2 fields but found 1",
+		},
+		{
+			when: "skipping leading records with a different number of fields",
+			then: "the skipped records should not affect field count discovery",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("\"Title, with comma\"\nx,y,z,w\na,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().Quote('"'),
+				csv.ReaderOpts().SkipLeadingRecords(2),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+			},
+			rows: [][]string{{"1", "2"}},
+		},
+		{
+			when: "skipping leading records with expected headers and column selection",
+			then: "the skipped records should not be validated or projected",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("x,y,z,w\na,b\n1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingRecords(1),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+				csv.ReaderOpts().SelectColumns(1),
+			},
+			rows: [][]string{{"b"}, {"2"}},
+		},
+		{
+			when: "dropping trailing records",
+			then: "the last records should not be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("a,b\n1,2\n3,4\nTotal,6\nRows,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().DropTrailingRecords(2),
+			},
+			rows: [][]string{{"1", "2"}, {"3", "4"}},
+		},
+		{
+			when: "dropping trailing records while borrowing rows and fields",
+			then: "buffered rows should not be corrupted",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2\n3,4\n5,6\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().BorrowRow(true),
+				csv.ReaderOpts().BorrowFields(true),
+				csv.ReaderOpts().DropTrailingRecords(1),
+			},
+			rows: [][]string{{"1", "2"}, {"3", "4"}},
+		},
+		{
+			when: "dropping more trailing records than exist",
+			then: "no rows should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2\n3,4\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().DropTrailingRecords(3),
+			},
+		},
+		{
+			when: "dropping trailing records and all records are dropped with error on no rows",
+			then: "a no rows error should be returned",
+			newOptsF: func() []csv.ReaderOption {
+				return []csv.ReaderOption{
+					csv.ReaderOpts().Reader(strings.NewReader("1,2\n")),
+				}
+			},
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().DropTrailingRecords(1),
+				csv.ReaderOpts().ErrorOnNoRows(true),
+			},
+			iterErrIs: []error{csv.ErrParsing, csv.ErrNoRows},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderSkipInitializationErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tcs := []functionalReaderTestCase{
+		{
+			when: "skipping a negative number of leading lines",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingLines(-1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nskip leading lines cannot be less than zero",
+		},
+		{
+			when: "skipping a negative number of leading records",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().SkipLeadingRecords(-1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\nskip leading records cannot be less than zero",
+		},
+		{
+			when: "dropping a negative number of trailing records",
+			then: "a bad config error should be returned",
+			newOpts: []csv.ReaderOption{
+				csv.ReaderOpts().DropTrailingRecords(-1),
+			},
+			newReaderErrIs:  []error{csv.ErrBadConfig},
+			newReaderErrStr: csv.ErrBadConfig.Error() + "\ndrop trailing records cannot be less than zero",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.newOptsF = func() []csv.ReaderOption {
+			return []csv.ReaderOption{
+				csv.ReaderOpts().Reader(strings.NewReader("")),
+			}
+		}
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalReaderSkippedContentCallbacks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("given leading lines, leading records, and trailing records are skipped", func(t *testing.T) {
+		t.Run("should report all skipped content to the callbacks", func(t *testing.T) {
+			var lines []string
+			var records [][]string
+
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("Bank Export\r\nAccount: 1234\nfrom,to\na,b\n1,2\n3,4\nTotal,2")),
+				csv.ReaderOpts().SkipLeadingLines(2),
+				csv.ReaderOpts().SkipLeadingRecords(1),
+				csv.ReaderOpts().DropTrailingRecords(1),
+				csv.ReaderOpts().ExpectHeaders("a", "b"),
+				csv.ReaderOpts().RemoveHeaderRow(true),
+				csv.ReaderOpts().OnSkippedLine(func(line []byte) {
+					lines = append(lines, string(line))
+				}),
+				csv.ReaderOpts().OnSkippedRecord(func(row []string) {
+					records = append(records, row)
+				}),
+			)
+			assert.Nil(t, err)
+
+			var rows [][]string
+			for cr.Scan() {
+				rows = append(rows, cr.Row())
+			}
+			assert.Nil(t, cr.Err())
+			assert.Nil(t, cr.Close())
+
+			assert.Equal(t, []string{"Bank Export", "Account: 1234"}, lines)
+			assert.Equal(t, [][]string{{"from", "to"}, {"Total", "2"}}, records)
+			assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}}, rows)
+		})
+	})
+
+	t.Run("given the final skipped line has no terminator", func(t *testing.T) {
+		t.Run("should report the partial line", func(t *testing.T) {
+			var lines []string
+
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader("a\nb")),
+				csv.ReaderOpts().SkipLeadingLines(5),
+				csv.ReaderOpts().OnSkippedLine(func(line []byte) {
+					lines = append(lines, string(line))
+				}),
+			)
+			assert.Nil(t, err)
+
+			assert.False(t, cr.Scan())
+			assert.Nil(t, cr.Err())
+			assert.Equal(t, []string{"a", "b"}, lines)
+		})
+	})
+}
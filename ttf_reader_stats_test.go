@@ -0,0 +1,82 @@
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalReaderStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when reading a document with comments and a byte order marker", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		const doc = "\xEF\xBB\xBF# hello\r\na,b\r\n# c\r\n\"1\",22\r\n333,4\r\n"
+
+		op := csv.ReaderOpts()
+		cr, err := csv.NewReader(
+			op.Reader(strings.NewReader(doc)),
+			op.Comment('#'),
+			op.CommentsAllowedAfterStartOfRecords(true),
+			op.Quote('"'),
+			op.DiscoverRecordSeparator(true),
+			op.RemoveByteOrderMarker(true),
+		)
+		is.Nil(err)
+
+		is.Equal(csv.ReaderStats{}, cr.(csv.ExtendedReader).Stats())
+
+		var rows [][]string
+		for row := range cr.IntoIter() {
+			rows = append(rows, row)
+		}
+		is.Nil(cr.Err())
+		is.Equal([][]string{{"a", "b"}, {"1", "22"}, {"333", "4"}}, rows)
+
+		s := cr.(csv.ExtendedReader).Stats()
+		is.Equal(uint64(3), s.Records)
+		is.Equal(uint64(len(doc)), s.Bytes)
+		is.Equal(uint64(2), s.CommentLines)
+		is.Equal(uint64(len(" hello")+len(" c")), s.CommentBytes)
+		is.Equal(4, s.MaxRecordBytes)
+		is.Equal("\r\n", s.RecordSeparator)
+		is.True(s.ByteOrderMarker)
+		is.Less(uint64(0), s.BufferRefills)
+
+		is.Nil(cr.Close())
+		is.Equal(s, cr.(csv.ExtendedReader).Stats())
+	})
+
+	t.Run("when the record buffer must grow", func(t *testing.T) {
+		t.Parallel()
+
+		is := assert.New(t)
+
+		long := strings.Repeat("x", 64)
+
+		op := csv.ReaderOpts()
+		cr, err := csv.NewReader(
+			op.Reader(strings.NewReader("a\n"+long+"\nb\n")),
+			op.InitialRecordBufferSize(8),
+			op.ReaderBufferSize(16),
+		)
+		is.Nil(err)
+
+		for cr.Scan() {
+		}
+		is.Nil(cr.Err())
+
+		s := cr.(csv.ExtendedReader).Stats()
+		is.Equal(uint64(3), s.Records)
+		is.Equal(uint64(1), s.RecordBufferGrowths)
+		is.Equal(len(long), s.MaxRecordBytes)
+		is.Equal("\n", s.RecordSeparator)
+		is.False(s.ByteOrderMarker)
+		is.Less(uint64(4), s.BufferRefills)
+	})
+}
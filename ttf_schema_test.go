@@ -0,0 +1,141 @@
+package csv_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalInferSchema(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	const doc = "flag,count,big,price,ratio,when,day,wait,zip,name,blank\n" +
+		"1,-3,18446744073709551615,1.50,1e3,2024-01-02T03:04:05.5Z,2024-01-02,1.5s,02134,Zoë,\n" +
+		"0,12,7,-2,0.25,2024-01-03T00:00:00Z,2024-01-03,2m,10001,,\n" +
+		",,,,,,,,,Al,\n" +
+		"x,x,x,x,x,x,x,x,x,x,x\n"
+
+	s, err := csv.InferSchema(docReader(t, doc), 3)
+	is.Nil(err)
+	is.Equal(csv.Schema{Columns: []csv.ColumnSchema{
+		{Name: "flag", Type: csv.ColumnBool, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 1, MaxLength: 1}},
+		{Name: "count", Type: csv.ColumnInt64, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 2, MaxLength: 2}},
+		{Name: "big", Type: csv.ColumnUint64, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 1, MaxLength: 20}},
+		{Name: "price", Type: csv.ColumnDecimal, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 2, MaxLength: 4}},
+		{Name: "ratio", Type: csv.ColumnFloat64, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 3, MaxLength: 4}},
+		{Name: "when", Type: csv.ColumnTime, Layout: time.RFC3339Nano, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 20, MaxLength: 22}},
+		{Name: "day", Type: csv.ColumnTime, Layout: time.DateOnly, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 10, MaxLength: 10}},
+		{Name: "wait", Type: csv.ColumnDuration, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 2, MaxLength: 4}},
+		{Name: "zip", Type: csv.ColumnInt64, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 5, MaxLength: 5, LeadingZeros: true}},
+		{Name: "name", Type: csv.ColumnString, Observed: csv.ColumnObservations{Values: 2, Empty: true, MinLength: 2, MaxLength: 3}},
+		{Name: "blank", Type: csv.ColumnString, Observed: csv.ColumnObservations{Empty: true}},
+	}}, s)
+
+	// observations never become constraints
+	s, err = csv.InferSchema(docReader(t, "a,b\n1,\n2,x\n"), 0)
+	is.Nil(err)
+	is.Equal(csv.Schema{Columns: []csv.ColumnSchema{
+		{Name: "a", Type: csv.ColumnInt64, Observed: csv.ColumnObservations{Values: 2, MinLength: 1, MaxLength: 1}},
+		{Name: "b", Type: csv.ColumnString, Observed: csv.ColumnObservations{Values: 1, Empty: true, MinLength: 1, MaxLength: 1}},
+	}}, s)
+}
+
+func TestFunctionalInferSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf))
+	is.Nil(err)
+
+	_, err = cw.WriteRow("bool", "int64", "uint64", "float64", "time", "int")
+	is.Nil(err)
+
+	for i, v := range []struct {
+		b bool
+		i int64
+		u uint64
+		f float64
+		t time.Time
+	}{
+		{true, math.MinInt64, math.MaxUint64, math.MaxFloat64, time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)},
+		{false, 0, 0, math.SmallestNonzeroFloat64, time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("", -3600))},
+		{true, math.MaxInt64, 1, math.Inf(-1), time.Unix(0, 0).UTC()},
+	} {
+		rw, err := cw.NewRecord()
+		is.Nil(err)
+		_, err = rw.Bool(v.b).Int64(v.i).Uint64(v.u).Float64(v.f).Time(v.t).Int(i).Write()
+		is.Nil(err)
+	}
+	is.Nil(cw.Close())
+
+	s, err := csv.InferSchema(docReader(t, buf.String()), 0)
+	is.Nil(err)
+
+	var types []csv.ColumnType
+	for _, c := range s.Columns {
+		types = append(types, c.Type)
+	}
+	is.Equal([]csv.ColumnType{
+		csv.ColumnBool,
+		csv.ColumnInt64,
+		csv.ColumnUint64,
+		csv.ColumnFloat64,
+		csv.ColumnTime,
+		csv.ColumnInt64,
+	}, types)
+	is.Equal(time.RFC3339Nano, s.Columns[4].Layout)
+}
+
+func TestFunctionalInferSchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when       string
+		doc        string
+		sampleRows int
+		errIs      error
+		errStr     string
+	}{
+		{
+			when:       "the sample row count is negative",
+			doc:        "a\n",
+			sampleRows: -1,
+			errIs:      csv.ErrBadConfig,
+			errStr:     csv.ErrBadConfig.Error() + "\nsample rows must be greater than or equal to zero",
+		},
+		{
+			when:   "there is no header row",
+			doc:    "",
+			errIs:  csv.ErrNoHeaderRow,
+			errStr: csv.ErrNoHeaderRow.Error(),
+		},
+		{
+			when:   "the document cannot be parsed",
+			doc:    "a\n1,2\n",
+			errIs:  csv.ErrParsing,
+			errStr: csv.ErrParsing.Error() + " at byte 4, record 2, field 1: " + csv.ErrTooManyFields.Error() + ": field count exceeds 1",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			_, err := csv.InferSchema(docReader(t, tc.doc), tc.sampleRows)
+			is.ErrorIs(err, tc.errIs)
+			if is.NotNil(err) {
+				is.Equal(tc.errStr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func sortDoc(t *testing.T, doc string, keys []csv.SortKey, opts csv.SortOptions, readerOpts ...csv.ReaderOption) (string, error) {
+	t.Helper()
+
+	cr, err := csv.NewReader(append([]csv.ReaderOption{csv.ReaderOpts().Reader(strings.NewReader(doc))}, readerOpts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cr.Close()
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cw.Close()
+
+	err = csv.Sort(cr, cw, keys, opts)
+	return buf.String(), err
+}
+
+func TestFunctionalSort(t *testing.T) {
+	t.Parallel()
+
+	const doc = "name,qty,when\n" +
+		"b,10,2024-01-02T00:00:00Z\n" +
+		"a,9,2024-01-03T00:00:00Z\n" +
+		"c,,2024-01-01T00:00:00Z\n" +
+		"a,10.5,2023-12-31T00:00:00Z\n"
+
+	tcs := []struct {
+		when string
+		keys []csv.SortKey
+		exp  string
+	}{
+		{
+			when: "sorting by a string column",
+			keys: []csv.SortKey{{Column: "name"}},
+			exp:  "name,qty,when\na,9,2024-01-03T00:00:00Z\na,10.5,2023-12-31T00:00:00Z\nb,10,2024-01-02T00:00:00Z\nc,,2024-01-01T00:00:00Z\n",
+		},
+		{
+			when: "sorting by a numeric column",
+			keys: []csv.SortKey{{Column: "qty", Type: csv.SortNumeric}},
+			exp:  "name,qty,when\nc,,2024-01-01T00:00:00Z\na,9,2024-01-03T00:00:00Z\nb,10,2024-01-02T00:00:00Z\na,10.5,2023-12-31T00:00:00Z\n",
+		},
+		{
+			when: "sorting by a descending time column",
+			keys: []csv.SortKey{{Column: "when", Type: csv.SortTime, Descending: true}},
+			exp:  "name,qty,when\na,9,2024-01-03T00:00:00Z\nb,10,2024-01-02T00:00:00Z\nc,,2024-01-01T00:00:00Z\na,10.5,2023-12-31T00:00:00Z\n",
+		},
+		{
+			when: "sorting by several columns",
+			keys: []csv.SortKey{{Column: "name", Descending: true}, {Index: 1, Type: csv.SortNumeric, Descending: true}},
+			exp:  "name,qty,when\nc,,2024-01-01T00:00:00Z\nb,10,2024-01-02T00:00:00Z\na,10.5,2023-12-31T00:00:00Z\na,9,2024-01-03T00:00:00Z\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		for _, budget := range []int64{0, 1} {
+			t.Run(fmt.Sprintf("when %s with MemoryBudget=%d", tc.when, budget), func(t *testing.T) {
+				t.Parallel()
+
+				is := assert.New(t)
+
+				dir := t.TempDir()
+
+				out, err := sortDoc(t, doc, tc.keys, csv.SortOptions{
+					Header:       true,
+					Stable:       true,
+					MemoryBudget: budget,
+					TempDir:      dir,
+				})
+				is.Nil(err)
+				is.Equal(tc.exp, out)
+
+				// temporary run files are always removed
+				entries, err := os.ReadDir(dir)
+				is.Nil(err)
+				is.Empty(entries)
+			})
+		}
+	}
+}
+
+func TestFunctionalSortStableSpill(t *testing.T) {
+	t.Parallel()
+
+	var sb strings.Builder
+	var exp []string
+	for i := range 200 {
+		sb.WriteString(strconv.Itoa(i%7) + "," + strconv.Itoa(i) + "\n")
+	}
+	for k := range 7 {
+		for i := range 200 {
+			if i%7 == k {
+				exp = append(exp, strconv.Itoa(k)+","+strconv.Itoa(i)+"\n")
+			}
+		}
+	}
+
+	// a fan-in below the number of runs requires several merge passes
+	for _, fanIn := range []int{0, 2, 3} {
+		t.Run(fmt.Sprintf("when MaxMergeFanIn=%d", fanIn), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			dir := t.TempDir()
+
+			out, err := sortDoc(t, sb.String(), []csv.SortKey{{Index: 0, Type: csv.SortNumeric}}, csv.SortOptions{
+				Stable:        true,
+				MemoryBudget:  1024,
+				MaxMergeFanIn: fanIn,
+				TempDir:       dir,
+			})
+			is.Nil(err)
+			is.Equal(strings.Join(exp, ""), out)
+
+			entries, err := os.ReadDir(dir)
+			is.Nil(err)
+			is.Empty(entries)
+		})
+	}
+}
+
+func TestFunctionalSortSpillQuotedFields(t *testing.T) {
+	t.Parallel()
+
+	const doc = "k,v\n3,\"say \"\"hi\"\"\"\n1,\"a,b\"\n4,\"\"\n2,\"line1\nline2\"\n"
+
+	exp := [][]string{
+		{"k", "v"},
+		{"1", "a,b"},
+		{"2", "line1\nline2"},
+		{"3", `say "hi"`},
+		{"4", ""},
+	}
+
+	for _, budget := range []int64{0, 1} {
+		t.Run(fmt.Sprintf("when MemoryBudget=%d", budget), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			out, err := sortDoc(t, doc, []csv.SortKey{{Column: "k", Type: csv.SortNumeric}}, csv.SortOptions{
+				Header:       true,
+				MemoryBudget: budget,
+				TempDir:      t.TempDir(),
+			}, csv.ReaderOpts().Quote('"'))
+			is.Nil(err)
+
+			cr, err := csv.NewReader(
+				csv.ReaderOpts().Reader(strings.NewReader(out)),
+				csv.ReaderOpts().Quote('"'),
+			)
+			is.Nil(err)
+			defer cr.Close()
+
+			var rows [][]string
+			for row := range cr.IntoIter() {
+				rows = append(rows, slices.Clone(row))
+			}
+			is.Nil(cr.Err())
+			is.Equal(exp, rows)
+		})
+	}
+}
+
+func TestFunctionalSortEmptyValuesFirst(t *testing.T) {
+	t.Parallel()
+
+	const doc = "k,v\nb,1\n,2\na,3\n"
+
+	tcs := []struct {
+		when       string
+		descending bool
+		exp        string
+	}{
+		{
+			when: "ascending",
+			exp:  "k,v\n,2\na,3\nb,1\n",
+		},
+		{
+			when:       "descending",
+			descending: true,
+			exp:        "k,v\n,2\nb,1\na,3\n",
+		},
+	}
+
+	for _, tc := range tcs {
+		for _, budget := range []int64{0, 1} {
+			t.Run(fmt.Sprintf("when %s with MemoryBudget=%d", tc.when, budget), func(t *testing.T) {
+				t.Parallel()
+
+				is := assert.New(t)
+
+				out, err := sortDoc(t, doc, []csv.SortKey{{Column: "k", Descending: tc.descending}}, csv.SortOptions{
+					Header:       true,
+					MemoryBudget: budget,
+					TempDir:      t.TempDir(),
+				})
+				is.Nil(err)
+				is.Equal(tc.exp, out)
+			})
+		}
+	}
+}
+
+func TestFunctionalSortColumnMap(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	out, err := sortDoc(t, "k,v\nb,1\na,2\n", []csv.SortKey{{Column: "k"}}, csv.SortOptions{},
+		csv.ReaderOpts().ExpectHeaders("k", "v"),
+		csv.ReaderOpts().RemoveHeaderRow(true),
+	)
+	is.Nil(err)
+	is.Equal("a,2\nb,1\n", out)
+}
+
+func TestFunctionalSortErrors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when   string
+		doc    string
+		keys   []csv.SortKey
+		opts   csv.SortOptions
+		errIs  error
+		errStr string
+	}{
+		{
+			when:   "no keys are specified",
+			doc:    "a\n",
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nno sort keys",
+		},
+		{
+			when:   "the sort type is invalid",
+			doc:    "a\n",
+			keys:   []csv.SortKey{{Type: csv.SortTime + 1}},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\ninvalid sort type",
+		},
+		{
+			when:   "the sort index is negative",
+			doc:    "a\n",
+			keys:   []csv.SortKey{{Index: -1}},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nsort key index must be greater than or equal to zero",
+		},
+		{
+			when:   "the memory budget is negative",
+			doc:    "a\n",
+			keys:   []csv.SortKey{{}},
+			opts:   csv.SortOptions{MemoryBudget: -1},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nmemory budget must be greater than or equal to zero",
+		},
+		{
+			when:   "the max merge fan-in is one",
+			doc:    "a\n",
+			keys:   []csv.SortKey{{}},
+			opts:   csv.SortOptions{MaxMergeFanIn: 1},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nmax merge fan-in must be greater than one",
+		},
+		{
+			when:   "the sort column is not in the header",
+			doc:    "a\n1\n",
+			keys:   []csv.SortKey{{Column: "b"}},
+			opts:   csv.SortOptions{Header: true},
+			errIs:  csv.ErrBadConfig,
+			errStr: csv.ErrBadConfig.Error() + "\nsort key column \"b\" not found",
+		},
+		{
+			when:   "the sort index is out of range",
+			doc:    "a\n1\n",
+			keys:   []csv.SortKey{{Index: 1}},
+			errStr: "sort key column 1: index out of range",
+		},
+		{
+			when:   "a numeric value is invalid",
+			doc:    "a\n1\nx\n",
+			keys:   []csv.SortKey{{Column: "a", Type: csv.SortNumeric}},
+			opts:   csv.SortOptions{Header: true},
+			errStr: "sort key column a: strconv.ParseFloat: parsing \"x\": invalid syntax",
+		},
+		{
+			when:   "the input cannot be parsed",
+			doc:    "a\n1,2\n",
+			keys:   []csv.SortKey{{}},
+			errIs:  csv.ErrParsing,
+			errStr: csv.ErrParsing.Error() + " at byte 4, record 2, field 1: " + csv.ErrTooManyFields.Error() + ": field count exceeds 1",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			tc.opts.TempDir = t.TempDir()
+			_, err := sortDoc(t, tc.doc, tc.keys, tc.opts)
+			if tc.errIs != nil {
+				is.ErrorIs(err, tc.errIs)
+			}
+			if is.NotNil(err) {
+				is.Equal(tc.errStr, err.Error())
+			}
+		})
+	}
+}
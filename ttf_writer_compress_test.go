@@ -0,0 +1,354 @@
+package csv_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterCompressInitErrors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	tcs := []functionalWriterTestCase{
+		{
+			when: "compression is not supported for writing",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Compress(csv.CompressionBzip2, gzip.DefaultCompression),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\ninvalid compression",
+		},
+		{
+			when: "compression level is invalid",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Compress(csv.CompressionGzip, gzip.BestCompression+1),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\ninvalid compression level",
+		},
+		{
+			when: "gzip members are enabled without compression",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().GzipMembers(csv.GzipMemberInterval{Records: 1}),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\ngzip members and index require gzip compression",
+		},
+		{
+			when: "a gzip index is enabled without compression",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().GzipIndex(&buf),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\ngzip members and index require gzip compression",
+		},
+		{
+			when: "the gzip member interval is zero",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+				csv.WriterOpts().GzipMembers(csv.GzipMemberInterval{}),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\ngzip member interval must specify a number of bytes or records",
+		},
+		{
+			when: "the gzip index writer is nil",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+				csv.WriterOpts().GzipIndex(nil),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\nnil gzip index writer",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func gunzip(t *testing.T, p []byte) string {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func TestFunctionalWriterCompressGzip(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&buf),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.BestSpeed),
+	)
+	is.Nil(err)
+
+	_, err = cw.WriteHeader(
+		csv.WriteHeaderOpts().IncludeByteOrderMarker(true),
+		csv.WriteHeaderOpts().Headers("a", "b"),
+	)
+	is.Nil(err)
+
+	n, err := cw.WriteRow("1", "2")
+	is.Nil(err)
+	is.Equal(4, n)
+
+	// nothing is guaranteed to be written until the trailer is flushed
+	is.Nil(cw.Close())
+	is.Nil(cw.Close())
+
+	is.Equal("\xEF\xBB\xBFa,b\n1,2\n", gunzip(t, buf.Bytes()))
+	is.Equal(uint64(len("\xEF\xBB\xBFa,b\n1,2\n")), cw.Stats().Bytes)
+}
+
+func TestFunctionalWriterCompressGzipEmpty(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf, idx bytes.Buffer
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&buf),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+		csv.WriterOpts().GzipIndex(&idx),
+	)
+	is.Nil(err)
+	is.Nil(cw.Close())
+
+	is.NotZero(buf.Len())
+	is.Equal("", gunzip(t, buf.Bytes()))
+	is.Equal("offset,compressed_bytes,uncompressed_offset,uncompressed_bytes,first_record,records\n0,"+strconv.Itoa(buf.Len())+",0,0,0,0\n", idx.String())
+}
+
+func TestFunctionalWriterCompressGzipMembers(t *testing.T) {
+	t.Parallel()
+
+	for _, memclear := range []bool{false, true} {
+		for _, useRecordWriter := range []bool{false, true} {
+			t.Run(fmt.Sprintf("when ClearFreedDataMemory=%v and useRecordWriter=%v", memclear, useRecordWriter), func(t *testing.T) {
+				t.Parallel()
+
+				is := assert.New(t)
+
+				var buf, idx bytes.Buffer
+				cw, err := csv.NewWriter(
+					csv.WriterOpts().Writer(&buf),
+					csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+					csv.WriterOpts().GzipMembers(csv.GzipMemberInterval{Records: 3, Bytes: 20}),
+					csv.WriterOpts().GzipIndex(&idx),
+					csv.WriterOpts().ClearFreedDataMemory(memclear),
+				)
+				is.Nil(err)
+
+				_, err = cw.WriteHeader(csv.WriteHeaderOpts().Headers("id", "name"))
+				is.Nil(err)
+
+				// the record limit closes the first member after the header and two
+				// records, then the byte limit closes the second member after the
+				// long record
+				for _, row := range [][]string{
+					{"1", "a"},
+					{"2", "b"},
+					{"3", "a much longer name"},
+					{"4", "d"},
+				} {
+					if useRecordWriter {
+						rw := cw.MustNewRecord()
+						_, err = rw.String(row[0]).String(row[1]).Write()
+					} else {
+						_, err = cw.WriteRow(row...)
+					}
+					is.Nil(err)
+				}
+
+				is.Nil(cw.Close())
+
+				doc := "id,name\n1,a\n2,b\n3,a much longer name\n4,d\n"
+				is.Equal(doc, gunzip(t, buf.Bytes()))
+
+				cr, err := csv.NewReader(
+					csv.ReaderOpts().Reader(bytes.NewReader(idx.Bytes())),
+					csv.ReaderOpts().ExpectHeaders("offset", "compressed_bytes", "uncompressed_offset", "uncompressed_bytes", "first_record", "records"),
+					csv.ReaderOpts().RemoveHeaderRow(true),
+				)
+				is.Nil(err)
+
+				var members []csv.GzipMember
+				for row := range cr.IntoIter() {
+					var v [6]uint64
+					for i := range v {
+						v[i], err = strconv.ParseUint(row[i], 10, 64)
+						is.Nil(err)
+					}
+					members = append(members, csv.GzipMember{v[0], v[1], v[2], v[3], v[4], v[5]})
+				}
+				is.Nil(cr.Err())
+
+				if !is.Len(members, 3) {
+					return
+				}
+
+				is.Equal(uint64(0), members[0].Offset)
+				is.Equal(uint64(buf.Len()), members[2].Offset+members[2].CompressedBytes)
+
+				var firstRecord, uncompressedOffset uint64
+				for i, m := range members {
+					if i > 0 {
+						is.Equal(members[i-1].Offset+members[i-1].CompressedBytes, m.Offset)
+					}
+					is.Equal(firstRecord, m.FirstRecord)
+					is.Equal(uncompressedOffset, m.UncompressedOffset)
+
+					// every member can be decompressed on its own
+					s := gunzip(t, buf.Bytes()[m.Offset:m.Offset+m.CompressedBytes])
+					is.Equal(doc[m.UncompressedOffset:m.UncompressedOffset+m.UncompressedBytes], s)
+
+					firstRecord += m.Records
+					uncompressedOffset += m.UncompressedBytes
+				}
+
+				is.Equal([]uint64{3, 1, 1}, []uint64{members[0].Records, members[1].Records, members[2].Records})
+				is.Equal(uint64(len(doc)), uncompressedOffset)
+			})
+		}
+	}
+}
+
+func TestFunctionalWriterCompressGzipRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&buf),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+		csv.WriterOpts().GzipMembers(csv.GzipMemberInterval{Records: 1}),
+	)
+	is.Nil(err)
+
+	var expRows [][]string
+	for i := range 10 {
+		row := []string{strconv.Itoa(i), "x"}
+		expRows = append(expRows, row)
+		_, err = cw.WriteRow(row...)
+		is.Nil(err)
+	}
+	is.Nil(cw.Close())
+
+	cr, err := csv.NewReader(
+		csv.ReaderOpts().Reader(&buf),
+		csv.ReaderOpts().Decompress(csv.CompressionAuto),
+	)
+	is.Nil(err)
+
+	var rows [][]string
+	for row := range cr.IntoIter() {
+		rows = append(rows, row)
+	}
+	is.Nil(cr.Err())
+	is.Equal(expRows, rows)
+}
+
+func TestFunctionalWriterCompressGzipReset(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf1, buf2 bytes.Buffer
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&buf1),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+		csv.WriterOpts().ClearFreedDataMemory(true),
+	)
+	is.Nil(err)
+
+	_, err = cw.WriteRow("a")
+	is.Nil(err)
+	is.Nil(cw.Close())
+
+	is.Nil(cw.Reset(&buf2))
+	_, err = cw.WriteRow("b")
+	is.Nil(err)
+	is.Nil(cw.Close())
+
+	is.Equal("a\n", gunzip(t, buf1.Bytes()))
+	is.Equal("b\n", gunzip(t, buf2.Bytes()))
+
+	var idx bytes.Buffer
+	cw, err = csv.NewWriter(
+		csv.WriterOpts().Writer(&buf1),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+		csv.WriterOpts().GzipIndex(&idx),
+	)
+	is.Nil(err)
+
+	err = cw.Reset(&buf2)
+	is.ErrorIs(err, csv.ErrBadConfig)
+	is.Equal(csv.ErrBadConfig.Error()+"\nreset is not supported when writing a gzip index", err.Error())
+}
+
+// failingWriter accepts n bytes and then fails every write with err.
+type failingWriter struct {
+	err error
+	n   int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, w.err
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestFunctionalWriterCompressGzipCloseError(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	errWrite := fmt.Errorf("write failed")
+
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&failingWriter{err: errWrite, n: 10}),
+		csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+	)
+	is.Nil(err)
+
+	// the gzip header is written immediately while the compressor buffers
+	// small writes
+	_, err = cw.WriteRow("a")
+	is.Nil(err)
+
+	err = cw.Close()
+	is.ErrorIs(err, csv.ErrIO)
+	is.ErrorIs(err, errWrite)
+}
@@ -0,0 +1,130 @@
+package csv_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterContextPaths(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	canceledErrStr := csv.ErrIO.Error() + ": " + context.Canceled.Error()
+
+	tcs := []functionalWriterTestCase{
+		{
+			when: "context is nil",
+			then: "a bad config error should be returned",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Context(nil),
+			},
+			newWriterErrIs:  []error{csv.ErrBadConfig},
+			newWriterErrStr: csv.ErrBadConfig.Error() + "\nnil context",
+		},
+		{
+			when: "context can never be done",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Context(context.Background()),
+			},
+			wrs: []wr{
+				{r: []string{"a", "b"}, n: 4},
+			},
+			res: "a,b\n",
+		},
+		{
+			when: "context is done",
+			then: "writes should fail with an io error wrapping the context error",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Context(canceledCtx),
+			},
+			wrs: []wr{
+				{r: []string{"a", "b"}, errIs: []error{csv.ErrIO, context.Canceled}, errStr: canceledErrStr},
+			},
+		},
+		{
+			when: "context is done and writing headers",
+			then: "the header write should fail with an io error wrapping the context error",
+			newOpts: []csv.WriterOption{
+				csv.WriterOpts().Context(canceledCtx),
+			},
+			whOpts: []csv.WriteHeaderOption{
+				csv.WriteHeaderOpts().Headers("a", "b"),
+			},
+			whErrIs:  []error{csv.ErrWriteHeaderFailed, csv.ErrIO, context.Canceled},
+			whErrStr: csv.ErrWriteHeaderFailed.Error() + "\n" + canceledErrStr,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.Run(t)
+	}
+}
+
+func TestFunctionalWriterContextDoneBetweenRecords(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf), csv.WriterOpts().Context(ctx))
+	is.Nil(err)
+
+	_, err = cw.WriteRow("a")
+	is.Nil(err)
+
+	cancel()
+
+	rw, err := cw.NewRecord()
+	is.Nil(err)
+	n, err := rw.String("b").Write()
+	is.Zero(n)
+	is.ErrorIs(err, csv.ErrIO)
+	is.ErrorIs(err, context.Canceled)
+	is.ErrorIs(rw.Err(), context.Canceled)
+
+	// the writer is left in the error state
+	_, err2 := cw.WriteRow("c")
+	is.Equal(err, err2)
+
+	is.Equal("a\n", buf.String())
+	is.Nil(cw.Close())
+}
+
+func TestFunctionalWriterWriteRowContext(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	cw, err := csv.NewWriter(csv.WriterOpts().Writer(&buf))
+	is.Nil(err)
+
+	n, err := cw.WriteRowContext(context.Background(), "a", "b")
+	is.Nil(err)
+	is.Equal(4, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n, err = cw.WriteRowContext(ctx, "c", "d")
+	is.Zero(n)
+	is.ErrorIs(err, csv.ErrIO)
+	is.ErrorIs(err, context.Canceled)
+	is.Equal(csv.ErrIO.Error()+": "+context.Canceled.Error(), err.Error())
+
+	// the writer is left in the error state
+	_, err2 := cw.WriteRow("e", "f")
+	is.Equal(err, err2)
+
+	is.Equal("a,b\n", buf.String())
+	is.Nil(cw.Close())
+}
@@ -0,0 +1,48 @@
+package csv_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterDialect(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		when string
+		opts []csv.WriterOption
+		exp  csv.Dialect
+	}{
+		{
+			when: "the writer uses the defaults",
+			exp:  csv.Dialect{FieldSeparator: ',', Quote: '"', RecordSeparator: "\n"},
+		},
+		{
+			when: "the writer is customized",
+			opts: []csv.WriterOption{
+				csv.WriterOpts().FieldSeparator('¦'),
+				csv.WriterOpts().Quote('\''),
+				csv.WriterOpts().Escape('\\'),
+				csv.WriterOpts().CommentRune('#'),
+				csv.WriterOpts().RecordSeparator("\r\n"),
+			},
+			exp: csv.Dialect{FieldSeparator: '¦', Quote: '\'', Escape: '\\', Comment: '#', RecordSeparator: "\r\n"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			cw, err := csv.NewWriter(append(tc.opts, csv.WriterOpts().Writer(io.Discard))...)
+			is.Nil(err)
+
+			is.Equal(tc.exp, cw.Dialect())
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package csv_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// memPartitions is an in-memory set of partition destinations keyed by
+// partition key which tracks how each one was opened.
+type memPartitions struct {
+	docs    map[string]*bytes.Buffer
+	opens   []string
+	numOpen int
+	maxOpen int
+	err     error
+}
+
+type memPartition struct {
+	*bytes.Buffer
+	mp     *memPartitions
+	closed bool
+}
+
+func (p *memPartition) Close() error {
+	if p.closed {
+		return errors.New("already closed")
+	}
+	p.closed = true
+	p.mp.numOpen--
+	return p.mp.err
+}
+
+func (mp *memPartitions) factory(key string, reopen bool) (io.WriteCloser, error) {
+	buf, ok := mp.docs[key]
+	if ok != reopen {
+		return nil, fmt.Errorf("partition %s: unexpected reopen=%v", key, reopen)
+	}
+	if !ok {
+		buf = &bytes.Buffer{}
+		mp.docs[key] = buf
+	}
+
+	mp.opens = append(mp.opens, fmt.Sprintf("%s:%v", key, reopen))
+	mp.numOpen++
+	mp.maxOpen = max(mp.maxOpen, mp.numOpen)
+
+	return &memPartition{Buffer: buf, mp: mp}, nil
+}
+
+func newMemPartitions() *memPartitions {
+	return &memPartitions{docs: map[string]*bytes.Buffer{}}
+}
+
+func firstColumn(row []string) (string, error) {
+	return row[0], nil
+}
+
+func TestFunctionalPartitionedWriterInitErrors(t *testing.T) {
+	t.Parallel()
+
+	mp := newMemPartitions()
+	op := csv.PartitionedWriterOpts()
+
+	tcs := []struct {
+		when    string
+		keyFunc func([]string) (string, error)
+		factory func(string, bool) (io.WriteCloser, error)
+		opts    []csv.PartitionedWriterOption
+		errStr  string
+	}{
+		{
+			when:    "the key function is nil",
+			factory: mp.factory,
+			errStr:  "nil key function",
+		},
+		{
+			when:    "the factory is nil",
+			keyFunc: firstColumn,
+			errStr:  "nil partition factory",
+		},
+		{
+			when:    "max open partitions is zero",
+			keyFunc: firstColumn,
+			factory: mp.factory,
+			opts:    []csv.PartitionedWriterOption{op.MaxOpenPartitions(0)},
+			errStr:  "max open partitions must be greater than zero",
+		},
+		{
+			when:    "a writer is specified",
+			keyFunc: firstColumn,
+			factory: mp.factory,
+			opts:    []csv.PartitionedWriterOption{op.WriterOptions(csv.WriterOpts().Writer(io.Discard))},
+			errStr:  "a writer cannot be specified when partitioning",
+		},
+		{
+			when:    "a gzip index is specified",
+			keyFunc: firstColumn,
+			factory: mp.factory,
+			opts: []csv.PartitionedWriterOption{op.WriterOptions(
+				csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression),
+				csv.WriterOpts().GzipIndex(io.Discard),
+			)},
+			errStr: "a gzip index cannot be written when partitioning",
+		},
+		{
+			when:    "writer options are invalid",
+			keyFunc: firstColumn,
+			factory: mp.factory,
+			opts:    []csv.PartitionedWriterOption{op.WriterOptions(csv.WriterOpts().FieldSeparator('"'))},
+			errStr:  "invalid field separator and quote combination",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			pw, err := csv.NewPartitionedWriter(tc.keyFunc, tc.factory, tc.opts...)
+			is.Nil(pw)
+			is.ErrorIs(err, csv.ErrBadConfig)
+			is.Equal(csv.ErrBadConfig.Error()+"\n"+tc.errStr, err.Error())
+		})
+	}
+}
+
+func TestFunctionalPartitionedWriter(t *testing.T) {
+	t.Parallel()
+
+	for _, maxOpen := range []int{0, 1, 2} {
+		t.Run(fmt.Sprintf("when MaxOpenPartitions=%d", maxOpen), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			mp := newMemPartitions()
+
+			var opts []csv.PartitionedWriterOption
+			if maxOpen > 0 {
+				opts = append(opts, csv.PartitionedWriterOpts().MaxOpenPartitions(maxOpen))
+			}
+			opts = append(opts, csv.PartitionedWriterOpts().WriterOptions(csv.WriterOpts().FieldSeparator(';')))
+
+			pw, err := csv.NewPartitionedWriter(firstColumn, mp.factory, opts...)
+			is.Nil(err)
+
+			is.Nil(pw.WriteHeader(
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().CommentLines("by customer"),
+				csv.WriteHeaderOpts().Headers("customer", "amount"),
+			))
+			is.ErrorIs(pw.WriteHeader(), csv.ErrHeaderWritten)
+
+			for _, row := range [][]string{
+				{"a", "1"},
+				{"b", "2"},
+				{"a", "3"},
+				{"c", "4"},
+				{"b", "5"},
+				{"a", "6;7"},
+			} {
+				_, err = pw.WriteRow(row...)
+				is.Nil(err)
+			}
+			is.Equal(3, pw.NumPartitions())
+
+			is.Nil(pw.Close())
+			is.Nil(pw.Close())
+
+			_, err = pw.WriteRow("a", "8")
+			is.ErrorIs(err, csv.ErrWriterClosed)
+
+			header := "# by customer\ncustomer;amount\n"
+			docs := map[string]string{}
+			for k, v := range mp.docs {
+				docs[k] = v.String()
+			}
+			is.Equal(map[string]string{
+				"a": header + "a;1\na;3\na;\"6;7\"\n",
+				"b": header + "b;2\nb;5\n",
+				"c": header + "c;4\n",
+			}, docs)
+			is.Zero(mp.numOpen)
+
+			switch maxOpen {
+			case 0:
+				is.Equal(3, mp.maxOpen)
+				is.Equal([]string{"a:false", "b:false", "c:false"}, mp.opens)
+			case 1:
+				is.Equal(1, mp.maxOpen)
+				is.Equal([]string{"a:false", "b:false", "a:true", "c:false", "b:true", "a:true"}, mp.opens)
+			case 2:
+				is.Equal(2, mp.maxOpen)
+				is.Equal([]string{"a:false", "b:false", "c:false", "b:true", "a:true"}, mp.opens)
+			}
+		})
+	}
+}
+
+func TestFunctionalPartitionedWriterGzipReopen(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	mp := newMemPartitions()
+
+	pw, err := csv.NewPartitionedWriter(firstColumn, mp.factory,
+		csv.PartitionedWriterOpts().MaxOpenPartitions(1),
+		csv.PartitionedWriterOpts().WriterOptions(csv.WriterOpts().Compress(csv.CompressionGzip, gzip.DefaultCompression)),
+	)
+	is.Nil(err)
+
+	is.Nil(pw.WriteHeader(csv.WriteHeaderOpts().Headers("key", "value")))
+
+	for _, row := range [][]string{{"a", "1"}, {"b", "2"}, {"a", "3"}} {
+		_, err = pw.WriteRow(row...)
+		is.Nil(err)
+	}
+	is.Nil(pw.Close())
+	is.Zero(mp.numOpen)
+
+	is.Equal("key,value\na,1\na,3\n", gunzip(t, mp.docs["a"].Bytes()))
+	is.Equal("key,value\nb,2\n", gunzip(t, mp.docs["b"].Bytes()))
+
+	// every time a partition is opened a new compressed stream is started
+	br := bytes.NewReader(mp.docs["a"].Bytes())
+	gr, err := gzip.NewReader(br)
+	is.Nil(err)
+	var members []string
+	for err == nil {
+		gr.Multistream(false)
+		b, rErr := io.ReadAll(gr)
+		is.Nil(rErr)
+		members = append(members, string(b))
+
+		err = gr.Reset(br)
+	}
+	is.ErrorIs(err, io.EOF)
+	is.Equal([]string{"key,value\na,1\n", "a,3\n"}, members)
+}
+
+func TestFunctionalPartitionedWriterErrors(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	errKey := errors.New("no key")
+	errClose := errors.New("close failed")
+
+	mp := newMemPartitions()
+	mp.err = errClose
+
+	pw, err := csv.NewPartitionedWriter(
+		func(row []string) (string, error) {
+			if row[0] == "" {
+				return "", errKey
+			}
+			return row[0], nil
+		},
+		mp.factory,
+		csv.PartitionedWriterOpts().MaxOpenPartitions(1),
+	)
+	is.Nil(err)
+
+	err = pw.WriteHeader(csv.WriteHeaderOpts().Headers())
+	is.ErrorIs(err, csv.ErrBadConfig)
+
+	_, err = pw.WriteRow("", "x")
+	is.Equal(errKey, err)
+	is.Zero(pw.NumPartitions())
+
+	_, err = pw.WriteRow("a", "1")
+	is.Nil(err)
+
+	// closing partition a to make room for b fails but is only reported by Close
+	_, err = pw.WriteRow("b", "2")
+	is.Nil(err)
+
+	// a failed write to one partition does not affect the others
+	_, err = pw.WriteRow("b")
+	is.ErrorIs(err, csv.ErrInvalidFieldCountInRecord)
+	_, err = pw.WriteRow("c", "3")
+	is.Nil(err)
+
+	err = pw.Close()
+	is.ErrorIs(err, errClose)
+
+	var pErr csv.PartitionError
+	is.ErrorAs(err, &pErr)
+	is.Equal("partition a: close failed\npartition b: close failed\npartition c: close failed", err.Error())
+}
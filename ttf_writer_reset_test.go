@@ -0,0 +1,83 @@
+package csv_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterReset(t *testing.T) {
+	t.Parallel()
+
+	for _, memclear := range []bool{false, true} {
+		is := assert.New(t)
+
+		var buf1, buf2 bytes.Buffer
+
+		op := csv.WriterOpts()
+		cw, err := csv.NewWriter(
+			op.Writer(&buf1),
+			op.CommentRune('#'),
+			op.ClearFreedDataMemory(memclear),
+		)
+		is.Nil(err)
+
+		_, err = cw.WriteRow("#1", "2")
+		is.Nil(err)
+		is.Nil(cw.Close())
+
+		_, err = cw.WriteRow("3", "4")
+		is.ErrorIs(err, csv.ErrWriterClosed)
+
+		err = cw.Reset(nil)
+		is.ErrorIs(err, csv.ErrBadConfig)
+		is.Equal(csv.ErrBadConfig.Error()+"\nnil writer", err.Error())
+
+		is.Nil(cw.Reset(&buf2))
+		is.Equal(csv.WriterStats{}, cw.Stats())
+
+		// the field count, header, and first record state must all be rediscovered
+		_, err = cw.WriteHeader(csv.WriteHeaderOpts().Headers("#x"))
+		is.Nil(err)
+		rw := cw.MustNewRecord()
+		rw.String("#y")
+
+		is.ErrorIs(cw.Reset(&buf1), csv.ErrWriterNotReady)
+
+		_, err = rw.Write()
+		is.Nil(err)
+		is.Nil(cw.Close())
+
+		is.Equal("\"#1\",2\n", buf1.String())
+		is.Equal("\"#x\"\n#y\n", buf2.String())
+		is.Equal(uint64(2), cw.Stats().Records)
+	}
+}
+
+func TestFunctionalWriterResetAfterIOError(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	ioErr := errors.New("boom")
+
+	cw, err := csv.NewWriter(
+		csv.WriterOpts().Writer(&errWriter{err: ioErr}),
+	)
+	is.Nil(err)
+
+	_, err = cw.WriteRow("a")
+	is.ErrorIs(err, ioErr)
+	_, err = cw.WriteRow("b")
+	is.ErrorIs(err, ioErr)
+
+	var buf bytes.Buffer
+	is.Nil(cw.Reset(&buf))
+
+	_, err = cw.WriteRow("c")
+	is.Nil(err)
+	is.Equal("c\n", buf.String())
+}
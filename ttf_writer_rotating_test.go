@@ -0,0 +1,267 @@
+package csv_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// memPart is an in-memory part created by a RotatingWriter part factory.
+type memPart struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (p *memPart) Close() error {
+	p.closed = true
+	return nil
+}
+
+// memParts returns a part factory which records every part it creates.
+func memParts() (*[]*memPart, func(int) (io.WriteCloser, error)) {
+	var parts []*memPart
+	return &parts, func(part int) (io.WriteCloser, error) {
+		if part != len(parts) {
+			return nil, fmt.Errorf("unexpected part %d", part)
+		}
+		p := &memPart{}
+		parts = append(parts, p)
+		return p, nil
+	}
+}
+
+func TestFunctionalRotatingWriterInitErrors(t *testing.T) {
+	t.Parallel()
+
+	_, factory := memParts()
+
+	op := csv.RotatingWriterOpts()
+
+	tcs := []struct {
+		when    string
+		factory func(int) (io.WriteCloser, error)
+		opts    []csv.RotatingWriterOption
+		errStr  string
+	}{
+		{
+			when:   "the factory is nil",
+			opts:   []csv.RotatingWriterOption{op.MaxPartRecords(1)},
+			errStr: "nil part factory",
+		},
+		{
+			when:    "no limits are specified",
+			factory: factory,
+			errStr:  "max part bytes or max part records must be specified",
+		},
+		{
+			when:    "max part bytes is zero",
+			factory: factory,
+			opts:    []csv.RotatingWriterOption{op.MaxPartBytes(0)},
+			errStr:  "max part bytes must be greater than zero",
+		},
+		{
+			when:    "max part records is zero",
+			factory: factory,
+			opts:    []csv.RotatingWriterOption{op.MaxPartRecords(0)},
+			errStr:  "max part records must be greater than zero",
+		},
+		{
+			when:    "a writer is specified",
+			factory: factory,
+			opts:    []csv.RotatingWriterOption{op.MaxPartRecords(1), op.WriterOptions(csv.WriterOpts().Writer(io.Discard))},
+			errStr:  "a writer cannot be specified when rotating parts",
+		},
+		{
+			when:    "compression is specified",
+			factory: factory,
+			opts:    []csv.RotatingWriterOption{op.MaxPartRecords(1), op.WriterOptions(csv.WriterOpts().Compress(csv.CompressionGzip, 1))},
+			errStr:  "compression cannot be used when rotating parts",
+		},
+		{
+			when:    "writer options are invalid",
+			factory: factory,
+			opts:    []csv.RotatingWriterOption{op.MaxPartRecords(1), op.WriterOptions(csv.WriterOpts().NumFields(0))},
+			errStr:  "num fields must be greater than zero",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run("when "+tc.when, func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			rw, err := csv.NewRotatingWriter(tc.factory, tc.opts...)
+			is.Nil(rw)
+			is.ErrorIs(err, csv.ErrBadConfig)
+			is.Equal(csv.ErrBadConfig.Error()+"\n"+tc.errStr, err.Error())
+		})
+	}
+}
+
+func TestFunctionalRotatingWriterMaxPartRecords(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	parts, factory := memParts()
+
+	rw, err := csv.NewRotatingWriter(factory,
+		csv.RotatingWriterOpts().MaxPartRecords(2),
+		csv.RotatingWriterOpts().WriterOptions(csv.WriterOpts().RecordSeparator("\r\n")),
+	)
+	is.Nil(err)
+
+	_, err = rw.WriteHeader(
+		csv.WriteHeaderOpts().IncludeByteOrderMarker(true),
+		csv.WriteHeaderOpts().CommentRune('#'),
+		csv.WriteHeaderOpts().CommentLines("export"),
+		csv.WriteHeaderOpts().Headers("id", "name"),
+	)
+	is.Nil(err)
+	is.Equal(0, rw.Part())
+
+	for i := range 5 {
+		_, err = rw.WriteRow(strconv.Itoa(i), "#x")
+		is.Nil(err)
+	}
+	is.Equal(2, rw.Part())
+	is.Nil(rw.Close())
+	is.Nil(rw.Close())
+
+	header := "\xEF\xBB\xBF# export\r\nid,name\r\n"
+
+	if !is.Len(*parts, 3) {
+		return
+	}
+	is.Equal(header+"0,#x\r\n1,#x\r\n", (*parts)[0].String())
+	is.Equal(header+"2,#x\r\n3,#x\r\n", (*parts)[1].String())
+	is.Equal(header+"4,#x\r\n", (*parts)[2].String())
+
+	var total uint64
+	for _, p := range *parts {
+		is.True(p.closed)
+		total += uint64(p.Len())
+	}
+
+	s := rw.Stats()
+	is.Equal(uint64(6), s.Records)
+	is.Equal(total, s.Bytes)
+}
+
+func TestFunctionalRotatingWriterMaxPartBytes(t *testing.T) {
+	t.Parallel()
+
+	for _, memclear := range []bool{false, true} {
+		t.Run(fmt.Sprintf("when ClearFreedDataMemory=%v", memclear), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			parts, factory := memParts()
+
+			rw, err := csv.NewRotatingWriter(factory,
+				csv.RotatingWriterOpts().MaxPartBytes(16),
+				csv.RotatingWriterOpts().WriterOptions(csv.WriterOpts().ClearFreedDataMemory(memclear)),
+			)
+			is.Nil(err)
+
+			_, err = rw.WriteHeader(csv.WriteHeaderOpts().Headers("a", "b"))
+			is.Nil(err)
+
+			_, err = rw.WriteRow("1", "2")
+			is.Nil(err)
+
+			// records written through a RecordWriter are never split
+			rec := rw.MustNewRecord()
+			_, err = rec.String("3").Int(4).Write()
+			is.Nil(err)
+
+			_, err = rw.WriteFieldRow(csv.FieldWriters().String("5"), csv.FieldWriters().String("6"))
+			is.Nil(err)
+
+			// a record which alone exceeds the limit is still written whole
+			_, err = rw.WriteRow("a longer record", "7")
+			is.Nil(err)
+
+			_, err = rw.WriteFieldRowBorrowed([]csv.FieldWriter{csv.FieldWriters().String("8"), csv.FieldWriters().String("9")})
+			is.Nil(err)
+
+			is.Nil(rw.Close())
+
+			var docs []string
+			for _, p := range *parts {
+				is.True(p.closed)
+				docs = append(docs, p.String())
+			}
+			is.Equal([]string{
+				"a,b\n1,2\n3,4\n5,6\n",
+				"a,b\na longer record,7\n",
+				"a,b\n8,9\n",
+			}, docs)
+		})
+	}
+}
+
+func TestFunctionalRotatingWriterCloseWithoutWrites(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	parts, factory := memParts()
+
+	rw, err := csv.NewRotatingWriter(factory, csv.RotatingWriterOpts().MaxPartRecords(1))
+	is.Nil(err)
+	is.Nil(rw.Close())
+
+	if is.Len(*parts, 1) {
+		is.True((*parts)[0].closed)
+		is.Equal("", (*parts)[0].String())
+	}
+}
+
+func TestFunctionalRotatingWriterFactoryError(t *testing.T) {
+	t.Parallel()
+
+	is := assert.New(t)
+
+	errFactory := errors.New("factory failed")
+
+	var parts []*memPart
+	rw, err := csv.NewRotatingWriter(
+		func(part int) (io.WriteCloser, error) {
+			if part == 1 {
+				return nil, errFactory
+			}
+			p := &memPart{}
+			parts = append(parts, p)
+			return p, nil
+		},
+		csv.RotatingWriterOpts().MaxPartRecords(1),
+	)
+	is.Nil(err)
+
+	_, err = rw.WriteRow("a")
+	is.Nil(err)
+
+	_, err = rw.WriteRow("b")
+	is.ErrorIs(err, csv.ErrIO)
+	is.ErrorIs(err, errFactory)
+
+	// the writer is left in the error state
+	_, err2 := rw.WriteRow("c")
+	is.Equal(err, err2)
+
+	is.Nil(rw.Close())
+
+	if is.Len(parts, 1) {
+		is.True(parts[0].closed)
+		is.Equal("a\n", parts[0].String())
+	}
+}
@@ -0,0 +1,69 @@
+package csv_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/josephcopenhaver/csv-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionalWriterStats(t *testing.T) {
+	t.Parallel()
+
+	for _, memclear := range []bool{false, true} {
+		t.Run(fmt.Sprintf("when writing headers, rows, and records with ClearFreedDataMemory=%v", memclear), func(t *testing.T) {
+			t.Parallel()
+
+			is := assert.New(t)
+
+			var buf bytes.Buffer
+
+			op := csv.WriterOpts()
+			cw, err := csv.NewWriter(
+				op.Writer(&buf),
+				op.ClearFreedDataMemory(memclear),
+			)
+			is.Nil(err)
+
+			is.Equal(csv.WriterStats{}, cw.Stats())
+
+			_, err = cw.WriteHeader(
+				csv.WriteHeaderOpts().IncludeByteOrderMarker(true),
+				csv.WriteHeaderOpts().CommentRune('#'),
+				csv.WriteHeaderOpts().CommentLines("hi"),
+				csv.WriteHeaderOpts().Headers("a", "b"),
+			)
+			is.Nil(err)
+
+			_, err = cw.WriteRow("x,y", `say "hi"`)
+			is.Nil(err)
+
+			_, err = cw.WriteFieldRow(csv.FieldWriters().Bytes([]byte(`"`)), csv.FieldWriters().Int(1))
+			is.Nil(err)
+
+			rw := cw.MustNewRecord()
+			rw.String("never").String(`"written"`)
+			rw.Rollback()
+
+			rw = cw.MustNewRecord()
+			rw.String("plain").String("a\nb")
+			_, err = rw.Write()
+			is.Nil(err)
+
+			_, err = cw.WriteRow("bad\xff", "x")
+			is.NotNil(err)
+
+			is.Nil(cw.Close())
+
+			is.Equal(csv.WriterStats{
+				Records:        4,
+				Bytes:          uint64(buf.Len()),
+				QuotedFields:   4,
+				EscapedFields:  2,
+				MaxRecordBytes: len("\"x,y\",\"say \"\"hi\"\"\"\n"),
+			}, cw.Stats())
+		})
+	}
+}
@@ -842,3 +842,61 @@ func TestRuneSetIndexNonePaths(t *testing.T) {
 		tc.Run(t)
 	}
 }
+
+func TestRuneSetSWARParity(t *testing.T) {
+	t.Parallel()
+
+	// alphabet is biased towards the runes in the sets below so that matches
+	// land at every offset within a word
+	alphabet := []string{"a", "b", ",", "\"", "\r", "\n", "\x00", "\x7F", "\u0085", " ", "\U0001F600", "é", "\xE2", "\x80", "\xC2"}
+	singleBytes := ",\"\r\n\x00\x7F\t|;#"
+
+	rng := rand.New(rand.NewSource(1))
+
+	for numSingleBytes := 0; numSingleBytes <= len(singleBytes); numSingleBytes++ {
+		for _, mbRunes := range []string{"", "\u0085", "\u0085\u2028\U0001F600"} {
+			set := singleBytes[:numSingleBytes] + mbRunes
+
+			var rs4, ref4 runeSet4
+			var rs6, ref6 runeSet6
+			for _, r := range set {
+				rs4.addRune(r)
+				ref4.addRune(r)
+				rs6.addRune(r)
+				ref6.addRune(r)
+			}
+			ref4.swarEnabled = false
+			ref6.swarEnabled = false
+
+			t.Run(fmt.Sprintf("when set is %+q/then results match byte at a time scanning", set), func(t *testing.T) {
+				is := assert.New(t)
+
+				is.Equal(numSingleBytes > 0 && numSingleBytes <= swarMaxBytes, rs6.swarEnabled)
+
+				for range 500 {
+					var s string
+					for n := rng.Intn(40); n > 0; n-- {
+						s += alphabet[rng.Intn(len(alphabet))]
+					}
+					p := []byte(s)
+
+					er, en, ei := ref4.indexAnyRuneLenInString(s)
+					r, n, i := rs4.indexAnyRuneLenInString(s)
+					is.Equal([]any{er, en, ei}, []any{r, n, i}, "rS4 %+q", s)
+					is.Equal(ei, rs4.indexAnyInString(s), "rS4 %+q", s)
+					is.Equal(ei, rs4.indexAnyInBytes(p), "rS4 %+q", s)
+					r, n, i = rs4.indexAnyRuneLenInBytes(p)
+					is.Equal([]any{er, en, ei}, []any{r, n, i}, "rS4 %+q", s)
+
+					er, en, ei = ref6.indexAnyRuneLenInString(s)
+					r, n, i = rs6.indexAnyRuneLenInString(s)
+					is.Equal([]any{er, en, ei}, []any{r, n, i}, "rS6 %+q", s)
+					is.Equal(ei, rs6.indexAnyInString(s), "rS6 %+q", s)
+					is.Equal(ei, rs6.indexAnyInBytes(p), "rS6 %+q", s)
+					r, n, i = rs6.indexAnyRuneLenInBytes(p)
+					is.Equal([]any{er, en, ei}, []any{r, n, i}, "rS6 %+q", s)
+				}
+			})
+		}
+	}
+}
@@ -6,6 +6,19 @@ type writeBuffer struct {
 	escapedQuoteSeq      twoRuneEncoder
 	escapedEscapeSeq     twoRuneEncoder
 	quote                rune
+
+	// recQuotedFields and recEscapedFields count the fields of the
+	// record currently being buffered which were quoted or contained
+	// runes that needed to be escaped.
+	recQuotedFields  int
+	recEscapedFields int
+}
+
+// resetRecordBuf prepares the write buffer to stage a new record.
+func (wb *writeBuffer) resetRecordBuf() {
+	wb.recordBuf = wb.recordBuf[:0]
+	wb.recQuotedFields = 0
+	wb.recEscapedFields = 0
 }
 
 // setRecordBuf should only be called when the record buf has been appended to
@@ -1,8 +1,11 @@
 package csv
 
 import (
+	"compress/gzip"
+	"context"
 	"errors"
 	"io"
+	"slices"
 	"strings"
 	"unicode/utf8"
 	"unsafe"
@@ -66,7 +69,11 @@ func (e writeIOErr) Error() string {
 
 type wCfg struct {
 	writer                     io.Writer
+	ctx                        context.Context
+	gzipIndex                  io.Writer
+	gzipMemberInterval         GzipMemberInterval
 	initialRecordBufferSize    int
+	compressionLevel           int
 	recordBuf                  []byte
 	recordSep                  [2]rune
 	numFields                  int
@@ -82,6 +89,10 @@ type wCfg struct {
 	commentSet                 bool
 	recordSepRuneLen           int8
 	clearMemoryAfterFree       bool
+	ctxSet                     bool
+	compression                Compression
+	gzipMembersSet             bool
+	gzipIndexSet               bool
 }
 
 type WriterOption func(*wCfg)
@@ -178,6 +189,63 @@ func (WriterOptions) Writer(v io.Writer) WriterOption {
 	}
 }
 
+// Context enables cancellation of writing.
+//
+// The context is checked before each record is written by WriteRow,
+// WriteFieldRow, WriteFieldRowBorrowed, and RecordWriter.Write. Once it is
+// done those calls return an error classified as ErrIO which wraps
+// ctx.Err() and nothing further is written.
+func (WriterOptions) Context(ctx context.Context) WriterOption {
+	return func(cfg *wCfg) {
+		cfg.ctx = ctx
+		cfg.ctxSet = true
+	}
+}
+
+// Compress compresses everything written to the underlying writer,
+// including byte order markers and comment lines.
+//
+// Only CompressionNone and CompressionGzip are supported. The level must be
+// between gzip.HuffmanOnly and gzip.BestCompression, see compress/gzip.
+//
+// Close must be called to flush the gzip trailer. It will still never close
+// the underlying writer. WriterStats.Bytes counts bytes before compression.
+func (WriterOptions) Compress(c Compression, level int) WriterOption {
+	return func(cfg *wCfg) {
+		cfg.compression = c
+		cfg.compressionLevel = level
+	}
+}
+
+// GzipMembers writes a multi-member gzip stream which starts a new member
+// each time the interval is reached. Members always begin at a record
+// boundary, which allows the stream to be split and decompressed in
+// parallel.
+//
+// Requires Compress to be enabled with CompressionGzip.
+func (WriterOptions) GzipMembers(interval GzipMemberInterval) WriterOption {
+	return func(cfg *wCfg) {
+		cfg.gzipMemberInterval = interval
+		cfg.gzipMembersSet = true
+	}
+}
+
+// GzipIndex writes a sidecar CSV document to w describing the location of
+// each gzip member as it is completed, see GzipMember. The document has a
+// header row of:
+//
+//	offset,compressed_bytes,uncompressed_offset,uncompressed_bytes,first_record,records
+//
+// Requires Compress to be enabled with CompressionGzip. The index is
+// complete once Close returns. Reset is not supported when an index is
+// being written.
+func (WriterOptions) GzipIndex(w io.Writer) WriterOption {
+	return func(cfg *wCfg) {
+		cfg.gzipIndex = w
+		cfg.gzipIndexSet = true
+	}
+}
+
 func (WriterOptions) NumFields(v int) WriterOption {
 	return func(cfg *wCfg) {
 		cfg.numFields = v
@@ -202,6 +270,9 @@ func (WriterOptions) Escape(r rune) WriterOption {
 // that contains data goes out of scope that zero values are written
 // to every byte within the buffer.
 //
+// When the Compress option is enabled the history window of the
+// compressor is also overwritten by Close and Reset.
+//
 // This may significantly degrade performance and is recommended only
 // for sensitive data or long-lived processes.
 func (WriterOptions) ClearFreedDataMemory(b bool) WriterOption {
@@ -298,6 +369,10 @@ func (cfg *wCfg) validate() error {
 		return errors.New("nil writer")
 	}
 
+	if cfg.ctxSet && cfg.ctx == nil {
+		return errors.New("nil context")
+	}
+
 	if cfg.recordSepRuneLen == 0 {
 		return errors.New("record separator can only be one valid utf8 newline rune long or \"\\r\\n\"")
 	}
@@ -349,6 +424,27 @@ func (cfg *wCfg) validate() error {
 		}
 	}
 
+	switch cfg.compression {
+	case CompressionNone:
+		if cfg.gzipMembersSet || cfg.gzipIndexSet {
+			return errors.New("gzip members and index require gzip compression")
+		}
+	case CompressionGzip:
+		if cfg.compressionLevel < gzip.HuffmanOnly || cfg.compressionLevel > gzip.BestCompression {
+			return errors.New("invalid compression level")
+		}
+	default:
+		return errors.New("invalid compression")
+	}
+
+	if cfg.gzipMembersSet && cfg.gzipMemberInterval == (GzipMemberInterval{}) {
+		return errors.New("gzip member interval must specify a number of bytes or records")
+	}
+
+	if cfg.gzipIndexSet && cfg.gzipIndex == nil {
+		return errors.New("nil gzip index writer")
+	}
+
 	return nil
 }
 
@@ -361,8 +457,12 @@ type Writer struct {
 	recordSepSeq    runeEncoder
 	quoteSeq        runeEncoder
 	numFields       int
+	cfgNumFields    int
 	writer          io.Writer
+	ctx             context.Context
+	gz              *gzipWriter
 	err             error
+	stats           WriterStats
 	escape, comment rune
 	bitFlags        wFlag
 }
@@ -458,6 +558,19 @@ func NewWriter(options ...WriterOption) (*Writer, error) {
 		comment = cfg.comment
 	}
 
+	// a context that can never be done does not need to be checked
+	ctx := cfg.ctx
+	if ctx != nil && ctx.Done() == nil {
+		ctx = nil
+	}
+
+	writer := cfg.writer
+	var gz *gzipWriter
+	if cfg.compression == CompressionGzip {
+		gz = newGzipWriter(writer, cfg.compressionLevel, cfg.gzipMemberInterval, cfg.gzipIndex)
+		writer = gz
+	}
+
 	w := &Writer{
 		writeBuffer: writeBuffer{
 			recordBuf:            recordBuf,
@@ -467,7 +580,10 @@ func NewWriter(options ...WriterOption) (*Writer, error) {
 			quote:                cfg.quote,
 		},
 		numFields:      cfg.numFields,
-		writer:         cfg.writer,
+		cfgNumFields:   cfg.numFields,
+		writer:         writer,
+		ctx:            ctx,
+		gz:             gz,
 		controlRuneSet: controlRuneSet,
 		twoQuotesSeq:   twoQuotesSeq,
 		fieldSepSeq:    fieldSepSeq,
@@ -499,11 +615,16 @@ func (w *Writer) writeRow(row []FieldWriter) (int, error) {
 		}
 	}
 
+	var n int
+	var err error
 	if (w.bitFlags & wFlagClearMemoryAfterFree) == 0 {
-		return w.writeRow_memclearOff(row)
+		n, err = w.writeRow_memclearOff(row)
+	} else {
+		n, err = w.writeRow_memclearOn(row)
 	}
 
-	return w.writeRow_memclearOn(row)
+	w.trackRecord(n, err)
+	return n, err
 }
 
 func (w *Writer) writeStrRow(row []string) (int, error) {
@@ -524,18 +645,24 @@ func (w *Writer) writeStrRow(row []string) (int, error) {
 		}
 	}
 
+	var n int
+	var err error
 	if (w.bitFlags & wFlagClearMemoryAfterFree) == 0 {
-		return w.writeStrRow_memclearOff(row)
+		n, err = w.writeStrRow_memclearOff(row)
+	} else {
+		n, err = w.writeStrRow_memclearOn(row)
 	}
 
-	return w.writeStrRow_memclearOn(row)
+	w.trackRecord(n, err)
+	return n, err
 }
 
 // Close should be called after writing all rows
 // successfully to the underlying writer.
 //
-// Close currently always returns nil, but in the future
-// it may not.
+// Close returns nil unless the Compress option is enabled, in which case
+// it writes the gzip trailer and any remaining sidecar index entries and
+// returns an error classified as ErrIO should that fail.
 //
 // Should any configuration options require post-flight
 // checks they will be implemented here.
@@ -550,22 +677,84 @@ func (w *Writer) Close() error {
 
 	w.setErr(ErrWriterClosed)
 
+	var err error
+	if w.gz != nil {
+		if err = w.gz.close(); err != nil {
+			err = writeIOErr{err}
+		}
+	}
+
 	if (w.bitFlags & wFlagClearMemoryAfterFree) != 0 {
 		clear(w.recordBuf[:cap(w.recordBuf)])
 		clear(w.fieldWriterBuf[:])
+
+		if w.gz != nil {
+			w.gz.clearMemory()
+		}
 	}
 
+	return err
+}
+
+// Reset discards all writing state and prepares the Writer to write a new
+// document to writer using the same configuration.
+//
+// Discovered field counts, header state, statistics, and any error or closed
+// state are reinitialized while the record buffer is kept for reuse. When
+// ClearFreedDataMemory is enabled the record buffer is zeroed before it is
+// reused.
+//
+// Reset returns ErrWriterNotReady while a RecordWriter is active.
+//
+// When the Compress option is enabled a new compressed stream is started
+// and any incomplete prior stream is discarded without writing its
+// trailer. Reset is not supported when the GzipIndex option is enabled.
+func (w *Writer) Reset(writer io.Writer) error {
+	if writer == nil {
+		return errors.Join(ErrBadConfig, errors.New("nil writer"))
+	}
+
+	if w.gz != nil && w.gz.index != nil {
+		return errors.Join(ErrBadConfig, errors.New("reset is not supported when writing a gzip index"))
+	}
+
+	if (w.bitFlags & wFlagRecordBuffCheckedOut) != 0 {
+		return ErrWriterNotReady
+	}
+
+	if (w.bitFlags & wFlagClearMemoryAfterFree) != 0 {
+		clear(w.recordBuf[:cap(w.recordBuf)])
+		clear(w.fieldWriterBuf[:])
+
+		if w.gz != nil {
+			w.gz.clearMemory()
+		}
+	}
+
+	w.resetRecordBuf()
+	if w.gz != nil {
+		w.gz.reset(writer)
+		writer = w.gz
+	}
+	w.writer = writer
+	w.numFields = w.cfgNumFields
+	w.err = nil
+	w.stats = WriterStats{}
+	w.bitFlags &= ^(wFlagFirstRecordWritten | wFlagHeaderWritten | wFlagClosed)
+
 	return nil
 }
 
 type whCfg struct {
 	headers         []string
 	commentLines    []string
+	metadata        []MetadataEntry
 	comment         rune
 	trimHeaders     bool
 	headersSet      bool
 	commentSet      bool
 	commentLinesSet bool
+	metadataSet     bool
 	includeBOM      bool
 }
 
@@ -625,6 +814,33 @@ func (WriteHeaderOptions) CommentLines(s ...string) WriteHeaderOption {
 	}
 }
 
+// Metadata writes each key value pair as a comment line of the form
+// "key: value" after any CommentLines, ordered by key.
+//
+// Use MetadataEntries when a specific order is required. Keys must not be
+// empty. Backslashes, colons within keys, and newline runes are escaped so
+// the values can be recovered via ExtendedReader.Metadata by a reader
+// configured with RetainComments.
+func (WriteHeaderOptions) Metadata(m map[string]string) WriteHeaderOption {
+	entries := make([]MetadataEntry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, MetadataEntry{k, v})
+	}
+	slices.SortFunc(entries, func(a, b MetadataEntry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return WriteHeaderOpts().MetadataEntries(entries...)
+}
+
+// MetadataEntries is the ordered equivalent of Metadata.
+func (WriteHeaderOptions) MetadataEntries(entries ...MetadataEntry) WriteHeaderOption {
+	return func(cfg *whCfg) {
+		cfg.metadata = entries
+		cfg.metadataSet = true
+	}
+}
+
 func (WriteHeaderOptions) IncludeByteOrderMarker(b bool) WriteHeaderOption {
 	return func(cfg *whCfg) {
 		cfg.includeBOM = b
@@ -640,6 +856,12 @@ func (cfg *whCfg) validate(w *Writer) error {
 		}
 	}
 
+	for _, e := range cfg.metadata {
+		if e.Key == "" {
+			return errors.New("metadata keys must not be empty")
+		}
+	}
+
 	if !cfg.commentSet {
 		if w.comment != invalidControlRune {
 			// loads the value from the parent writer context and
@@ -648,6 +870,8 @@ func (cfg *whCfg) validate(w *Writer) error {
 			cfg.comment = w.comment
 		} else if cfg.commentLinesSet {
 			return errors.New("comment lines require a comment rune")
+		} else if cfg.metadataSet {
+			return errors.New("metadata requires a comment rune")
 		}
 	} else if w.comment != invalidControlRune {
 		return errors.New("comment rune cannot be specified when writing headers while the writer instance already has one specified")
@@ -676,6 +900,17 @@ func (cfg *whCfg) validate(w *Writer) error {
 		w.numFields = len(cfg.headers)
 	}
 
+	if cfg.metadataSet {
+		lines := make([]string, 0, len(cfg.commentLines)+len(cfg.metadata))
+		lines = append(lines, cfg.commentLines...)
+		for _, e := range cfg.metadata {
+			lines = append(lines, encodeMetadataEntry(e))
+		}
+
+		cfg.commentLines = lines
+		cfg.commentLinesSet = true
+	}
+
 	return nil
 }
 
@@ -687,6 +922,7 @@ func (w *Writer) writeBOM() (int, error) {
 	}
 
 	n, err := w.writer.Write(utf8BOMBytes)
+	w.stats.Bytes += uint64(n)
 	if err != nil {
 		err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 		w.setErr(err)
@@ -779,6 +1015,7 @@ func (w *Writer) WriteHeader(options ...WriteHeaderOption) (int, error) {
 
 				n, err = w.writer.Write(linePrefix)
 				result += n
+				w.stats.Bytes += uint64(n)
 				if err != nil {
 					err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 					w.setErr(err)
@@ -811,6 +1048,7 @@ func (w *Writer) WriteHeader(options ...WriteHeaderOption) (int, error) {
 							if scanIdx != len(line) {
 								n, err = w.writer.Write(line[scanIdx:])
 								result += n
+								w.stats.Bytes += uint64(n)
 								if err != nil {
 									err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 									w.setErr(err)
@@ -826,6 +1064,7 @@ func (w *Writer) WriteHeader(options ...WriteHeaderOption) (int, error) {
 							// write the non-newline content before newline and comment prefix
 							n, err = w.writer.Write(line[prevIdx:scanIdx])
 							result += n
+							w.stats.Bytes += uint64(n)
 							if err != nil {
 								err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 								w.setErr(err)
@@ -837,6 +1076,7 @@ func (w *Writer) WriteHeader(options ...WriteHeaderOption) (int, error) {
 
 						n, err = w.writer.Write(recSepAndLinePrefix)
 						result += n
+						w.stats.Bytes += uint64(n)
 						if err != nil {
 							err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 							w.setErr(err)
@@ -847,6 +1087,7 @@ func (w *Writer) WriteHeader(options ...WriteHeaderOption) (int, error) {
 
 				n, err = w.writer.Write(recSep)
 				result += n
+				w.stats.Bytes += uint64(n)
 				if err != nil {
 					err = errors.Join(ErrWriteHeaderFailed, writeIOErr{err})
 					w.setErr(err)
@@ -897,11 +1138,25 @@ func (w *Writer) WriteRow(row ...string) (int, error) {
 		return 0, err
 	}
 
-	w.recordBuf = w.recordBuf[:0]
+	w.resetRecordBuf()
 
 	return w.writeStrRow(row)
 }
 
+// WriteRowContext is like WriteRow but first checks that ctx is not done.
+//
+// Should ctx be done an error classified as ErrIO which wraps ctx.Err() is
+// returned, nothing is written, and the Writer is left in that error state.
+func (w *Writer) WriteRowContext(ctx context.Context, row ...string) (int, error) {
+	if err := ctx.Err(); err != nil && w.err == nil && (w.bitFlags&wFlagRecordBuffCheckedOut) == 0 {
+		err = writeIOErr{err}
+		w.setErr(err)
+		return 0, err
+	}
+
+	return w.WriteRow(row...)
+}
+
 // WriteFieldRow will take a vararg collection of FieldWriter instances and write them as a csv record row.
 //
 // Each subsequent call to WriteRow, WriteFieldRow, or WriteFieldRowBorrowed should have the same slice length.
@@ -915,7 +1170,7 @@ func (w *Writer) WriteFieldRow(row ...FieldWriter) (int, error) {
 		return 0, err
 	}
 
-	w.recordBuf = w.recordBuf[:0]
+	w.resetRecordBuf()
 
 	return w.writeRow(row)
 }
@@ -929,7 +1184,7 @@ func (w *Writer) WriteFieldRowBorrowed(row []FieldWriter) (int, error) {
 		return 0, err
 	}
 
-	w.recordBuf = w.recordBuf[:0]
+	w.resetRecordBuf()
 
 	return w.writeRow(row)
 }
@@ -961,6 +1216,15 @@ func (w *Writer) writeRowPreflightCheck(n int) error {
 		return ErrRowNilOrEmpty
 	}
 
+	// check if the writer's context is done
+	if ctx := w.ctx; ctx != nil {
+		if err := ctx.Err(); err != nil {
+			err = writeIOErr{err}
+			w.setErr(err)
+			return err
+		}
+	}
+
 	if v := w.numFields; v != n {
 		if v != -1 {
 
@@ -0,0 +1,229 @@
+package csv
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// GzipMemberInterval configures how often a new gzip member is started when
+// writing a multi-member gzip stream.
+//
+// A new member is started after the first record which makes either limit be
+// reached or exceeded, so records are never split across members. A zero
+// value for either field disables that limit.
+type GzipMemberInterval struct {
+	// Bytes is the number of uncompressed bytes after which a new member
+	// is started.
+	Bytes uint64
+	// Records is the number of records, including any header row, after
+	// which a new member is started.
+	Records uint64
+}
+
+// GzipMember describes the location of one member within a gzip stream
+// produced by a Writer.
+//
+// Every member is a complete gzip stream on its own, so decompression can
+// begin at any member Offset.
+type GzipMember struct {
+	// Offset is the position of the first byte of the member within the
+	// compressed stream.
+	Offset uint64
+	// CompressedBytes is the length of the member within the compressed
+	// stream.
+	CompressedBytes uint64
+	// UncompressedOffset is the position of the first byte of the member
+	// within the uncompressed document.
+	UncompressedOffset uint64
+	// UncompressedBytes is the number of uncompressed bytes the member
+	// contains.
+	UncompressedBytes uint64
+	// FirstRecord is the zero based index of the first record within the
+	// member, counting any header row as a record.
+	FirstRecord uint64
+	// Records is the number of records the member contains.
+	Records uint64
+}
+
+// gzipIndexHeaders are the column names of the sidecar index document
+// written when the GzipIndex option is used.
+var gzipIndexHeaders = [...]string{
+	"offset",
+	"compressed_bytes",
+	"uncompressed_offset",
+	"uncompressed_bytes",
+	"first_record",
+	"records",
+}
+
+// zeroBlock is compressed into io.Discard to overwrite the history window of
+// a gzip writer when ClearFreedDataMemory is enabled.
+var zeroBlock [1 << 14]byte
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += uint64(n)
+	return n, err
+}
+
+// gzipWriter compresses everything written to it as a sequence of gzip
+// members, starting a new member at the first write after a record boundary
+// which reached the configured interval.
+//
+// Members are started lazily so a stream never ends with an empty member.
+type gzipWriter struct {
+	dst      countingWriter
+	gz       *gzip.Writer
+	index    *Writer
+	interval GzipMemberInterval
+	member   GzipMember
+	// numRecords is the number of records written to all prior members
+	numRecords uint64
+	// uncompressedBytes is the number of bytes written to all prior members
+	uncompressedBytes uint64
+	numMembers        uint64
+	memberOpen        bool
+	memberFull        bool
+}
+
+func newGzipWriter(w io.Writer, level int, interval GzipMemberInterval, index io.Writer) *gzipWriter {
+	gw := &gzipWriter{
+		dst:      countingWriter{w: w},
+		interval: interval,
+	}
+
+	// the level has already been validated
+	gw.gz, _ = gzip.NewWriterLevel(&gw.dst, level)
+
+	if index != nil {
+		// the configuration is static and always valid
+		gw.index, _ = NewWriter(WriterOpts().Writer(index))
+	}
+
+	return gw
+}
+
+func (gw *gzipWriter) Write(p []byte) (int, error) {
+	if gw.memberFull {
+		if err := gw.closeMember(); err != nil {
+			return 0, err
+		}
+	}
+
+	if !gw.memberOpen {
+		gw.openMember()
+	}
+
+	n, err := gw.gz.Write(p)
+	gw.member.UncompressedBytes += uint64(n)
+	return n, err
+}
+
+func (gw *gzipWriter) openMember() {
+	gw.gz.Reset(&gw.dst)
+	gw.member = GzipMember{
+		Offset:             gw.dst.n,
+		UncompressedOffset: gw.uncompressedBytes,
+		FirstRecord:        gw.numRecords,
+	}
+	gw.memberOpen = true
+}
+
+// closeMember writes the trailer of the current member and appends the
+// member to the sidecar index if one is configured.
+func (gw *gzipWriter) closeMember() error {
+	gw.memberFull = false
+	gw.memberOpen = false
+
+	if err := gw.gz.Close(); err != nil {
+		return err
+	}
+
+	m := &gw.member
+	m.CompressedBytes = gw.dst.n - m.Offset
+	gw.numRecords += m.Records
+	gw.uncompressedBytes += m.UncompressedBytes
+	gw.numMembers++
+
+	if gw.index == nil {
+		return nil
+	}
+
+	if gw.numMembers == 1 {
+		if _, err := gw.index.WriteRow(gzipIndexHeaders[:]...); err != nil {
+			return err
+		}
+	}
+
+	f := FieldWriters()
+	_, err := gw.index.WriteFieldRow(
+		f.Uint64(m.Offset),
+		f.Uint64(m.CompressedBytes),
+		f.Uint64(m.UncompressedOffset),
+		f.Uint64(m.UncompressedBytes),
+		f.Uint64(m.FirstRecord),
+		f.Uint64(m.Records),
+	)
+	return err
+}
+
+// endRecord is called after each record is successfully written and marks
+// the current member as full once the member interval is reached.
+func (gw *gzipWriter) endRecord() {
+	m := &gw.member
+	m.Records++
+
+	if (gw.interval.Records > 0 && m.Records >= gw.interval.Records) || (gw.interval.Bytes > 0 && m.UncompressedBytes >= gw.interval.Bytes) {
+		gw.memberFull = true
+	}
+}
+
+// close flushes the trailer of the final member. An empty member is written
+// when nothing was ever written so the output is always a valid gzip stream.
+func (gw *gzipWriter) close() error {
+	if !gw.memberOpen && gw.numMembers == 0 {
+		gw.openMember()
+	}
+
+	if gw.memberOpen {
+		if err := gw.closeMember(); err != nil {
+			return err
+		}
+	}
+
+	if gw.index != nil {
+		return gw.index.Close()
+	}
+
+	return nil
+}
+
+// reset discards all member state and prepares the gzipWriter to compress
+// a new stream written to w.
+func (gw *gzipWriter) reset(w io.Writer) {
+	gw.dst = countingWriter{w: w}
+	gw.member = GzipMember{}
+	gw.numRecords = 0
+	gw.uncompressedBytes = 0
+	gw.numMembers = 0
+	gw.memberOpen = false
+	gw.memberFull = false
+}
+
+// clearMemory overwrites the history window and pending output of the
+// compressor, which would otherwise retain uncompressed data from the most
+// recently written member.
+func (gw *gzipWriter) clearMemory() {
+	gw.gz.Reset(io.Discard)
+	for range 8 {
+		_, _ = gw.gz.Write(zeroBlock[:])
+	}
+	_ = gw.gz.Close()
+	gw.gz.Reset(io.Discard)
+}
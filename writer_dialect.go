@@ -0,0 +1,40 @@
+package csv
+
+import "unicode/utf8"
+
+// Dialect describes the format of the documents a Writer produces.
+type Dialect struct {
+	FieldSeparator rune
+	Quote          rune
+	// Escape is the rune which escapes quotes within quoted fields. It is
+	// zero when quotes are escaped by doubling them.
+	Escape rune
+	// Comment is the rune which begins comment lines. It is zero unless the
+	// Writer was configured with CommentRune.
+	Comment         rune
+	RecordSeparator string
+}
+
+// Dialect returns the format the Writer was configured to produce.
+//
+// Options given to WriteHeader, such as a comment rune which only applies
+// to the header, are not reflected.
+func (w *Writer) Dialect() Dialect {
+	fieldSep, _ := utf8.DecodeRune(w.fieldSepSeq.b[:w.fieldSepSeq.n])
+
+	d := Dialect{
+		FieldSeparator:  fieldSep,
+		Quote:           w.quote,
+		RecordSeparator: string(w.recordSepSeq.b[:w.recordSepSeq.n]),
+	}
+
+	if w.escape != invalidControlRune {
+		d.Escape = w.escape
+	}
+
+	if w.comment != invalidControlRune {
+		d.Comment = w.comment
+	}
+
+	return d
+}
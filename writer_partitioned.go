@@ -0,0 +1,345 @@
+package csv
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"io"
+)
+
+// PartitionError identifies which partition of a PartitionedWriter an error
+// relates to.
+type PartitionError struct {
+	Key string
+	Err error
+}
+
+func (e PartitionError) Error() string {
+	return "partition " + e.Key + ": " + e.Err.Error()
+}
+
+func (e PartitionError) Unwrap() error {
+	return e.Err
+}
+
+type pwCfg struct {
+	writerOpts        []WriterOption
+	maxOpenPartitions int
+	maxOpenSet        bool
+}
+
+type PartitionedWriterOption func(*pwCfg)
+
+// PartitionedWriterOptions should never be instantiated manually
+//
+// Instead call PartitionedWriterOpts()
+//
+// This is only exported to allow godocs to discover the exported methods.
+//
+// PartitionedWriterOptions will never have exported members and the zero value is not
+// part of the semver guarantee. Instantiate it incorrectly at your own peril.
+//
+// Calling the function is a nop that is compiled away anyways, you will not
+// optimize anything at all. Use PartitionedWriterOpts()!
+type PartitionedWriterOptions struct{}
+
+func PartitionedWriterOpts() PartitionedWriterOptions {
+	return PartitionedWriterOptions{}
+}
+
+// MaxOpenPartitions bounds the number of partition destinations which are
+// open at the same time.
+//
+// When the bound is reached the least recently written partition and its
+// Writer are closed, releasing any compression state. Should another record
+// be routed to it the destination is reopened in append mode with a new
+// Writer which does not write the header again. A compressed partition then
+// consists of several concatenated compressed streams.
+// By default the number of open partitions is unbounded.
+func (PartitionedWriterOptions) MaxOpenPartitions(n int) PartitionedWriterOption {
+	return func(cfg *pwCfg) {
+		cfg.maxOpenPartitions = n
+		cfg.maxOpenSet = true
+	}
+}
+
+// WriterOptions configures the Writer created for every partition. The
+// Writer option must not be specified.
+func (PartitionedWriterOptions) WriterOptions(options ...WriterOption) PartitionedWriterOption {
+	return func(cfg *pwCfg) {
+		cfg.writerOpts = append(cfg.writerOpts, options...)
+	}
+}
+
+func (cfg *pwCfg) validate() error {
+	if cfg.maxOpenSet && cfg.maxOpenPartitions <= 0 {
+		return errors.New("max open partitions must be greater than zero")
+	}
+
+	var wc wCfg
+	for _, f := range cfg.writerOpts {
+		f(&wc)
+	}
+
+	if wc.writer != nil {
+		return errors.New("a writer cannot be specified when partitioning")
+	}
+
+	if wc.gzipIndexSet {
+		return errors.New("a gzip index cannot be written when partitioning")
+	}
+
+	return nil
+}
+
+// partition is the destination of every record routed to a single key.
+//
+// Its Writer and destination are only held while the partition is open so
+// that closing the least recently written partition to bound the number of
+// open destinations also releases any compression state.
+type partition struct {
+	key  string
+	w    *Writer
+	wc   io.WriteCloser
+	elem *list.Element
+	// closeErr is the first error returned when closing the destination
+	// to make room for another partition
+	closeErr error
+	opened   bool
+}
+
+// close closes the Writer and destination of the partition if it is open.
+func (p *partition) close(lru *list.List) {
+	if p.w == nil {
+		return
+	}
+
+	// closing the Writer may flush a compression trailer
+	err := p.w.Close()
+	err = errors.Join(err, p.wc.Close())
+
+	lru.Remove(p.elem)
+	p.elem = nil
+	p.w = nil
+	p.wc = nil
+
+	if err != nil && p.closeErr == nil {
+		p.closeErr = err
+	}
+}
+
+// PartitionedWriter routes each record to a Writer selected by a key
+// derived from the record, so that one stream of records can be fanned out
+// to many documents which share the same format and header.
+//
+// A Writer is held for every open partition and the number of open
+// partitions may be bounded with MaxOpenPartitions. It is not safe for
+// concurrent use.
+type PartitionedWriter struct {
+	keyFunc       func(row []string) (string, error)
+	factory       func(key string, reopen bool) (io.WriteCloser, error)
+	writerOpts    []WriterOption
+	headerOpts    []WriteHeaderOption
+	partitions    map[string]*partition
+	order         []*partition
+	lru           list.List
+	maxOpen       int
+	headerWritten bool
+	closed        bool
+}
+
+// NewPartitionedWriter creates a PartitionedWriter which calls keyFunc with
+// every record to select its partition.
+//
+// factory is called to open the destination of a partition the first time
+// a record is routed to its key with reopen set to false. Should the
+// destination have been closed to honor MaxOpenPartitions, factory is
+// called again with reopen set to true and must then return a destination
+// which appends to what was previously written, such as a file opened with
+// os.O_APPEND.
+func NewPartitionedWriter(keyFunc func(row []string) (string, error), factory func(key string, reopen bool) (io.WriteCloser, error), options ...PartitionedWriterOption) (*PartitionedWriter, error) {
+	var cfg pwCfg
+	for _, f := range options {
+		f(&cfg)
+	}
+
+	if keyFunc == nil {
+		return nil, errors.Join(ErrBadConfig, errors.New("nil key function"))
+	}
+
+	if factory == nil {
+		return nil, errors.Join(ErrBadConfig, errors.New("nil partition factory"))
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, errors.Join(ErrBadConfig, err)
+	}
+
+	writerOpts := cfg.writerOpts[:len(cfg.writerOpts):len(cfg.writerOpts)]
+
+	// validate the shared writer options once up front
+	if _, err := NewWriter(append(writerOpts, WriterOpts().Writer(io.Discard))...); err != nil {
+		return nil, err
+	}
+
+	return &PartitionedWriter{
+		keyFunc:    keyFunc,
+		factory:    factory,
+		writerOpts: writerOpts,
+		partitions: map[string]*partition{},
+		maxOpen:    cfg.maxOpenPartitions,
+	}, nil
+}
+
+// WriteHeader sets the header written at the start of every partition when
+// it is first created, see Writer.WriteHeader.
+//
+// It must be called before any record is written. The options are validated
+// immediately but nothing is written until a record is routed to a
+// partition.
+func (pw *PartitionedWriter) WriteHeader(options ...WriteHeaderOption) error {
+	if pw.closed {
+		return ErrWriterClosed
+	}
+
+	if pw.headerWritten || len(pw.partitions) > 0 {
+		return ErrHeaderWritten
+	}
+	pw.headerWritten = true
+
+	w, err := NewWriter(append(pw.writerOpts, WriterOpts().Writer(io.Discard))...)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.WriteHeader(options...); err != nil {
+		return err
+	}
+
+	pw.headerOpts = options
+	return nil
+}
+
+// WriteRow writes the record to the partition selected by the key function,
+// see Writer.WriteRow.
+//
+// An error returned by the key function is returned as is and nothing is
+// written. An error writing to one partition does not affect any other.
+func (pw *PartitionedWriter) WriteRow(row ...string) (int, error) {
+	if pw.closed {
+		return 0, ErrWriterClosed
+	}
+
+	p, err := pw.partition(row)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.w.WriteRow(row...)
+}
+
+// WriteRowContext is like WriteRow but first checks that ctx is not done,
+// see Writer.WriteRowContext.
+func (pw *PartitionedWriter) WriteRowContext(ctx context.Context, row ...string) (int, error) {
+	if pw.closed {
+		return 0, ErrWriterClosed
+	}
+
+	p, err := pw.partition(row)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.w.WriteRowContext(ctx, row...)
+}
+
+// partition returns the open partition for the row, creating it when the
+// key has not been seen before.
+func (pw *PartitionedWriter) partition(row []string) (*partition, error) {
+	key, err := pw.keyFunc(row)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := pw.partitions[key]
+	if !ok {
+		p = &partition{key: key}
+		pw.partitions[key] = p
+		pw.order = append(pw.order, p)
+	}
+
+	if p.w != nil {
+		pw.lru.MoveToFront(p.elem)
+		return p, nil
+	}
+
+	if err := pw.open(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// open opens the destination of a partition and creates its Writer, first
+// closing the least recently written partition should the open partition
+// bound be reached.
+//
+// The header is only written when the destination is first created.
+func (pw *PartitionedWriter) open(p *partition) error {
+	if pw.maxOpen > 0 && pw.lru.Len() >= pw.maxOpen {
+		pw.lru.Back().Value.(*partition).close(&pw.lru)
+	}
+
+	wc, err := pw.factory(p.key, p.opened)
+	if err != nil {
+		return err
+	}
+
+	w, err := NewWriter(append(pw.writerOpts, WriterOpts().Writer(wc))...)
+	if err != nil {
+		return errors.Join(err, wc.Close())
+	}
+
+	reopen := p.opened
+	p.opened = true
+	p.w = w
+	p.wc = wc
+	p.elem = pw.lru.PushFront(p)
+
+	if !reopen && pw.headerOpts != nil {
+		if _, err := w.WriteHeader(pw.headerOpts...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NumPartitions returns the number of distinct keys records have been
+// routed to.
+func (pw *PartitionedWriter) NumPartitions() int {
+	return len(pw.order)
+}
+
+// Close closes the Writer and destination of every open partition in the
+// order the partitions were created.
+//
+// Errors from all partitions are joined together, each wrapped in a
+// PartitionError.
+func (pw *PartitionedWriter) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+
+	var errs []error
+	for _, p := range pw.order {
+		p.close(&pw.lru)
+
+		if p.closeErr != nil {
+			errs = append(errs, PartitionError{p.key, p.closeErr})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,317 @@
+package csv
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+type rwCfg struct {
+	writerOpts     []WriterOption
+	maxPartBytes   int64
+	maxPartRecords uint64
+	maxBytesSet    bool
+	maxRecordsSet  bool
+}
+
+type RotatingWriterOption func(*rwCfg)
+
+// RotatingWriterOptions should never be instantiated manually
+//
+// Instead call RotatingWriterOpts()
+//
+// This is only exported to allow godocs to discover the exported methods.
+//
+// RotatingWriterOptions will never have exported members and the zero value is not
+// part of the semver guarantee. Instantiate it incorrectly at your own peril.
+//
+// Calling the function is a nop that is compiled away anyways, you will not
+// optimize anything at all. Use RotatingWriterOpts()!
+type RotatingWriterOptions struct{}
+
+func RotatingWriterOpts() RotatingWriterOptions {
+	return RotatingWriterOptions{}
+}
+
+// MaxPartBytes starts a new part before writing a record which would make
+// the current part larger than n bytes.
+//
+// Records are never split, so a part which contains only the header and a
+// single record may still exceed the limit.
+func (RotatingWriterOptions) MaxPartBytes(n int64) RotatingWriterOption {
+	return func(cfg *rwCfg) {
+		cfg.maxPartBytes = n
+		cfg.maxBytesSet = true
+	}
+}
+
+// MaxPartRecords starts a new part once the current part contains n
+// records, not counting the header row.
+func (RotatingWriterOptions) MaxPartRecords(n uint64) RotatingWriterOption {
+	return func(cfg *rwCfg) {
+		cfg.maxPartRecords = n
+		cfg.maxRecordsSet = true
+	}
+}
+
+// WriterOptions configures the Writer used to format every part. The Writer
+// option must not be specified.
+func (RotatingWriterOptions) WriterOptions(options ...WriterOption) RotatingWriterOption {
+	return func(cfg *rwCfg) {
+		cfg.writerOpts = append(cfg.writerOpts, options...)
+	}
+}
+
+func (cfg *rwCfg) validate() error {
+	if cfg.maxBytesSet && cfg.maxPartBytes <= 0 {
+		return errors.New("max part bytes must be greater than zero")
+	}
+
+	if cfg.maxRecordsSet && cfg.maxPartRecords == 0 {
+		return errors.New("max part records must be greater than zero")
+	}
+
+	if !cfg.maxBytesSet && !cfg.maxRecordsSet {
+		return errors.New("max part bytes or max part records must be specified")
+	}
+
+	var wc wCfg
+	for _, f := range cfg.writerOpts {
+		f(&wc)
+	}
+
+	if wc.writer != nil {
+		return errors.New("a writer cannot be specified when rotating parts")
+	}
+
+	if wc.compression != CompressionNone {
+		return errors.New("compression cannot be used when rotating parts")
+	}
+
+	return nil
+}
+
+// partWriter sits between the Writer and the current part and opens a new
+// part whenever a record would exceed the configured limits.
+//
+// It relies on the Writer handing each record to the underlying writer in a
+// single call so that records are never split across parts.
+type partWriter struct {
+	factory   func(part int) (io.WriteCloser, error)
+	dst       io.WriteCloser
+	headerBuf []byte
+	// replayedBytes is the number of header bytes written to every part
+	// after the first
+	replayedBytes  uint64
+	maxBytes       int64
+	maxRecords     uint64
+	partBytes      int64
+	partRecords    uint64
+	part           int
+	started        bool
+	inHeader       bool
+	clearHeaderBuf bool
+}
+
+func (pw *partWriter) Write(p []byte) (int, error) {
+	if pw.dst == nil {
+		if err := pw.open(); err != nil {
+			return 0, err
+		}
+	} else if !pw.inHeader && pw.partRecords > 0 && ((pw.maxRecords > 0 && pw.partRecords >= pw.maxRecords) || (pw.maxBytes > 0 && pw.partBytes+int64(len(p)) > pw.maxBytes)) {
+		if err := pw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := pw.dst.Write(p)
+	pw.partBytes += int64(n)
+
+	if pw.inHeader {
+		if pw.clearHeaderBuf {
+			appendAndClear(&pw.headerBuf, p[:n])
+		} else {
+			pw.headerBuf = append(pw.headerBuf, p[:n]...)
+		}
+	} else if err == nil {
+		pw.partRecords++
+	}
+
+	return n, err
+}
+
+// open creates the next part and writes the header bytes captured from the
+// first part to it.
+func (pw *partWriter) open() error {
+	pw.started = true
+
+	dst, err := pw.factory(pw.part)
+	if err != nil {
+		return err
+	}
+	pw.dst = dst
+	pw.partBytes = 0
+	pw.partRecords = 0
+
+	if len(pw.headerBuf) == 0 {
+		return nil
+	}
+
+	n, err := dst.Write(pw.headerBuf)
+	pw.partBytes += int64(n)
+	pw.replayedBytes += uint64(n)
+	return err
+}
+
+func (pw *partWriter) rotate() error {
+	dst := pw.dst
+	pw.dst = nil
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	pw.part++
+	return pw.open()
+}
+
+// RotatingWriter writes a stream of records as a sequence of standalone
+// parts, starting a new part whenever the configured size or record limits
+// would be exceeded.
+//
+// Every part begins with the same byte order marker, comment lines, and
+// header row written by WriteHeader, and records are never split across
+// parts. It is not safe for concurrent use.
+type RotatingWriter struct {
+	w  *Writer
+	pw partWriter
+}
+
+// NewRotatingWriter creates a RotatingWriter which calls factory to create
+// each part, starting with part zero, only once there is something to
+// write to it.
+//
+// Each part is closed before the next one is created and the final part is
+// closed by Close.
+func NewRotatingWriter(factory func(part int) (io.WriteCloser, error), options ...RotatingWriterOption) (*RotatingWriter, error) {
+	var cfg rwCfg
+	for _, f := range options {
+		f(&cfg)
+	}
+
+	if factory == nil {
+		return nil, errors.Join(ErrBadConfig, errors.New("nil part factory"))
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, errors.Join(ErrBadConfig, err)
+	}
+
+	rw := &RotatingWriter{
+		pw: partWriter{
+			factory:    factory,
+			maxBytes:   cfg.maxPartBytes,
+			maxRecords: cfg.maxPartRecords,
+		},
+	}
+
+	w, err := NewWriter(append(cfg.writerOpts[:len(cfg.writerOpts):len(cfg.writerOpts)], WriterOpts().Writer(&rw.pw))...)
+	if err != nil {
+		return nil, err
+	}
+	rw.w = w
+	rw.pw.clearHeaderBuf = (w.bitFlags & wFlagClearMemoryAfterFree) != 0
+
+	return rw, nil
+}
+
+// WriteHeader writes the header to the first part and records it so it can
+// be repeated at the start of every following part, see Writer.WriteHeader.
+func (rw *RotatingWriter) WriteHeader(options ...WriteHeaderOption) (int, error) {
+	rw.pw.inHeader = true
+	defer func() {
+		rw.pw.inHeader = false
+	}()
+
+	return rw.w.WriteHeader(options...)
+}
+
+// WriteRow writes a record to the current part, see Writer.WriteRow.
+func (rw *RotatingWriter) WriteRow(row ...string) (int, error) {
+	return rw.w.WriteRow(row...)
+}
+
+// WriteRowContext writes a record to the current part, see
+// Writer.WriteRowContext.
+func (rw *RotatingWriter) WriteRowContext(ctx context.Context, row ...string) (int, error) {
+	return rw.w.WriteRowContext(ctx, row...)
+}
+
+// WriteFieldRow writes a record to the current part, see
+// Writer.WriteFieldRow.
+func (rw *RotatingWriter) WriteFieldRow(row ...FieldWriter) (int, error) {
+	return rw.w.WriteFieldRow(row...)
+}
+
+// WriteFieldRowBorrowed writes a record to the current part, see
+// Writer.WriteFieldRowBorrowed.
+func (rw *RotatingWriter) WriteFieldRowBorrowed(row []FieldWriter) (int, error) {
+	return rw.w.WriteFieldRowBorrowed(row)
+}
+
+// NewRecord returns a RecordWriter which writes to the current part, see
+// Writer.NewRecord.
+func (rw *RotatingWriter) NewRecord() (*RecordWriter, error) {
+	return rw.w.NewRecord()
+}
+
+// MustNewRecord is like NewRecord but panics on error, see
+// Writer.MustNewRecord.
+func (rw *RotatingWriter) MustNewRecord() *RecordWriter {
+	return rw.w.MustNewRecord()
+}
+
+// Part returns the zero based index of the current part.
+func (rw *RotatingWriter) Part() int {
+	return rw.pw.part
+}
+
+// Stats returns a summary of the work performed across all parts so far.
+//
+// Bytes includes the header bytes repeated at the start of every part
+// after the first.
+func (rw *RotatingWriter) Stats() WriterStats {
+	s := rw.w.Stats()
+	s.Bytes += rw.pw.replayedBytes
+	return s
+}
+
+// Close closes the Writer and the current part. A part is created first
+// should nothing have been written so that at least one part always
+// exists.
+func (rw *RotatingWriter) Close() error {
+	if (rw.w.bitFlags & wFlagClosed) != 0 {
+		return nil
+	}
+
+	err := rw.w.Close()
+
+	pw := &rw.pw
+	if !pw.started {
+		if openErr := pw.open(); openErr != nil {
+			err = errors.Join(err, openErr)
+		}
+	}
+
+	if pw.dst != nil {
+		if closeErr := pw.dst.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+		pw.dst = nil
+	}
+
+	if pw.clearHeaderBuf {
+		clear(pw.headerBuf[:cap(pw.headerBuf)])
+	}
+
+	return err
+}
@@ -0,0 +1,52 @@
+package csv
+
+// WriterStats is a point in time summary of the work performed by a Writer.
+//
+// Every value is tracked unconditionally at the cost of a few increments per
+// record, so it is always safe to call Writer.Stats.
+type WriterStats struct {
+	// Records is the number of records successfully written, including
+	// any header row written by WriteHeader.
+	Records uint64
+	// Bytes is the number of bytes written to the underlying writer,
+	// including byte order markers and comment lines. When the Compress
+	// option is enabled it is the number of bytes before compression.
+	Bytes uint64
+	// QuotedFields is the number of fields within successfully written
+	// records that were wrapped in quotes.
+	QuotedFields uint64
+	// EscapedFields is the number of fields within successfully written
+	// records that contained at least one quote or escape rune which had
+	// to be escaped.
+	EscapedFields uint64
+	// MaxRecordBytes is the largest number of bytes written for a single
+	// record including the record separator.
+	MaxRecordBytes int
+}
+
+// trackRecord updates the writer statistics after the record buffer has
+// been handed to the underlying writer.
+func (w *Writer) trackRecord(n int, err error) {
+	w.stats.Bytes += uint64(n)
+
+	if err != nil {
+		return
+	}
+
+	w.stats.Records++
+	if w.gz != nil {
+		w.gz.endRecord()
+	}
+	w.stats.QuotedFields += uint64(w.recQuotedFields)
+	w.stats.EscapedFields += uint64(w.recEscapedFields)
+	if n > w.stats.MaxRecordBytes {
+		w.stats.MaxRecordBytes = n
+	}
+}
+
+// Stats returns a summary of the work performed by the Writer so far.
+//
+// It may be called at any time, including after Close.
+func (w *Writer) Stats() WriterStats {
+	return w.stats
+}